@@ -0,0 +1,41 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeviceClassPolicySpec defines the namespaces allowed to use a device-class
+type DeviceClassPolicySpec struct {
+	// DeviceClass is the name of the device-class restricted by this policy.
+	// The default device-class is specified by "00default".
+	DeviceClass string `json:"deviceClass"`
+	// Namespaces is the list of the names of namespaces allowed to use the device-class.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector selects namespaces allowed to use the device-class.
+	// An empty selector selects all namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// DeviceClassPolicy is the Schema for the deviceclasspolicies API
+type DeviceClassPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DeviceClassPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DeviceClassPolicyList contains a list of DeviceClassPolicy
+type DeviceClassPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceClassPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeviceClassPolicy{}, &DeviceClassPolicyList{})
+}
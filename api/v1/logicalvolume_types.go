@@ -28,6 +28,7 @@ type LogicalVolumeStatus struct {
 	Code        codes.Code         `json:"code,omitempty"`
 	Message     string             `json:"message,omitempty"`
 	CurrentSize *resource.Quantity `json:"currentSize,omitempty"`
+	WipedSize   *resource.Quantity `json:"wipedSize,omitempty"`
 }
 
 //+kubebuilder:object:root=true
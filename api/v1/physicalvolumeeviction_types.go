@@ -0,0 +1,62 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PhysicalVolumeEvictionSpec defines the physical volume to be evacuated
+type PhysicalVolumeEvictionSpec struct {
+	// NodeName is the name of the node of the physical volume.
+	NodeName string `json:"nodeName"`
+	// DeviceClass is the device-class of the volume group of the physical volume.
+	DeviceClass string `json:"deviceClass,omitempty"`
+	// PhysicalVolume is the device path of the physical volume, e.g. /dev/sdb.
+	PhysicalVolume string `json:"physicalVolume"`
+	// Remove removes the physical volume from the volume group after all extents are moved.
+	Remove bool `json:"remove,omitempty"`
+}
+
+// EvictionPhase is the phase of a PhysicalVolumeEviction.
+type EvictionPhase string
+
+// Phases of PhysicalVolumeEviction
+const (
+	EvictionPhaseRunning   EvictionPhase = "Running"
+	EvictionPhaseSucceeded EvictionPhase = "Succeeded"
+	EvictionPhaseFailed    EvictionPhase = "Failed"
+)
+
+// PhysicalVolumeEvictionStatus defines the observed state of PhysicalVolumeEviction
+type PhysicalVolumeEvictionStatus struct {
+	Phase     EvictionPhase      `json:"phase,omitempty"`
+	Message   string             `json:"message,omitempty"`
+	MovedSize *resource.Quantity `json:"movedSize,omitempty"`
+	TotalSize *resource.Quantity `json:"totalSize,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+
+// PhysicalVolumeEviction is the Schema for the physicalvolumeevictions API
+type PhysicalVolumeEviction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PhysicalVolumeEvictionSpec   `json:"spec,omitempty"`
+	Status PhysicalVolumeEvictionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PhysicalVolumeEvictionList contains a list of PhysicalVolumeEviction
+type PhysicalVolumeEvictionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PhysicalVolumeEviction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PhysicalVolumeEviction{}, &PhysicalVolumeEvictionList{})
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageQuotaSpec defines the limits of the storage capacity in a namespace
+type StorageQuotaSpec struct {
+	// Hard is the limit of the total size of TopoLVM volumes per device-class.
+	// The default device-class is keyed by "00default".
+	Hard map[string]resource.Quantity `json:"hard"`
+}
+
+// StorageQuotaStatus defines the observed usage of the storage capacity in a namespace
+type StorageQuotaStatus struct {
+	// Used is the total size of TopoLVM volumes per device-class.
+	Used map[string]resource.Quantity `json:"used,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// StorageQuota is the Schema for the storagequotas API
+type StorageQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StorageQuotaSpec   `json:"spec,omitempty"`
+	Status StorageQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// StorageQuotaList contains a list of StorageQuota
+type StorageQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StorageQuota{}, &StorageQuotaList{})
+}
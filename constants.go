@@ -1,10 +1,26 @@
 package topolvm
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
 
 // CapacityKeyPrefix is the key prefix of Node annotation that represents VG free space.
 const CapacityKeyPrefix = "capacity.topolvm.cybozu.com/"
 
+// CapacityUpdatedAtKey is the key of Node annotation that records when the capacity annotations were last changed.
+// The value is in RFC 3339 format.
+const CapacityUpdatedAtKey = "topolvm.cybozu.com/capacity-updated-at"
+
+// DrainingKeyPrefix is the key prefix of Node annotation that marks a device-class as draining.
+// New volumes are not placed on a draining device-class while existing volumes keep working.
+const DrainingKeyPrefix = "draining.topolvm.cybozu.com/"
+
+// SpareKeyPrefix is the key prefix of Node annotation that overrides the spare capacity of a device-class.
+// The value is a percentage of the volume group size like "5%", or a quantity like "100Gi".
+const SpareKeyPrefix = "spare.topolvm.cybozu.com/"
+
 // CapacityResource is the resource name of topolvm capacity.
 const CapacityResource = corev1.ResourceName("topolvm.cybozu.com/capacity")
 
@@ -20,6 +36,45 @@ const DeviceClassKey = "topolvm.cybozu.com/device-class"
 // ResizeRequestedAtKey is the key of LogicalVolume that represents the timestamp of the resize request.
 const ResizeRequestedAtKey = "topolvm.cybozu.com/resize-requested-at"
 
+// RestoreFromKey is the key of LogicalVolume annotation that specifies the UID of a removed LogicalVolume to restore from the trash.
+const RestoreFromKey = "topolvm.cybozu.com/restore-from"
+
+// ImportFromKey is the key of LogicalVolume annotation that specifies the name of an existing LV to import.
+const ImportFromKey = "topolvm.cybozu.com/import-from"
+
+// ImportFsTypeKey is the key of LogicalVolume annotation that specifies the filesystem type of an imported LV.
+const ImportFsTypeKey = "topolvm.cybozu.com/import-fstype"
+
+// RecoverFromKey is the key of LogicalVolume annotation that specifies the name of an existing LV
+// that a re-created LogicalVolume takes over.
+const RecoverFromKey = "topolvm.cybozu.com/recover-from"
+
+// NodeDeletionPolicyKey is the key of StorageClass annotation that specifies how volumes are handled when their node is deleted.
+const NodeDeletionPolicyKey = "topolvm.cybozu.com/node-deletion-policy"
+
+// NodeDeletionGracePeriodKey is the key of StorageClass annotation that specifies how long volumes are retained
+// before cleanup under the "wait-for-grace-period" policy.
+const NodeDeletionGracePeriodKey = "topolvm.cybozu.com/node-deletion-grace-period"
+
+// Node deletion policies
+const (
+	// NodeDeletionPolicyDelete deletes PVCs and LogicalVolumes on the deleted node.
+	NodeDeletionPolicyDelete = "delete"
+	// NodeDeletionPolicyRetain keeps PVCs and LogicalVolumes and marks their PVs as lost.
+	NodeDeletionPolicyRetain = "retain"
+	// NodeDeletionPolicyWaitForGracePeriod marks PVs as lost and deletes them if the node does not come back within the grace period.
+	NodeDeletionPolicyWaitForGracePeriod = "wait-for-grace-period"
+)
+
+// DefaultNodeDeletionGracePeriod is the default grace period for the "wait-for-grace-period" policy.
+const DefaultNodeDeletionGracePeriod = time.Hour
+
+// LostNodeKey is the key of PersistentVolume annotation that records the name of the deleted node of the volume.
+const LostNodeKey = "topolvm.cybozu.com/lost-node"
+
+// CleanupAfterKey is the key of PersistentVolume annotation that records when a lost volume will be cleaned up.
+const CleanupAfterKey = "topolvm.cybozu.com/cleanup-after"
+
 // LogicalVolumeFinalizer is the name of LogicalVolume finalizer
 const LogicalVolumeFinalizer = "topolvm.cybozu.com/logicalvolume"
 
@@ -57,3 +112,10 @@ const CreatedbyLabelKey = "app.kubernetes.io/created-by"
 
 // Label value that indicates The controller/user who created this resource
 const CreatedbyLabelValue = "topolvm-controller"
+
+// OwnedTag is the LVM tag added to logical volumes created or imported by TopoLVM.
+const OwnedTag = "topolvm.cybozu.com/owned"
+
+// TrashedAtTagPrefix is the prefix of the LVM tag that records when a logical volume was moved into the trash.
+// The rest of the tag is the UNIX time in seconds.
+const TrashedAtTagPrefix = "topolvm.cybozu.com/trashed-at="
@@ -11,4 +11,10 @@ const (
 	// be dynamically provisioned. Its value is the name of the selected node.
 	// https://github.com/kubernetes/kubernetes/blob/9bae1bc56804db4905abebcd408e0f02e199ab93/pkg/controller/volume/persistentvolume/util/util.go#L53
 	AnnSelectedNode = "volume.kubernetes.io/selected-node"
+
+	// volumeLostReason is the reason of events for volumes on deleted nodes.
+	volumeLostReason = "NodeLost"
+
+	// invalidNodeDeletionPolicyReason is the reason of events for nodes that cannot be finalized due to an invalid policy.
+	invalidNodeDeletionPolicyReason = "InvalidNodeDeletionPolicy"
 )
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const defaultImportFsType = "ext4"
+
+// ImportedVolumeReconciler creates a PersistentVolume for an imported LogicalVolume
+type ImportedVolumeReconciler struct {
+	client.Client
+}
+
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;create
+
+// Reconcile creates a PersistentVolume bound to TopoLVM once the LV has been imported on the node.
+func (r *ImportedVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	lv := &topolvmv1.LogicalVolume{}
+	err := r.Get(ctx, req.NamespacedName, lv)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, err
+	}
+
+	if lv.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	if _, ok := lv.Annotations[topolvm.ImportFromKey]; !ok {
+		return ctrl.Result{}, nil
+	}
+	// topolvm-node has not imported the LV yet, or failed to import it.
+	if lv.Status.VolumeID == "" || lv.Status.Code != codes.OK || lv.Status.CurrentSize == nil {
+		return ctrl.Result{}, nil
+	}
+
+	pv := &corev1.PersistentVolume{}
+	err = r.Get(ctx, client.ObjectKey{Name: lv.Name}, pv)
+	switch {
+	case err == nil:
+		return ctrl.Result{}, nil
+	case apierrors.IsNotFound(err):
+	default:
+		log.Error(err, "unable to fetch PersistentVolume", "name", lv.Name)
+		return ctrl.Result{}, err
+	}
+
+	pv = newImportedPersistentVolume(lv)
+	if err := r.Create(ctx, pv); err != nil {
+		log.Error(err, "failed to create PersistentVolume", "name", pv.Name)
+		return ctrl.Result{}, err
+	}
+
+	log.Info("created PersistentVolume for imported LV", "name", pv.Name, "volume_id", lv.Status.VolumeID, "node", lv.Spec.NodeName)
+	return ctrl.Result{}, nil
+}
+
+func newImportedPersistentVolume(lv *topolvmv1.LogicalVolume) *corev1.PersistentVolume {
+	fsType := lv.Annotations[topolvm.ImportFsTypeKey]
+	if fsType == "" {
+		fsType = defaultImportFsType
+	}
+	volumeMode := corev1.PersistentVolumeFilesystem
+
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: lv.Name,
+			Annotations: map[string]string{
+				"pv.kubernetes.io/provisioned-by": topolvm.PluginName,
+			},
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: *lv.Status.CurrentSize,
+			},
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			VolumeMode:                    &volumeMode,
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       topolvm.PluginName,
+					VolumeHandle: lv.Status.VolumeID,
+					FSType:       fsType,
+				},
+			},
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{
+									Key:      topolvm.TopologyNodeKey,
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{lv.Spec.NodeName},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ImportedVolumeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isImported := func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[topolvm.ImportFromKey]
+		return ok
+	}
+	pred := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isImported(e.Object) },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isImported(e.ObjectNew) },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		WithEventFilter(pred).
+		For(&topolvmv1.LogicalVolume{}).
+		Complete(r)
+}
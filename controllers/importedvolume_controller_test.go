@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ImportedVolumeReconciler", func() {
+	ctx := context.Background()
+
+	createImportedLV := func(name string, annotations map[string]string, status topolvmv1.LogicalVolumeStatus) *topolvmv1.LogicalVolume {
+		lv := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:        name,
+				NodeName:    "node1",
+				Size:        resource.MustParse("2Gi"),
+				DeviceClass: topolvm.DefaultDeviceClassName,
+			},
+		}
+		Expect(k8sClient.Create(ctx, lv)).To(Succeed())
+		lv.Status = status
+		Expect(k8sClient.Status().Update(ctx, lv)).To(Succeed())
+		return lv
+	}
+	reconcile := func(lv *topolvmv1.LogicalVolume) {
+		r := &ImportedVolumeReconciler{Client: k8sClient}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(lv)})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	imported := func() topolvmv1.LogicalVolumeStatus {
+		size := resource.MustParse("2Gi")
+		return topolvmv1.LogicalVolumeStatus{VolumeID: "volume-id", Code: codes.OK, CurrentSize: &size}
+	}
+
+	It("should create a PersistentVolume for an imported LogicalVolume", func() {
+		lv := createImportedLV("imported1", map[string]string{
+			topolvm.ImportFromKey:   "data",
+			topolvm.ImportFsTypeKey: "xfs",
+		}, imported())
+		reconcile(lv)
+
+		pv := &corev1.PersistentVolume{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: lv.Name}, pv)).To(Succeed())
+		Expect(pv.Spec.CSI).NotTo(BeNil())
+		Expect(pv.Spec.CSI.Driver).To(Equal(topolvm.PluginName))
+		Expect(pv.Spec.CSI.VolumeHandle).To(Equal("volume-id"))
+		Expect(pv.Spec.CSI.FSType).To(Equal("xfs"))
+		Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		Expect(pv.Spec.StorageClassName).To(BeEmpty())
+		capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+		Expect(capacity.Value()).To(Equal(int64(2 << 30)))
+		terms := pv.Spec.NodeAffinity.Required.NodeSelectorTerms
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].MatchExpressions).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key:      topolvm.TopologyNodeKey,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"node1"},
+		}))
+
+		By("reconciling again")
+		reconcile(lv)
+		pv2 := &corev1.PersistentVolume{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: lv.Name}, pv2)).To(Succeed())
+		Expect(pv2.UID).To(Equal(pv.UID))
+	})
+
+	It("should default the filesystem type to ext4", func() {
+		lv := createImportedLV("imported2", map[string]string{
+			topolvm.ImportFromKey: "data",
+		}, imported())
+		reconcile(lv)
+
+		pv := &corev1.PersistentVolume{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: lv.Name}, pv)).To(Succeed())
+		Expect(pv.Spec.CSI.FSType).To(Equal("ext4"))
+	})
+
+	It("should not create a PersistentVolume until the LV is imported", func() {
+		lv := createImportedLV("imported3", map[string]string{
+			topolvm.ImportFromKey: "data",
+		}, topolvmv1.LogicalVolumeStatus{Code: codes.InvalidArgument, Message: "size mismatch"})
+		reconcile(lv)
+
+		pv := &corev1.PersistentVolume{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Name: lv.Name}, pv)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should ignore LogicalVolumes that are not imported", func() {
+		lv := createImportedLV("imported4", nil, imported())
+		reconcile(lv)
+
+		pv := &corev1.PersistentVolume{}
+		err := k8sClient.Get(ctx, client.ObjectKey{Name: lv.Name}, pv)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/topolvm/topolvm"
@@ -15,27 +17,34 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const wipeStatusUpdateInterval = 10 * time.Second
+
 // LogicalVolumeReconciler reconciles a LogicalVolume object
 type LogicalVolumeReconciler struct {
 	client.Client
 	nodeName  string
 	vgService proto.VGServiceClient
 	lvService proto.LVServiceClient
+	lvEvents  <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=logicalvolumes,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=logicalvolumes/status,verbs=get;update;patch
 
 // NewLogicalVolumeReconciler returns LogicalVolumeReconciler with creating lvService and vgService.
-func NewLogicalVolumeReconciler(client client.Client, nodeName string, conn *grpc.ClientConn) *LogicalVolumeReconciler {
+// If lvEvents is not nil, LogicalVolumes received from it are also reconciled.
+func NewLogicalVolumeReconciler(client client.Client, nodeName string, conn *grpc.ClientConn, lvEvents <-chan event.GenericEvent) *LogicalVolumeReconciler {
 	return &LogicalVolumeReconciler{
 		Client:    client,
 		nodeName:  nodeName,
 		vgService: proto.NewVGServiceClient(conn),
 		lvService: proto.NewLVServiceClient(conn),
+		lvEvents:  lvEvents,
 	}
 }
 
@@ -91,7 +100,16 @@ func (r *LogicalVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			return ctrl.Result{}, err
 		}
 
-		err := r.expandLV(ctx, log, lv)
+		found, err := r.checkLV(ctx, log, lv)
+		if err != nil {
+			log.Error(err, "failed to check LV", "name", lv.Name)
+			return ctrl.Result{}, err
+		}
+		if !found {
+			return ctrl.Result{}, nil
+		}
+
+		err = r.expandLV(ctx, log, lv)
 		if err != nil {
 			log.Error(err, "failed to expand LV", "name", lv.Name)
 		}
@@ -122,26 +140,32 @@ func (r *LogicalVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *LogicalVolumeReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&topolvmv1.LogicalVolume{}).
-		WithEventFilter(&logicalVolumeFilter{r.nodeName}).
-		Complete(r)
+		WithEventFilter(&logicalVolumeFilter{r.nodeName})
+	if r.lvEvents != nil {
+		b = b.Watches(&source.Channel{Source: r.lvEvents}, &handler.EnqueueRequestForObject{})
+	}
+	return b.Complete(r)
 }
 
 func (r *LogicalVolumeReconciler) removeLVIfExists(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
 	// Finalizer's process ( RemoveLV then removeString ) is not atomic,
 	// so checking existence of LV to ensure its idempotence
-	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
+	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass, Name: VolumeName(lv)})
 	if err != nil {
 		log.Error(err, "failed to list LV")
 		return err
 	}
 
 	for _, v := range respList.Volumes {
-		if v.Name != string(lv.UID) {
+		if v.Name != VolumeName(lv) {
 			continue
 		}
-		_, err := r.lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: string(lv.UID), DeviceClass: lv.Spec.DeviceClass})
+		if err := r.wipeLV(ctx, log, lv); err != nil {
+			return err
+		}
+		_, err := r.lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: VolumeName(lv), DeviceClass: lv.Spec.DeviceClass})
 		if err != nil {
 			log.Error(err, "failed to remove LV", "name", lv.Name, "uid", lv.UID)
 			return err
@@ -153,15 +177,53 @@ func (r *LogicalVolumeReconciler) removeLVIfExists(ctx context.Context, log logr
 	return nil
 }
 
+// wipeLV wipes the LV according to the wipe policy of its device-class.
+// The progress is recorded in lv.Status.WipedSize at most once per wipeStatusUpdateInterval.
+func (r *LogicalVolumeReconciler) wipeLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
+	stream, err := r.lvService.WipeLV(ctx, &proto.WipeLVRequest{Name: VolumeName(lv), DeviceClass: lv.Spec.DeviceClass})
+	if err != nil {
+		log.Error(err, "failed to wipe LV", "name", lv.Name, "uid", lv.UID)
+		return err
+	}
+
+	var lastUpdate time.Time
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Error(err, "failed to wipe LV", "name", lv.Name, "uid", lv.UID)
+			return err
+		}
+
+		if resp.WipedBytes < resp.TotalBytes && time.Since(lastUpdate) < wipeStatusUpdateInterval {
+			continue
+		}
+		lastUpdate = time.Now()
+		lv.Status.WipedSize = resource.NewQuantity(int64(resp.WipedBytes), resource.BinarySI)
+		if err := r.Status().Update(ctx, lv); err != nil {
+			// the progress is informational, so wiping continues
+			log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		}
+	}
+
+	if lv.Status.WipedSize != nil {
+		log.Info("wiped LV", "name", lv.Name, "uid", lv.UID, "status.wipedSize", lv.Status.WipedSize.Value())
+	}
+	return nil
+}
+
+// volumeExists looks up only the LV of lv, so it does not scan the whole volume group.
 func (r *LogicalVolumeReconciler) volumeExists(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (bool, error) {
-	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
+	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass, Name: VolumeName(lv)})
 	if err != nil {
 		log.Error(err, "failed to get list of LV")
 		return false, err
 	}
 
 	for _, v := range respList.Volumes {
-		if v.Name != string(lv.UID) {
+		if v.Name != VolumeName(lv) {
 			continue
 		}
 		return true, nil
@@ -169,6 +231,43 @@ func (r *LogicalVolumeReconciler) volumeExists(ctx context.Context, log logr.Log
 	return false, nil
 }
 
+// VolumeName returns the name of the LVM logical volume for lv.
+// It differs from the UID of lv when lv has been re-created for an existing LV.
+func VolumeName(lv *topolvmv1.LogicalVolume) string {
+	if lv.Status.VolumeID != "" {
+		return lv.Status.VolumeID
+	}
+	return string(lv.UID)
+}
+
+// checkLV records in the status whether the LV still exists in lvmd.
+// A missing LV is reported with codes.NotFound so that the volume condition becomes abnormal.
+func (r *LogicalVolumeReconciler) checkLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (bool, error) {
+	found, err := r.volumeExists(ctx, log, lv)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case !found && lv.Status.Code != codes.NotFound:
+		log.Info("LV is missing", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID)
+		lv.Status.Code = codes.NotFound
+		lv.Status.Message = "LVM logical volume is missing"
+	case found && lv.Status.Code == codes.NotFound:
+		log.Info("LV is found again", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID)
+		lv.Status.Code = codes.OK
+		lv.Status.Message = ""
+	default:
+		return found, nil
+	}
+
+	if err := r.Status().Update(ctx, lv); err != nil {
+		log.Error(err, "failed to update status", "name", lv.Name, "uid", lv.UID)
+		return false, err
+	}
+	return found, nil
+}
+
 func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
 	// When lv.Status.Code is not codes.OK (== 0), CreateLV has already failed.
 	// LogicalVolume CRD will be deleted soon by the controller.
@@ -179,22 +278,114 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 	reqBytes := lv.Spec.Size.Value()
 
 	err := func() error {
-		// In case the controller crashed just after LVM LV creation, LV may already exist.
-		found, err := r.volumeExists(ctx, log, lv)
-		if err != nil {
-			lv.Status.Code = codes.Internal
-			lv.Status.Message = "failed to check volume existence"
+		// RestoreLV and ImportLV rename an LV, so they fail if retried after success.
+		// In case the controller crashed just after that, LV may already exist.
+		// CreateLV returns the existing LV by itself.
+		_, restore := lv.Annotations[topolvm.RestoreFromKey]
+		_, imported := lv.Annotations[topolvm.ImportFromKey]
+		if restore || imported {
+			found, err := r.volumeExists(ctx, log, lv)
+			if err != nil {
+				lv.Status.Code = codes.Internal
+				lv.Status.Message = "failed to check volume existence"
+				return err
+			}
+			if found {
+				log.Info("set volumeID to existing LogicalVolume", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID)
+				lv.Status.VolumeID = string(lv.UID)
+				lv.Status.Code = codes.OK
+				lv.Status.Message = ""
+				return nil
+			}
+		}
+
+		if origUID, ok := lv.Annotations[topolvm.RestoreFromKey]; ok {
+			resp, err := r.lvService.RestoreLV(ctx, &proto.RestoreLVRequest{Name: string(lv.UID), OriginalName: origUID, DeviceClass: lv.Spec.DeviceClass, SizeGb: uint64(reqBytes >> 30)})
+			if err != nil {
+				code, message := extractFromError(err)
+				log.Error(err, message)
+				lv.Status.Code = code
+				lv.Status.Message = message
+				return err
+			}
+
+			log.Info("restored LV from the trash", "name", lv.Name, "uid", lv.UID, "original uid", origUID)
+			lv.Status.VolumeID = resp.Volume.Name
+			lv.Status.CurrentSize = resource.NewQuantity(int64(resp.Volume.SizeGb<<30), resource.BinarySI)
+			lv.Status.Code = codes.OK
+			lv.Status.Message = ""
+			return nil
+		}
+
+		if name, ok := lv.Annotations[topolvm.RecoverFromKey]; ok {
+			respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass, Name: name})
+			if err != nil {
+				code, message := extractFromError(err)
+				log.Error(err, message)
+				lv.Status.Code = code
+				lv.Status.Message = message
+				return err
+			}
+			for _, v := range respList.Volumes {
+				if v.Name != name {
+					continue
+				}
+				// Only LVs created by TopoLVM and not used by other LogicalVolumes can be taken over;
+				// otherwise deleting this LogicalVolume would remove someone else's data.
+				if !containsString(v.Tags, topolvm.OwnedTag) {
+					err = status.Errorf(codes.FailedPrecondition, "LV to recover is not owned by TopoLVM: %s", name)
+					log.Error(err, "failed to recover LV", "name", lv.Name, "uid", lv.UID)
+					lv.Status.Code = codes.FailedPrecondition
+					lv.Status.Message = "LV to recover is not owned by TopoLVM"
+					return err
+				}
+				owner, err := r.volumeOwner(ctx, lv, name)
+				if err != nil {
+					lv.Status.Code = codes.Internal
+					lv.Status.Message = "failed to check LogicalVolumes using the LV"
+					return err
+				}
+				if owner != "" {
+					err = status.Errorf(codes.AlreadyExists, "LV to recover is used by LogicalVolume %s: %s", owner, name)
+					log.Error(err, "failed to recover LV", "name", lv.Name, "uid", lv.UID)
+					lv.Status.Code = codes.AlreadyExists
+					lv.Status.Message = "LV to recover is used by another LogicalVolume"
+					return err
+				}
+
+				log.Info("took over existing LV", "name", lv.Name, "uid", lv.UID, "lv", name)
+				lv.Status.VolumeID = v.Name
+				lv.Status.CurrentSize = resource.NewQuantity(int64(v.SizeGb<<30), resource.BinarySI)
+				lv.Status.Code = codes.OK
+				lv.Status.Message = ""
+				return nil
+			}
+			err = status.Errorf(codes.NotFound, "LV to recover is not found: %s", name)
+			log.Error(err, "failed to recover LV", "name", lv.Name, "uid", lv.UID)
+			lv.Status.Code = codes.NotFound
+			lv.Status.Message = "LV to recover is not found"
 			return err
 		}
-		if found {
-			log.Info("set volumeID to existing LogicalVolume", "name", lv.Name, "uid", lv.UID, "status.volumeID", lv.Status.VolumeID)
-			lv.Status.VolumeID = string(lv.UID)
+
+		if source, ok := lv.Annotations[topolvm.ImportFromKey]; ok {
+			resp, err := r.lvService.ImportLV(ctx, &proto.ImportLVRequest{Name: string(lv.UID), SourceName: source, DeviceClass: lv.Spec.DeviceClass, SizeGb: uint64(reqBytes >> 30), Tags: []string{topolvm.OwnedTag}})
+			if err != nil {
+				code, message := extractFromError(err)
+				log.Error(err, message)
+				lv.Status.Code = code
+				lv.Status.Message = message
+				return err
+			}
+
+			log.Info("imported existing LV", "name", lv.Name, "uid", lv.UID, "source", source)
+			lv.Status.VolumeID = resp.Volume.Name
+			lv.Status.CurrentSize = resource.NewQuantity(int64(resp.Volume.SizeGb<<30), resource.BinarySI)
 			lv.Status.Code = codes.OK
 			lv.Status.Message = ""
 			return nil
 		}
 
-		resp, err := r.lvService.CreateLV(ctx, &proto.CreateLVRequest{Name: string(lv.UID), DeviceClass: lv.Spec.DeviceClass, SizeGb: uint64(reqBytes >> 30)})
+		resp, err := r.lvService.CreateLV(ctx, &proto.CreateLVRequest{Name: string(lv.UID), DeviceClass: lv.Spec.DeviceClass, SizeGb: uint64(reqBytes >> 30), Tags: []string{topolvm.OwnedTag}})
 		if err != nil {
 			code, message := extractFromError(err)
 			log.Error(err, message)
@@ -227,6 +418,24 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 	return nil
 }
 
+// volumeOwner returns the name of another LogicalVolume on the node that uses the LV named volumeName,
+// or an empty string if there is none.
+func (r *LogicalVolumeReconciler) volumeOwner(ctx context.Context, lv *topolvmv1.LogicalVolume, volumeName string) (string, error) {
+	var lvList topolvmv1.LogicalVolumeList
+	if err := r.List(ctx, &lvList); err != nil {
+		return "", err
+	}
+	for _, other := range lvList.Items {
+		if other.UID == lv.UID || other.Spec.NodeName != lv.Spec.NodeName {
+			continue
+		}
+		if other.Status.VolumeID == volumeName || string(other.UID) == volumeName {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
 func (r *LogicalVolumeReconciler) expandLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
 	// lv.Status.CurrentSize is added in v0.4.0 and filled by topolvm-controller when resizing is triggered.
 	// The reconciliation loop of LogicalVolume may call expandLV before resizing is triggered.
@@ -243,7 +452,7 @@ func (r *LogicalVolumeReconciler) expandLV(ctx context.Context, log logr.Logger,
 	reqBytes := lv.Spec.Size.Value()
 
 	err := func() error {
-		_, err := r.lvService.ResizeLV(ctx, &proto.ResizeLVRequest{Name: string(lv.UID), SizeGb: uint64(reqBytes >> 30), DeviceClass: lv.Spec.DeviceClass})
+		_, err := r.lvService.ResizeLV(ctx, &proto.ResizeLVRequest{Name: VolumeName(lv), SizeGb: uint64(reqBytes >> 30), DeviceClass: lv.Spec.DeviceClass})
 		if err != nil {
 			code, message := extractFromError(err)
 			log.Error(err, message)
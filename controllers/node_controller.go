@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/topolvm/topolvm"
@@ -9,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -19,11 +22,15 @@ import (
 // NodeReconciler reconciles a Node object
 type NodeReconciler struct {
 	client.Client
+	Recorder         record.EventRecorder
 	SkipNodeFinalize bool
 }
 
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes/status,verbs=get;patch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 
 // Reconcile finalize Node
@@ -79,22 +86,46 @@ func (r *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	return ctrl.Result{}, nil
 }
 
-func (r *NodeReconciler) targetStorageClasses(ctx context.Context) (map[string]bool, error) {
+func (r *NodeReconciler) targetStorageClasses(ctx context.Context) (map[string]*storagev1.StorageClass, error) {
 	var scl storagev1.StorageClassList
 	if err := r.List(ctx, &scl); err != nil {
 		return nil, err
 	}
 
-	targets := make(map[string]bool)
-	for _, sc := range scl.Items {
+	targets := make(map[string]*storagev1.StorageClass)
+	for i := range scl.Items {
+		sc := &scl.Items[i]
 		if sc.Provisioner != topolvm.PluginName {
 			continue
 		}
-		targets[sc.Name] = true
+		targets[sc.Name] = sc
 	}
 	return targets, nil
 }
 
+// nodeDeletionPolicy returns the node deletion policy and the grace period of the StorageClass.
+// It returns an error for an unknown policy or an invalid grace period so that volumes are never deleted by mistake.
+func nodeDeletionPolicy(sc *storagev1.StorageClass) (string, time.Duration, error) {
+	policy := sc.Annotations[topolvm.NodeDeletionPolicyKey]
+	switch policy {
+	case "":
+		policy = topolvm.NodeDeletionPolicyDelete
+	case topolvm.NodeDeletionPolicyDelete, topolvm.NodeDeletionPolicyRetain, topolvm.NodeDeletionPolicyWaitForGracePeriod:
+	default:
+		return "", 0, fmt.Errorf("unknown %s in StorageClass %s: %s", topolvm.NodeDeletionPolicyKey, sc.Name, policy)
+	}
+
+	grace := topolvm.DefaultNodeDeletionGracePeriod
+	if v, ok := sc.Annotations[topolvm.NodeDeletionGracePeriodKey]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return "", 0, fmt.Errorf("invalid %s in StorageClass %s: %s", topolvm.NodeDeletionGracePeriodKey, sc.Name, v)
+		}
+		grace = d
+	}
+	return policy, grace, nil
+}
+
 func (r *NodeReconciler) doFinalize(ctx context.Context, log logr.Logger, node *corev1.Node) (ctrl.Result, error) {
 	if r.SkipNodeFinalize {
 		log.Info("skipping node finalize")
@@ -114,14 +145,46 @@ func (r *NodeReconciler) doFinalize(ctx context.Context, log logr.Logger, node *
 		return ctrl.Result{}, err
 	}
 
+	// Check the policies before handling any PVC so that an invalid one does not leave the node half finalized.
+	for _, pvc := range pvcs.Items {
+		if pvc.Spec.StorageClassName == nil {
+			continue
+		}
+		sc, ok := scs[*pvc.Spec.StorageClassName]
+		if !ok {
+			continue
+		}
+		if _, _, err := nodeDeletionPolicy(sc); err != nil {
+			log.Error(err, "invalid node deletion policy", "storageclass", sc.Name)
+			r.Recorder.Event(node, corev1.EventTypeWarning, invalidNodeDeletionPolicyReason, err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+
+	// volume IDs of the retained volumes
+	retained := make(map[string]bool)
 	for _, pvc := range pvcs.Items {
 		if pvc.Spec.StorageClassName == nil {
 			continue
 		}
-		if !scs[*pvc.Spec.StorageClassName] {
+		sc, ok := scs[*pvc.Spec.StorageClassName]
+		if !ok {
 			continue
 		}
 
+		policy, grace, _ := nodeDeletionPolicy(sc)
+		if policy != topolvm.NodeDeletionPolicyDelete {
+			volumeID, err := r.markVolumeLost(ctx, log, node, &pvc, policy, grace)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			// PVCs not provisioned yet have nothing to retain.
+			if volumeID != "" {
+				retained[volumeID] = true
+				continue
+			}
+		}
+
 		err = r.Delete(ctx, &pvc)
 		if err != nil {
 			log.Error(err, "unable to delete PVC", "name", pvc.Name, "namespace", pvc.Namespace)
@@ -138,7 +201,11 @@ func (r *NodeReconciler) doFinalize(ctx context.Context, log logr.Logger, node *
 	}
 
 	for _, lv := range lvList.Items {
-		err = r.cleanupLogicalVolume(ctx, log, &lv)
+		if retained[lv.Status.VolumeID] {
+			log.Info("retained LogicalVolume", "name", lv.Name)
+			continue
+		}
+		err = cleanupLogicalVolume(ctx, r.Client, log, &lv)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -147,7 +214,76 @@ func (r *NodeReconciler) doFinalize(ctx context.Context, log logr.Logger, node *
 	return ctrl.Result{}, nil
 }
 
-func (r *NodeReconciler) cleanupLogicalVolume(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
+// markVolumeLost annotates the PV bound to pvc with the deleted node and reports it.
+// The state is also shown in the reason and the message of the PV status.
+// It returns the volume ID of the PV, or an empty string if pvc is not bound to a TopoLVM volume.
+func (r *NodeReconciler) markVolumeLost(ctx context.Context, log logr.Logger, node *corev1.Node, pvc *corev1.PersistentVolumeClaim, policy string, grace time.Duration) (string, error) {
+	if pvc.Spec.VolumeName == "" {
+		return "", nil
+	}
+
+	pv := &corev1.PersistentVolume{}
+	err := r.Get(ctx, client.ObjectKey{Name: pvc.Spec.VolumeName}, pv)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		log.Error(err, "unable to fetch PersistentVolume", "name", pvc.Spec.VolumeName)
+		return "", err
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != topolvm.PluginName {
+		return "", nil
+	}
+	volumeID := pv.Spec.CSI.VolumeHandle
+
+	if pv.Annotations[topolvm.LostNodeKey] != node.Name {
+		pv2 := pv.DeepCopy()
+		if pv2.Annotations == nil {
+			pv2.Annotations = make(map[string]string)
+		}
+		pv2.Annotations[topolvm.LostNodeKey] = node.Name
+		delete(pv2.Annotations, topolvm.CleanupAfterKey)
+		if policy == topolvm.NodeDeletionPolicyWaitForGracePeriod {
+			pv2.Annotations[topolvm.CleanupAfterKey] = time.Now().Add(grace).UTC().Format(time.RFC3339)
+		}
+		if err := r.Patch(ctx, pv2, client.MergeFrom(pv)); err != nil {
+			log.Error(err, "failed to annotate PersistentVolume", "name", pv.Name)
+			return "", err
+		}
+
+		message := volumeLostMessage(pv2)
+		r.Recorder.Event(pv2, corev1.EventTypeWarning, volumeLostReason, message)
+		r.Recorder.Event(pvc, corev1.EventTypeWarning, volumeLostReason, message)
+		log.Info("marked PersistentVolume as lost", "name", pv.Name, "node", node.Name, "policy", policy)
+		pv = pv2
+	}
+
+	// the status is updated after the annotations, so a failure here is retried by the next reconciliation.
+	message := volumeLostMessage(pv)
+	if pv.Status.Reason == volumeLostReason && pv.Status.Message == message {
+		return volumeID, nil
+	}
+	pv2 := pv.DeepCopy()
+	pv2.Status.Reason = volumeLostReason
+	pv2.Status.Message = message
+	if err := r.Status().Patch(ctx, pv2, client.MergeFrom(pv)); err != nil {
+		log.Error(err, "failed to update status of PersistentVolume", "name", pv.Name)
+		return "", err
+	}
+	return volumeID, nil
+}
+
+// volumeLostMessage returns the message for the lost PV from its annotations.
+func volumeLostMessage(pv *corev1.PersistentVolume) string {
+	nodeName := pv.Annotations[topolvm.LostNodeKey]
+	if cleanupAfter, ok := pv.Annotations[topolvm.CleanupAfterKey]; ok {
+		return fmt.Sprintf("node %s was deleted; the volume will be deleted after %s unless the node comes back",
+			nodeName, cleanupAfter)
+	}
+	return fmt.Sprintf("node %s was deleted; the volume is retained", nodeName)
+}
+
+func cleanupLogicalVolume(ctx context.Context, c client.Client, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
 	finExists := false
 	for _, fin := range lv.Finalizers {
 		if fin == topolvm.LogicalVolumeFinalizer {
@@ -168,13 +304,13 @@ func (r *NodeReconciler) cleanupLogicalVolume(ctx context.Context, log logr.Logg
 		lv2.Finalizers = finalizers
 
 		patch := client.MergeFrom(lv)
-		if err := r.Patch(ctx, lv2, patch); err != nil {
+		if err := c.Patch(ctx, lv2, patch); err != nil {
 			log.Error(err, "failed to patch LogicalVolume", "name", lv.Name)
 			return err
 		}
 	}
 
-	err := r.Delete(ctx, lv)
+	err := c.Delete(ctx, lv)
 	if err != nil {
 		log.Error(err, "failed to delete LogicalVolume", "name", lv.Name)
 		return err
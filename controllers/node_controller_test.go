@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("NodeReconciler and PersistentVolumeReconciler", func() {
+	ctx := context.Background()
+
+	createStorageClass := func(name string, annotations map[string]string) *storagev1.StorageClass {
+		sc := &storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+			},
+			Provisioner: topolvm.PluginName,
+		}
+		Expect(k8sClient.Create(ctx, sc)).To(Succeed())
+		return sc
+	}
+
+	createNode := func(name string) {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Finalizers: []string{topolvm.NodeFinalizer},
+			},
+		}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+	}
+
+	deleteNode := func(name string) {
+		node := &corev1.Node{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: name}, node)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, node)).To(Succeed())
+	}
+
+	// createVolume creates a bound PVC, its PV and its LogicalVolume on the node.
+	createVolume := func(name, nodeName, scName string) (*corev1.PersistentVolumeClaim, *corev1.PersistentVolume, *topolvmv1.LogicalVolume) {
+		size := resource.MustParse("1Gi")
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{AnnSelectedNode: nodeName},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: &scName,
+				VolumeName:       name,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:         corev1.ResourceList{corev1.ResourceStorage: size},
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: scName,
+				ClaimRef: &corev1.ObjectReference{
+					Namespace: pvc.Namespace,
+					Name:      pvc.Name,
+					UID:       pvc.UID,
+				},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver:       topolvm.PluginName,
+						VolumeHandle: name + "-id",
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pv)).To(Succeed())
+
+		lv := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Finalizers: []string{topolvm.LogicalVolumeFinalizer},
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:     name,
+				NodeName: nodeName,
+				Size:     size,
+			},
+		}
+		Expect(k8sClient.Create(ctx, lv)).To(Succeed())
+		lv.Status.VolumeID = name + "-id"
+		Expect(k8sClient.Status().Update(ctx, lv)).To(Succeed())
+		return pvc, pv, lv
+	}
+
+	// gone returns nil if obj is deleted or being deleted.
+	gone := func(obj client.Object) func() error {
+		return func() error {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if obj.GetDeletionTimestamp() != nil {
+				return nil
+			}
+			return errors.New("not deleted")
+		}
+	}
+
+	// removed returns nil if obj is removed completely.
+	removed := func(obj client.Object) func() error {
+		return func() error {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return errors.New("not removed")
+		}
+	}
+
+	// exists returns nil if obj exists and is not being deleted.
+	exists := func(obj client.Object) func() error {
+		return func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+			if obj.GetDeletionTimestamp() != nil {
+				return errors.New("being deleted")
+			}
+			return nil
+		}
+	}
+
+	It("should delete volumes on a deleted node by default", func() {
+		createStorageClass("sc-delete", nil)
+		createNode("node-delete")
+		pvc, pv, lv := createVolume("vol-delete", "node-delete", "sc-delete")
+
+		deleteNode("node-delete")
+		Eventually(removed(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-delete"}}), "10s").Should(Succeed())
+		Eventually(gone(pvc), "10s").Should(Succeed())
+		Eventually(gone(lv), "10s").Should(Succeed())
+
+		Expect(exists(pv)()).To(Succeed())
+		Expect(pv.Annotations).NotTo(HaveKey(topolvm.LostNodeKey))
+	})
+
+	It("should retain volumes on a deleted node", func() {
+		createStorageClass("sc-retain", map[string]string{
+			topolvm.NodeDeletionPolicyKey: topolvm.NodeDeletionPolicyRetain,
+		})
+		createNode("node-retain")
+		pvc, pv, lv := createVolume("vol-retain", "node-retain", "sc-retain")
+
+		deleteNode("node-retain")
+		Eventually(removed(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-retain"}}), "10s").Should(Succeed())
+
+		Expect(exists(pvc)()).To(Succeed())
+		Expect(exists(lv)()).To(Succeed())
+		Expect(exists(pv)()).To(Succeed())
+		Expect(pv.Annotations).To(HaveKeyWithValue(topolvm.LostNodeKey, "node-retain"))
+		Expect(pv.Annotations).NotTo(HaveKey(topolvm.CleanupAfterKey))
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv); err != nil {
+				return err
+			}
+			if pv.Status.Reason != volumeLostReason {
+				return fmt.Errorf("unexpected reason: %s", pv.Status.Reason)
+			}
+			return nil
+		}, "10s").Should(Succeed())
+		Expect(pv.Status.Message).To(Equal("node node-retain was deleted; the volume is retained"))
+
+		By("keeping the volumes without a grace period")
+		Consistently(exists(pvc), "2s").Should(Succeed())
+		Consistently(exists(lv), "2s").Should(Succeed())
+	})
+
+	It("should delete volumes after the grace period", func() {
+		createStorageClass("sc-grace", map[string]string{
+			topolvm.NodeDeletionPolicyKey:      topolvm.NodeDeletionPolicyWaitForGracePeriod,
+			topolvm.NodeDeletionGracePeriodKey: "1h",
+		})
+		createNode("node-grace")
+		before := time.Now()
+		pvc, pv, lv := createVolume("vol-grace", "node-grace", "sc-grace")
+
+		deleteNode("node-grace")
+		Eventually(removed(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-grace"}}), "10s").Should(Succeed())
+
+		Expect(exists(pv)()).To(Succeed())
+		Expect(pv.Annotations).To(HaveKeyWithValue(topolvm.LostNodeKey, "node-grace"))
+		cleanupAfter, err := time.Parse(time.RFC3339, pv.Annotations[topolvm.CleanupAfterKey])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cleanupAfter).To(BeTemporally(">=", before.Add(time.Hour).Truncate(time.Second)))
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv); err != nil {
+				return err
+			}
+			if pv.Status.Reason != volumeLostReason {
+				return fmt.Errorf("unexpected reason: %s", pv.Status.Reason)
+			}
+			return nil
+		}, "10s").Should(Succeed())
+		Expect(pv.Status.Message).To(ContainSubstring(pv.Annotations[topolvm.CleanupAfterKey]))
+		Consistently(exists(pvc), "2s").Should(Succeed())
+		Expect(exists(lv)()).To(Succeed())
+
+		By("expiring the grace period")
+		pv2 := pv.DeepCopy()
+		pv2.Annotations[topolvm.CleanupAfterKey] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		Expect(k8sClient.Patch(ctx, pv2, client.MergeFrom(pv))).To(Succeed())
+
+		Eventually(gone(pvc), "10s").Should(Succeed())
+		Eventually(gone(lv), "10s").Should(Succeed())
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv); err != nil {
+				return err
+			}
+			if _, ok := pv.Annotations[topolvm.CleanupAfterKey]; ok {
+				return errors.New("cleanup-after annotation remains")
+			}
+			return nil
+		}, "10s").Should(Succeed())
+	})
+
+	It("should recover volumes when the node comes back", func() {
+		createStorageClass("sc-recover", map[string]string{
+			topolvm.NodeDeletionPolicyKey: topolvm.NodeDeletionPolicyWaitForGracePeriod,
+		})
+		createNode("node-recover")
+		pvc, pv, lv := createVolume("vol-recover", "node-recover", "sc-recover")
+
+		deleteNode("node-recover")
+		Eventually(removed(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-recover"}}), "10s").Should(Succeed())
+		Expect(exists(pv)()).To(Succeed())
+		Expect(pv.Annotations).To(HaveKey(topolvm.LostNodeKey))
+		Expect(pv.Annotations).To(HaveKey(topolvm.CleanupAfterKey))
+
+		createNode("node-recover")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv); err != nil {
+				return err
+			}
+			if _, ok := pv.Annotations[topolvm.LostNodeKey]; ok {
+				return errors.New("lost-node annotation remains")
+			}
+			if _, ok := pv.Annotations[topolvm.CleanupAfterKey]; ok {
+				return errors.New("cleanup-after annotation remains")
+			}
+			if pv.Status.Reason != "" || pv.Status.Message != "" {
+				return fmt.Errorf("status remains: %s: %s", pv.Status.Reason, pv.Status.Message)
+			}
+			return nil
+		}, "10s").Should(Succeed())
+		Expect(exists(pvc)()).To(Succeed())
+		Expect(exists(lv)()).To(Succeed())
+	})
+
+	It("should not finalize a node with an unknown policy", func() {
+		sc := createStorageClass("sc-unknown", map[string]string{
+			topolvm.NodeDeletionPolicyKey: "keep",
+		})
+		createNode("node-unknown")
+		pvc, pv, lv := createVolume("vol-unknown", "node-unknown", "sc-unknown")
+
+		deleteNode("node-unknown")
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-unknown"}}
+		Consistently(func() error {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(node), node); err != nil {
+				return err
+			}
+			if node.DeletionTimestamp == nil {
+				return errors.New("node is not being deleted")
+			}
+			return nil
+		}, "2s").Should(Succeed())
+		Expect(exists(pvc)()).To(Succeed())
+		Expect(exists(lv)()).To(Succeed())
+		Expect(exists(pv)()).To(Succeed())
+		Expect(pv.Annotations).NotTo(HaveKey(topolvm.LostNodeKey))
+
+		By("fixing the policy")
+		sc2 := sc.DeepCopy()
+		sc2.Annotations[topolvm.NodeDeletionPolicyKey] = topolvm.NodeDeletionPolicyRetain
+		Expect(k8sClient.Patch(ctx, sc2, client.MergeFrom(sc))).To(Succeed())
+
+		Eventually(removed(node), "30s").Should(Succeed())
+		Expect(exists(pvc)()).To(Succeed())
+		Expect(exists(pv)()).To(Succeed())
+		Expect(pv.Annotations).To(HaveKeyWithValue(topolvm.LostNodeKey, "node-unknown"))
+	})
+})
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// PersistentVolumeReconciler reconciles PersistentVolumes whose node has been deleted
+type PersistentVolumeReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes/status,verbs=get;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile recovers a lost PV when its node comes back, or cleans it up after the grace period.
+func (r *PersistentVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	pv := &corev1.PersistentVolume{}
+	err := r.Get(ctx, req.NamespacedName, pv)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, err
+	}
+
+	nodeName, ok := pv.Annotations[topolvm.LostNodeKey]
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+
+	node := &corev1.Node{}
+	err = r.Get(ctx, client.ObjectKey{Name: nodeName}, node)
+	switch {
+	case err == nil && node.DeletionTimestamp == nil:
+		return ctrl.Result{}, r.recover(ctx, log, pv, nodeName)
+	case err == nil, apierrors.IsNotFound(err):
+	default:
+		log.Error(err, "unable to fetch Node", "name", nodeName)
+		return ctrl.Result{}, err
+	}
+
+	v, ok := pv.Annotations[topolvm.CleanupAfterKey]
+	if !ok {
+		// retained until the node comes back or an administrator handles it.
+		return ctrl.Result{}, nil
+	}
+	cleanupAfter, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		log.Error(err, "invalid annotation", "name", pv.Name, "key", topolvm.CleanupAfterKey, "value", v)
+		return ctrl.Result{}, nil
+	}
+	if wait := time.Until(cleanupAfter); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	return ctrl.Result{}, r.cleanup(ctx, log, pv, nodeName)
+}
+
+// recover removes the lost marks from pv as its node has come back.
+func (r *PersistentVolumeReconciler) recover(ctx context.Context, log logr.Logger, pv *corev1.PersistentVolume, nodeName string) error {
+	// the status is cleared first because the PV is no longer reconciled once the annotations are removed.
+	if pv.Status.Reason == volumeLostReason {
+		pv2 := pv.DeepCopy()
+		pv2.Status.Reason = ""
+		pv2.Status.Message = ""
+		if err := r.Status().Patch(ctx, pv2, client.MergeFrom(pv)); err != nil {
+			log.Error(err, "failed to clear status", "name", pv.Name)
+			return err
+		}
+		pv = pv2
+	}
+
+	pv2 := pv.DeepCopy()
+	delete(pv2.Annotations, topolvm.LostNodeKey)
+	delete(pv2.Annotations, topolvm.CleanupAfterKey)
+	if err := r.Patch(ctx, pv2, client.MergeFrom(pv)); err != nil {
+		log.Error(err, "failed to remove annotations", "name", pv.Name)
+		return err
+	}
+
+	r.Recorder.Eventf(pv2, corev1.EventTypeNormal, "NodeRecovered", "node %s came back", nodeName)
+	log.Info("recovered lost PersistentVolume", "name", pv.Name, "node", nodeName)
+	return nil
+}
+
+// cleanup deletes the PVC and the LogicalVolume of pv as the grace period has expired.
+func (r *PersistentVolumeReconciler) cleanup(ctx context.Context, log logr.Logger, pv *corev1.PersistentVolume, nodeName string) error {
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, pvc)
+		switch {
+		case err == nil:
+			if pvc.UID == ref.UID {
+				if err := r.Delete(ctx, pvc); err != nil {
+					log.Error(err, "unable to delete PVC", "name", pvc.Name, "namespace", pvc.Namespace)
+					return err
+				}
+				log.Info("deleted PVC", "name", pvc.Name, "namespace", pvc.Namespace)
+			}
+		case apierrors.IsNotFound(err):
+		default:
+			log.Error(err, "unable to fetch PVC", "name", ref.Name, "namespace", ref.Namespace)
+			return err
+		}
+	}
+
+	lvList := new(topolvmv1.LogicalVolumeList)
+	err := r.List(ctx, lvList, client.MatchingFields{keyLogicalVolumeNode: nodeName})
+	if err != nil {
+		log.Error(err, "failed to get LogicalVolumes")
+		return err
+	}
+	for _, lv := range lvList.Items {
+		if pv.Spec.CSI == nil || lv.Status.VolumeID != pv.Spec.CSI.VolumeHandle {
+			continue
+		}
+		if err := cleanupLogicalVolume(ctx, r.Client, log, &lv); err != nil {
+			return err
+		}
+	}
+
+	pv2 := pv.DeepCopy()
+	delete(pv2.Annotations, topolvm.CleanupAfterKey)
+	if err := r.Patch(ctx, pv2, client.MergeFrom(pv)); err != nil {
+		log.Error(err, "failed to remove annotation", "name", pv.Name)
+		return err
+	}
+
+	r.Recorder.Event(pv2, corev1.EventTypeWarning, "VolumeCleanedUp",
+		fmt.Sprintf("node %s did not come back within the grace period", nodeName))
+	log.Info("cleaned up lost PersistentVolume", "name", pv.Name, "node", nodeName)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// This must be called after NodeReconciler.SetupWithManager because it uses the index for LogicalVolume.
+func (r *PersistentVolumeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	isLost := func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[topolvm.LostNodeKey]
+		return ok
+	}
+	pvPred := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isLost(e.Object) },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isLost(e.ObjectNew) },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+	// a re-registered Node is created again with the same name.
+	nodePred := predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return true },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.PersistentVolume{}, builder.WithPredicates(pvPred)).
+		Watches(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(r.lostVolumesOfNode), builder.WithPredicates(nodePred)).
+		Complete(r)
+}
+
+func (r *PersistentVolumeReconciler) lostVolumesOfNode(obj client.Object) []reconcile.Request {
+	var pvs corev1.PersistentVolumeList
+	if err := r.List(context.Background(), &pvs); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, pv := range pvs.Items {
+		if pv.Annotations[topolvm.LostNodeKey] != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: pv.Name}})
+	}
+	return requests
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const evictionStatusUpdateInterval = 10 * time.Second
+
+// PhysicalVolumeEvictionReconciler reconciles a PhysicalVolumeEviction object
+type PhysicalVolumeEvictionReconciler struct {
+	client.Client
+	nodeName  string
+	pvService proto.PVServiceClient
+
+	mu sync.Mutex
+	// started has the evictions started by this process.  An eviction is kept
+	// after it completes so that it is not started again while the cache is stale.
+	started map[types.UID]bool
+}
+
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=physicalvolumeevictions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=physicalvolumeevictions/status,verbs=get;update;patch
+
+// NewPhysicalVolumeEvictionReconciler returns PhysicalVolumeEvictionReconciler with creating pvService.
+func NewPhysicalVolumeEvictionReconciler(client client.Client, nodeName string, conn *grpc.ClientConn) *PhysicalVolumeEvictionReconciler {
+	return &PhysicalVolumeEvictionReconciler{
+		Client:    client,
+		nodeName:  nodeName,
+		pvService: proto.NewPVServiceClient(conn),
+		started:   make(map[types.UID]bool),
+	}
+}
+
+// Reconcile starts moving all extents off the physical volume of a PhysicalVolumeEviction.
+// As moving extents takes long, it runs in the background and does not block the other evictions.
+// An eviction is processed only once; it is not retried after it succeeds or fails.
+// An eviction left running by a restart of topolvm-node is started again.
+func (r *PhysicalVolumeEvictionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	ev := new(topolvmv1.PhysicalVolumeEviction)
+	if err := r.Get(ctx, req.NamespacedName, ev); err != nil {
+		if !apierrs.IsNotFound(err) {
+			log.Error(err, "unable to fetch PhysicalVolumeEviction")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if ev.Spec.NodeName != r.nodeName || ev.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	if ev.Status.Phase == topolvmv1.EvictionPhaseSucceeded || ev.Status.Phase == topolvmv1.EvictionPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.started[ev.UID] {
+		return ctrl.Result{}, nil
+	}
+
+	ev.Status.Phase = topolvmv1.EvictionPhaseRunning
+	ev.Status.Message = ""
+	if err := r.Status().Update(ctx, ev); err != nil {
+		log.Error(err, "failed to update status", "name", ev.Name)
+		return ctrl.Result{}, err
+	}
+
+	r.started[ev.UID] = true
+	go r.run(logr.NewContext(context.Background(), log), ev)
+	return ctrl.Result{}, nil
+}
+
+// run evicts the PV and records the result in the status.
+func (r *PhysicalVolumeEvictionReconciler) run(ctx context.Context, ev *topolvmv1.PhysicalVolumeEviction) {
+	log := crlog.FromContext(ctx)
+	key := client.ObjectKeyFromObject(ev)
+
+	log.Info("start evicting PV", "name", ev.Name, "pv", ev.Spec.PhysicalVolume, "deviceClass", ev.Spec.DeviceClass)
+	err := r.evictPV(ctx, log, ev)
+	phase := topolvmv1.EvictionPhaseSucceeded
+	var message string
+	if err != nil {
+		_, message = extractFromError(err)
+		phase = topolvmv1.EvictionPhaseFailed
+	}
+
+	err2 := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := r.Get(ctx, key, ev); err != nil {
+			return err
+		}
+		ev.Status.Phase = phase
+		ev.Status.Message = message
+		return r.Status().Update(ctx, ev)
+	})
+	if err2 != nil {
+		// the eviction is started again by the next reconciliation because the phase remains running.
+		log.Error(err2, "failed to update status", "name", ev.Name)
+		r.mu.Lock()
+		delete(r.started, ev.UID)
+		r.mu.Unlock()
+		return
+	}
+	if err != nil {
+		log.Error(err, "failed to evict PV", "name", ev.Name, "pv", ev.Spec.PhysicalVolume)
+		return
+	}
+	log.Info("evicted PV", "name", ev.Name, "pv", ev.Spec.PhysicalVolume, "remove", ev.Spec.Remove)
+}
+
+// evictPV calls EvictPV of lvmd.
+// The progress is recorded in the status at most once per evictionStatusUpdateInterval.
+func (r *PhysicalVolumeEvictionReconciler) evictPV(ctx context.Context, log logr.Logger, ev *topolvmv1.PhysicalVolumeEviction) error {
+	stream, err := r.pvService.EvictPV(ctx, &proto.EvictPVRequest{
+		Name:        ev.Spec.PhysicalVolume,
+		DeviceClass: ev.Spec.DeviceClass,
+		Remove:      ev.Spec.Remove,
+	})
+	if err != nil {
+		return err
+	}
+
+	var lastUpdate time.Time
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ev.Status.MovedSize = resource.NewQuantity(int64(resp.MovedBytes), resource.BinarySI)
+		ev.Status.TotalSize = resource.NewQuantity(int64(resp.TotalBytes), resource.BinarySI)
+		if resp.MovedBytes < resp.TotalBytes && time.Since(lastUpdate) < evictionStatusUpdateInterval {
+			continue
+		}
+		lastUpdate = time.Now()
+		if err := r.Status().Update(ctx, ev); err != nil {
+			// the progress is informational, so eviction continues
+			log.Error(err, "failed to update status", "name", ev.Name)
+		}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PhysicalVolumeEvictionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	onNode := func(obj client.Object) bool {
+		return obj.(*topolvmv1.PhysicalVolumeEviction).Spec.NodeName == r.nodeName
+	}
+	pred := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return onNode(e.Object) },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return onNode(e.ObjectNew) },
+		GenericFunc: func(e event.GenericEvent) bool { return onNode(e.Object) },
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topolvmv1.PhysicalVolumeEviction{}).
+		WithEventFilter(pred).
+		Complete(r)
+}
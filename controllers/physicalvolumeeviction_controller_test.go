@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/lvmdtest"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("PhysicalVolumeEvictionReconciler", func() {
+	ctx := context.Background()
+	var cleanups []func()
+	AfterEach(func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+		cleanups = nil
+	})
+
+	// startLVMd serves lvmd backed by b, and returns a reconciler connected to it.
+	startLVMd := func(b *fake.Backend) *PhysicalVolumeEvictionReconciler {
+		dir, err := os.MkdirTemp("", "lvmd")
+		Expect(err).NotTo(HaveOccurred())
+		cleanups = append(cleanups, func() { os.RemoveAll(dir) })
+		server, err := lvmdtest.NewServer(dir, b, []*lvmd.DeviceClass{{Name: "ssd", VolumeGroup: "vg", Default: true}})
+		Expect(err).NotTo(HaveOccurred())
+		cleanups = append(cleanups, server.Close)
+
+		conn, err := server.Dial()
+		Expect(err).NotTo(HaveOccurred())
+		cleanups = append(cleanups, func() { conn.Close() })
+		return NewPhysicalVolumeEvictionReconciler(k8sClient, "node1", conn)
+	}
+
+	newBackend := func() *fake.Backend {
+		b := fake.New()
+		b.AddPhysicalVolume("vg", "/dev/fake1", 10<<30)
+		b.AddPhysicalVolume("vg", "/dev/fake2", 10<<30)
+		vg, err := b.FindVolumeGroup(ctx, "vg")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = vg.CreateVolume(ctx, "lv1", 4<<30, nil, 0, "")
+		Expect(err).NotTo(HaveOccurred())
+		return b
+	}
+
+	createEviction := func(name, nodeName, pv string, remove bool) *topolvmv1.PhysicalVolumeEviction {
+		ev := &topolvmv1.PhysicalVolumeEviction{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: topolvmv1.PhysicalVolumeEvictionSpec{
+				NodeName:       nodeName,
+				DeviceClass:    "ssd",
+				PhysicalVolume: pv,
+				Remove:         remove,
+			},
+		}
+		Expect(k8sClient.Create(ctx, ev)).To(Succeed())
+		return ev
+	}
+
+	reconcile := func(r *PhysicalVolumeEvictionReconciler, ev *topolvmv1.PhysicalVolumeEviction) {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: ev.Name}})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	phase := func(ev *topolvmv1.PhysicalVolumeEviction) func() (topolvmv1.EvictionPhase, error) {
+		return func() (topolvmv1.EvictionPhase, error) {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ev), ev); err != nil {
+				return "", err
+			}
+			return ev.Status.Phase, nil
+		}
+	}
+
+	pvNames := func(b *fake.Backend) []string {
+		vg, err := b.FindVolumeGroup(ctx, "vg")
+		Expect(err).NotTo(HaveOccurred())
+		pvs, err := vg.ListPhysicalVolumes(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, pv := range pvs {
+			names = append(names, pv.Name())
+		}
+		return names
+	}
+
+	It("should evict and remove a physical volume", func() {
+		b := newBackend()
+		r := startLVMd(b)
+		ev := createEviction("evict-remove", "node1", "/dev/fake1", true)
+
+		reconcile(r, ev)
+		Eventually(phase(ev), "10s").Should(Equal(topolvmv1.EvictionPhaseSucceeded))
+		Expect(ev.Status.Message).To(BeEmpty())
+		Expect(ev.Status.MovedSize).NotTo(BeNil())
+		Expect(ev.Status.MovedSize.Value()).To(Equal(int64(4 << 30)))
+		Expect(ev.Status.TotalSize.Value()).To(Equal(int64(4 << 30)))
+		Expect(pvNames(b)).To(Equal([]string{"/dev/fake2"}))
+
+		By("not processing it again")
+		b.InjectFailure(fake.OpMove, errors.New("must not be called"), 0)
+		reconcile(r, ev)
+		Consistently(phase(ev), "1s").Should(Equal(topolvmv1.EvictionPhaseSucceeded))
+	})
+
+	It("should fail without moving data if the physical volume cannot be evicted", func() {
+		b := newBackend()
+		vg, err := b.FindVolumeGroup(ctx, "vg")
+		Expect(err).NotTo(HaveOccurred())
+		// only 2 GiB is left on /dev/fake2.
+		_, err = vg.CreateVolume(ctx, "lv2", 12<<30, nil, 0, "")
+		Expect(err).NotTo(HaveOccurred())
+		r := startLVMd(b)
+		ev := createEviction("evict-refused", "node1", "/dev/fake1", true)
+
+		reconcile(r, ev)
+		Eventually(phase(ev), "10s").Should(Equal(topolvmv1.EvictionPhaseFailed))
+		Expect(ev.Status.Message).To(ContainSubstring("not enough free space"))
+		Expect(ev.Status.MovedSize).To(BeNil())
+		Expect(pvNames(b)).To(Equal([]string{"/dev/fake1", "/dev/fake2"}))
+	})
+
+	It("should fail if moving extents fails", func() {
+		b := newBackend()
+		b.InjectFailure(fake.OpMove, errors.New("pvmove failed"), 0)
+		r := startLVMd(b)
+		ev := createEviction("evict-move-failure", "node1", "/dev/fake1", false)
+
+		reconcile(r, ev)
+		Eventually(phase(ev), "10s").Should(Equal(topolvmv1.EvictionPhaseFailed))
+		Expect(ev.Status.Message).To(ContainSubstring("pvmove failed"))
+
+		By("not retrying the failed eviction")
+		b.ClearFailures()
+		reconcile(r, ev)
+		Consistently(phase(ev), "1s").Should(Equal(topolvmv1.EvictionPhaseFailed))
+	})
+
+	It("should ignore evictions for other nodes", func() {
+		r := startLVMd(newBackend())
+		ev := createEviction("evict-other-node", "node2", "/dev/fake1", false)
+
+		reconcile(r, ev)
+		Consistently(phase(ev), "1s").Should(BeEmpty())
+	})
+
+	It("should not block the reconciliation while moving extents", func() {
+		b := newBackend()
+		r := startLVMd(b)
+		ev := createEviction("evict-background", "node1", "/dev/fake1", false)
+
+		// EvictPV does not return until unblock is closed.
+		r.pvService = &blockingPVService{PVServiceClient: r.pvService, unblock: make(chan struct{})}
+		reconcile(r, ev)
+		Eventually(phase(ev), "10s").Should(Equal(topolvmv1.EvictionPhaseRunning))
+
+		By("ignoring the running eviction")
+		reconcile(r, ev)
+		Expect(phase(ev)()).To(Equal(topolvmv1.EvictionPhaseRunning))
+
+		close(r.pvService.(*blockingPVService).unblock)
+		Eventually(phase(ev), "10s").Should(Equal(topolvmv1.EvictionPhaseSucceeded))
+	})
+})
+
+// blockingPVService blocks EvictPV until unblock is closed.
+type blockingPVService struct {
+	proto.PVServiceClient
+	unblock chan struct{}
+}
+
+func (s *blockingPVService) EvictPV(ctx context.Context, in *proto.EvictPVRequest, opts ...grpc.CallOption) (proto.PVService_EvictPVClient, error) {
+	<-s.unblock
+	return s.PVServiceClient.EvictPV(ctx, in, opts...)
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/quota"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// StorageQuotaReconciler reconciles a StorageQuota object
+type StorageQuotaReconciler struct {
+	client.Client
+}
+
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=storagequotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=storagequotas/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch
+
+// Reconcile updates the usage in the status of StorageQuota.
+func (r *StorageQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	q := &topolvmv1.StorageQuota{}
+	err := r.Get(ctx, req.NamespacedName, q)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, err
+	}
+
+	usage, err := quota.NewChecker(r.Client).Usage(ctx, q.Namespace, "")
+	if err != nil {
+		log.Error(err, "failed to calculate usage", "name", q.Name, "namespace", q.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	used := make(map[string]resource.Quantity)
+	for dc := range q.Spec.Hard {
+		used[dc] = *resource.NewQuantity(usage[dc], resource.BinarySI)
+	}
+
+	q2 := q.DeepCopy()
+	q2.Status.Used = used
+	if err := r.Status().Patch(ctx, q2, client.MergeFrom(q)); err != nil {
+		log.Error(err, "failed to update status", "name", q.Name, "namespace", q.Namespace)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the usage of a bound PVC follows the size of its LogicalVolume, which changes
+	// after the PVC while the LogicalVolume is being resized.
+	lvPred := predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldLV := e.ObjectOld.(*topolvmv1.LogicalVolume)
+			newLV := e.ObjectNew.(*topolvmv1.LogicalVolume)
+			return oldLV.Spec.Size.Cmp(newLV.Spec.Size) != 0 ||
+				!equalQuantity(oldLV.Status.CurrentSize, newLV.Status.CurrentSize)
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topolvmv1.StorageQuota{}).
+		Watches(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, handler.EnqueueRequestsFromMapFunc(r.quotasOfNamespace)).
+		Watches(&source.Kind{Type: &topolvmv1.LogicalVolume{}}, handler.EnqueueRequestsFromMapFunc(r.quotasOfLogicalVolume), builder.WithPredicates(lvPred)).
+		Complete(r)
+}
+
+// quotasOfLogicalVolume returns the quotas in the namespace of the PVC bound to the LogicalVolume.
+// The LogicalVolume has the same name as its PV.
+func (r *StorageQuotaReconciler) quotasOfLogicalVolume(obj client.Object) []reconcile.Request {
+	var pv corev1.PersistentVolume
+	if err := r.Get(context.Background(), client.ObjectKey{Name: obj.GetName()}, &pv); err != nil {
+		return nil
+	}
+	if pv.Spec.ClaimRef == nil {
+		return nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvc.Namespace = pv.Spec.ClaimRef.Namespace
+	return r.quotasOfNamespace(pvc)
+}
+
+func (r *StorageQuotaReconciler) quotasOfNamespace(obj client.Object) []reconcile.Request {
+	var quotas topolvmv1.StorageQuotaList
+	if err := r.List(context.Background(), &quotas, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(quotas.Items))
+	for i, q := range quotas.Items {
+		requests[i] = reconcile.Request{NamespacedName: client.ObjectKey{Namespace: q.Namespace, Name: q.Name}}
+	}
+	return requests
+}
+
+func equalQuantity(a, b *resource.Quantity) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(*b) == 0
+}
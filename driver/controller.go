@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
 	"github.com/topolvm/topolvm/csi"
 	"github.com/topolvm/topolvm/driver/k8s"
+	"github.com/topolvm/topolvm/policy"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -16,16 +20,21 @@ import (
 
 var ctrlLogger = ctrl.Log.WithName("driver").WithName("controller")
 
+// pvcNamespaceKey is the key of the parameter given by external-provisioner with --extra-create-metadata.
+const pvcNamespaceKey = "csi.storage.k8s.io/pvc/namespace"
+
 // NewControllerService returns a new ControllerServer.
-func NewControllerService(lvService *k8s.LogicalVolumeService, nodeService *k8s.NodeService) csi.ControllerServer {
-	return &controllerService{lvService: lvService, nodeService: nodeService}
+// If policyChecker is nil, DeviceClassPolicies are not enforced.
+func NewControllerService(lvService *k8s.LogicalVolumeService, nodeService *k8s.NodeService, policyChecker *policy.Checker) csi.ControllerServer {
+	return &controllerService{lvService: lvService, nodeService: nodeService, policyChecker: policyChecker}
 }
 
 type controllerService struct {
 	csi.UnimplementedControllerServer
 
-	lvService   *k8s.LogicalVolumeService
-	nodeService *k8s.NodeService
+	lvService     *k8s.LogicalVolumeService
+	nodeService   *k8s.NodeService
+	policyChecker *policy.Checker
 }
 
 func (s controllerService) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
@@ -81,6 +90,10 @@ func (s controllerService) CreateVolume(ctx context.Context, req *csi.CreateVolu
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err := s.checkPolicy(ctx, req.GetParameters()[pvcNamespaceKey], deviceClass); err != nil {
+		return nil, err
+	}
+
 	// process topology
 	var node string
 	requirements := req.GetAccessibilityRequirements()
@@ -150,6 +163,25 @@ func (s controllerService) CreateVolume(ctx context.Context, req *csi.CreateVolu
 	}, nil
 }
 
+// checkPolicy returns PermissionDenied error if namespace is not allowed to use deviceClass.
+// The check is skipped if namespace is not given by external-provisioner.
+func (s controllerService) checkPolicy(ctx context.Context, namespace, deviceClass string) error {
+	if s.policyChecker == nil || namespace == "" {
+		return nil
+	}
+	if deviceClass == topolvm.DefaultDeviceClassName {
+		deviceClass = topolvm.DefaultDeviceClassAnnotationName
+	}
+	allowed, err := s.policyChecker.Allowed(ctx, namespace, deviceClass)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to check device-class policy: %v", err)
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "namespace %s is not allowed to use device-class %s", namespace, deviceClass)
+	}
+	return nil
+}
+
 func convertRequestCapacity(requestBytes, limitBytes int64) (int64, error) {
 	if requestBytes < 0 {
 		return 0, errors.New("required capacity must not be negative")
@@ -278,6 +310,9 @@ func (s controllerService) ControllerGetCapabilities(context.Context, *csi.Contr
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
 	}
 
 	csiCaps := make([]*csi.ControllerServiceCapability, len(capabilities))
@@ -362,3 +397,149 @@ func (s controllerService) ControllerExpandVolume(ctx context.Context, req *csi.
 		NodeExpansionRequired: true,
 	}, nil
 }
+
+func (s controllerService) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	ctrlLogger.Info("ListVolumes called",
+		"max_entries", req.GetMaxEntries(),
+		"starting_token", req.GetStartingToken())
+
+	if req.GetMaxEntries() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_entries must not be negative")
+	}
+
+	lvs, err := s.lvService.ListVolumes(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	page, nextToken, err := paginateVolumes(lvs, req.GetStartingToken(), req.GetMaxEntries())
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	entries := make([]*csi.ListVolumesResponse_Entry, len(page))
+	for i := range page {
+		lv := &page[i]
+		condition, err := s.volumeCondition(ctx, lv)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		entries[i] = &csi.ListVolumesResponse_Entry{
+			Volume: newCSIVolume(lv),
+			Status: &csi.ListVolumesResponse_VolumeStatus{
+				VolumeCondition: condition,
+			},
+		}
+	}
+
+	return &csi.ListVolumesResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
+}
+
+func (s controllerService) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	ctrlLogger.Info("ControllerGetVolume called", "volume_id", volumeID)
+
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "volume id is nil")
+	}
+
+	lv, err := s.lvService.GetVolume(ctx, volumeID)
+	if err != nil {
+		if err == k8s.ErrVolumeNotFound {
+			return nil, status.Errorf(codes.NotFound, "LogicalVolume for volume id %s is not found", volumeID)
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	condition, err := s.volumeCondition(ctx, lv)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.ControllerGetVolumeResponse{
+		Volume: newCSIVolume(lv),
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			VolumeCondition: condition,
+		},
+	}, nil
+}
+
+func (s controllerService) volumeCondition(ctx context.Context, lv *topolvmv1.LogicalVolume) (*csi.VolumeCondition, error) {
+	nodeExists, err := s.nodeService.NodeExists(ctx, lv.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	return logicalVolumeCondition(lv, nodeExists), nil
+}
+
+// logicalVolumeCondition returns the condition of the volume observed from LogicalVolume.
+// The status is set by topolvm-node, e.g. when the LV is missing according to lvmd.
+func logicalVolumeCondition(lv *topolvmv1.LogicalVolume, nodeExists bool) *csi.VolumeCondition {
+	if lv.Status.Code != codes.OK {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("LogicalVolume %s has an error: code=%s, message=%s", lv.Name, lv.Status.Code, lv.Status.Message),
+		}
+	}
+	if !nodeExists {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("node %s is not found", lv.Spec.NodeName),
+		}
+	}
+	return &csi.VolumeCondition{
+		Abnormal: false,
+		Message:  "volume is healthy",
+	}
+}
+
+func newCSIVolume(lv *topolvmv1.LogicalVolume) *csi.Volume {
+	size := lv.Spec.Size.Value()
+	if lv.Status.CurrentSize != nil {
+		size = lv.Status.CurrentSize.Value()
+	}
+	return &csi.Volume{
+		CapacityBytes: size,
+		VolumeId:      lv.Status.VolumeID,
+		AccessibleTopology: []*csi.Topology{
+			{
+				Segments: map[string]string{topolvm.TopologyNodeKey: lv.Spec.NodeName},
+			},
+		},
+	}
+}
+
+// paginateVolumes returns a page of provisioned volumes sorted by volume ID.
+// The token is the index of the first volume in the page.
+func paginateVolumes(lvs []topolvmv1.LogicalVolume, startingToken string, maxEntries int32) ([]topolvmv1.LogicalVolume, string, error) {
+	var provisioned []topolvmv1.LogicalVolume
+	for _, lv := range lvs {
+		if lv.Status.VolumeID == "" {
+			continue
+		}
+		provisioned = append(provisioned, lv)
+	}
+	sort.Slice(provisioned, func(i, j int) bool {
+		return provisioned[i].Status.VolumeID < provisioned[j].Status.VolumeID
+	})
+
+	start := 0
+	if startingToken != "" {
+		var err error
+		start, err = strconv.Atoi(startingToken)
+		if err != nil || start < 0 || start > len(provisioned) {
+			return nil, "", fmt.Errorf("invalid starting_token: %s", startingToken)
+		}
+	}
+
+	end := len(provisioned)
+	var nextToken string
+	if maxEntries > 0 && start+int(maxEntries) < end {
+		end = start + int(maxEntries)
+		nextToken = strconv.Itoa(end)
+	}
+	return provisioned[start:end], nextToken, nil
+}
@@ -2,6 +2,9 @@ package driver
 
 import (
 	"testing"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
 )
 
 func TestController(t *testing.T) {
@@ -52,3 +55,64 @@ func TestController(t *testing.T) {
 		t.Errorf("should be 2: %d", v)
 	}
 }
+
+func TestPaginateVolumes(t *testing.T) {
+	lvs := []topolvmv1.LogicalVolume{
+		{Status: topolvmv1.LogicalVolumeStatus{VolumeID: "c"}},
+		{Status: topolvmv1.LogicalVolumeStatus{VolumeID: ""}},
+		{Status: topolvmv1.LogicalVolumeStatus{VolumeID: "a"}},
+		{Status: topolvmv1.LogicalVolumeStatus{VolumeID: "b"}},
+	}
+
+	page, next, err := paginateVolumes(lvs, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 3 || next != "" {
+		t.Errorf("should return all provisioned volumes: len=%d, next=%s", len(page), next)
+	}
+
+	page, next, err = paginateVolumes(lvs, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].Status.VolumeID != "a" || page[1].Status.VolumeID != "b" {
+		t.Errorf("unexpected first page: %v", page)
+	}
+	if next != "2" {
+		t.Errorf("next token should be 2: %s", next)
+	}
+
+	page, next, err = paginateVolumes(lvs, next, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].Status.VolumeID != "c" || next != "" {
+		t.Errorf("unexpected last page: %v, next=%s", page, next)
+	}
+
+	for _, token := range []string{"x", "-1", "4"} {
+		_, _, err = paginateVolumes(lvs, token, 0)
+		if err == nil {
+			t.Errorf("should be error for token %s", token)
+		}
+	}
+}
+
+func TestLogicalVolumeCondition(t *testing.T) {
+	lv := &topolvmv1.LogicalVolume{}
+	lv.Spec.NodeName = "node1"
+
+	if c := logicalVolumeCondition(lv, true); c.Abnormal {
+		t.Error("should be normal", c.Message)
+	}
+	if c := logicalVolumeCondition(lv, false); !c.Abnormal {
+		t.Error("should be abnormal if the node is gone")
+	}
+
+	lv.Status.Code = codes.NotFound
+	lv.Status.Message = "missing"
+	if c := logicalVolumeCondition(lv, true); !c.Abnormal {
+		t.Error("should be abnormal if the status has an error")
+	}
+}
@@ -86,6 +86,16 @@ func (v *volumeGetter) Get(ctx context.Context, volumeID string) (*topolvmv1.Log
 	return foundLv, nil
 }
 
+// List returns all LogicalVolumes from the cache.
+func (v *volumeGetter) List(ctx context.Context) ([]topolvmv1.LogicalVolume, error) {
+	lvList := new(topolvmv1.LogicalVolumeList)
+	err := v.cacheReader.List(ctx, lvList)
+	if err != nil {
+		return nil, err
+	}
+	return lvList.Items, nil
+}
+
 //+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=logicalvolumes,verbs=get;list;watch;create;delete
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
@@ -273,6 +283,11 @@ func (s *LogicalVolumeService) GetVolume(ctx context.Context, volumeID string) (
 	return s.volumeGetter.Get(ctx, volumeID)
 }
 
+// ListVolumes returns all LogicalVolumes.
+func (s *LogicalVolumeService) ListVolumes(ctx context.Context) ([]topolvmv1.LogicalVolume, error) {
+	return s.volumeGetter.List(ctx)
+}
+
 // UpdateSpecSize updates .Spec.Size of LogicalVolume.
 func (s *LogicalVolumeService) UpdateSpecSize(ctx context.Context, volumeID string, size *resource.Quantity) error {
 	for {
@@ -3,10 +3,14 @@ package k8s
 import (
 	"context"
 	"errors"
+	"math"
 	"strconv"
+	"time"
 
 	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/reservation"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -18,12 +22,14 @@ var ErrDeviceClassNotFound = errors.New("device class not found")
 // NodeService represents node service.
 type NodeService struct {
 	// it is safe to use cache reader because updating node annotations is periodic.
-	reader client.Reader
+	reader      client.Reader
+	reservation *reservation.Calculator
 }
 
 // NewNodeService returns NodeService.
-func NewNodeService(mgr manager.Manager) *NodeService {
-	return &NodeService{reader: mgr.GetClient()}
+// If calc is not nil, the capacity reserved by calc is subtracted from the capacity of nodes.
+func NewNodeService(mgr manager.Manager, calc *reservation.Calculator) *NodeService {
+	return &NodeService{reader: mgr.GetClient(), reservation: calc}
 }
 
 func (s NodeService) getNodes(ctx context.Context) (*corev1.NodeList, error) {
@@ -46,7 +52,53 @@ func (s NodeService) extractCapacityFromAnnotation(node *corev1.Node, deviceClas
 	return strconv.ParseInt(c, 10, 64)
 }
 
+func (s NodeService) reserved(ctx context.Context) (map[string]map[string]int64, error) {
+	if s.reservation == nil {
+		return nil, nil
+	}
+	return s.reservation.Reserved(ctx, time.Now())
+}
+
+// availableCapacity returns the capacity of node excluding the reserved capacity.
+// A draining device-class has no available capacity for new volumes.
+// topolvm-node marks topolvm.DefaultDeviceClassAnnotationName as draining
+// while the default device-class is draining.
+func (s NodeService) availableCapacity(node *corev1.Node, deviceClass string, reserved map[string]map[string]int64) (int64, error) {
+	c, err := s.extractCapacityFromAnnotation(node, deviceClass)
+	if err != nil {
+		return 0, err
+	}
+	if deviceClass == topolvm.DefaultDeviceClassName {
+		deviceClass = topolvm.DefaultDeviceClassAnnotationName
+	}
+	if _, ok := node.Annotations[topolvm.DrainingKeyPrefix+deviceClass]; ok {
+		return 0, nil
+	}
+	c -= reserved[node.Name][deviceClass]
+	if c < 0 {
+		return 0, nil
+	}
+	return c, nil
+}
+
+// NodeExists returns true if the node of the specified name exists.
+func (s NodeService) NodeExists(ctx context.Context, name string) (bool, error) {
+	n := new(corev1.Node)
+	err := s.reader.Get(ctx, client.ObjectKey{Name: name}, n)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // GetCapacityByName returns VG capacity of specified node by name.
+// Unlike the other methods, it ignores the reservation and draining
+// because it is used to expand existing volumes.
+// As topolvm-node reports zero capacity for a draining device-class,
+// it returns math.MaxInt64 for it and leaves the check of the free space to lvmd.
 func (s NodeService) GetCapacityByName(ctx context.Context, name, deviceClass string) (int64, error) {
 	n := new(corev1.Node)
 	err := s.reader.Get(ctx, client.ObjectKey{Name: name}, n)
@@ -54,7 +106,17 @@ func (s NodeService) GetCapacityByName(ctx context.Context, name, deviceClass st
 		return 0, err
 	}
 
-	return s.extractCapacityFromAnnotation(n, deviceClass)
+	c, err := s.extractCapacityFromAnnotation(n, deviceClass)
+	if err != nil {
+		return 0, err
+	}
+	if deviceClass == topolvm.DefaultDeviceClassName {
+		deviceClass = topolvm.DefaultDeviceClassAnnotationName
+	}
+	if _, ok := n.Annotations[topolvm.DrainingKeyPrefix+deviceClass]; ok {
+		return math.MaxInt64, nil
+	}
+	return c, nil
 }
 
 // GetCapacityByTopologyLabel returns VG capacity of specified node by TopoLVM's topology label.
@@ -63,13 +125,17 @@ func (s NodeService) GetCapacityByTopologyLabel(ctx context.Context, topology, d
 	if err != nil {
 		return 0, err
 	}
+	reserved, err := s.reserved(ctx)
+	if err != nil {
+		return 0, err
+	}
 
 	for _, node := range nl.Items {
 		if v, ok := node.Labels[topolvm.TopologyNodeKey]; ok {
 			if v != topology {
 				continue
 			}
-			return s.extractCapacityFromAnnotation(&node, dc)
+			return s.availableCapacity(&node, dc, reserved)
 		}
 	}
 
@@ -82,10 +148,14 @@ func (s NodeService) GetTotalCapacity(ctx context.Context, dc string) (int64, er
 	if err != nil {
 		return 0, err
 	}
+	reserved, err := s.reserved(ctx)
+	if err != nil {
+		return 0, err
+	}
 
 	capacity := int64(0)
 	for _, node := range nl.Items {
-		c, _ := s.extractCapacityFromAnnotation(&node, dc)
+		c, _ := s.availableCapacity(&node, dc, reserved)
 		capacity += c
 	}
 	return capacity, nil
@@ -97,10 +167,14 @@ func (s NodeService) GetMaxCapacity(ctx context.Context, deviceClass string) (st
 	if err != nil {
 		return "", 0, err
 	}
+	reserved, err := s.reserved(ctx)
+	if err != nil {
+		return "", 0, err
+	}
 	var nodeName string
 	var maxCapacity int64
 	for _, node := range nl.Items {
-		c, _ := s.extractCapacityFromAnnotation(&node, deviceClass)
+		c, _ := s.availableCapacity(&node, deviceClass, reserved)
 		if maxCapacity < c {
 			maxCapacity = c
 			nodeName = node.Name
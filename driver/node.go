@@ -2,6 +2,7 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path"
@@ -35,6 +36,7 @@ const (
 	findmntCmd       = "/bin/findmnt"
 	devicePermission = 0600 | unix.S_IFBLK
 	ephVolConKey     = "csi.storage.k8s.io/ephemeral"
+	mountInfoPath    = "/proc/self/mountinfo"
 )
 
 var nodeLogger = ctrl.Log.WithName("driver").WithName("node")
@@ -303,7 +305,7 @@ func (s *nodeService) findVolumeByID(listResp *proto.GetLVListResponse, name str
 }
 
 func (s *nodeService) getLvFromContext(ctx context.Context, deviceClass, volumeID string) (*proto.LogicalVolume, error) {
-	listResp, err := s.client.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: deviceClass})
+	listResp, err := s.client.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: deviceClass, Name: volumeID})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list LV: %v", err)
 	}
@@ -435,8 +437,13 @@ func (s *nodeService) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "seek on %s was failed: %v", p, err)
 		}
+		condition, err := s.volumeCondition(ctx, volID, p, &st)
+		if err != nil {
+			return nil, err
+		}
 		return &csi.NodeGetVolumeStatsResponse{
-			Usage: []*csi.VolumeUsage{{Total: pos, Unit: csi.VolumeUsage_BYTES}},
+			Usage:           []*csi.VolumeUsage{{Total: pos, Unit: csi.VolumeUsage_BYTES}},
+			VolumeCondition: condition,
 		}, nil
 	}
 
@@ -445,7 +452,16 @@ func (s *nodeService) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 	}
 
 	var sfs unix.Statfs_t
-	if err := filesystem.Statfs(p, &sfs); err != nil {
+	switch err := filesystem.Statfs(p, &sfs); err {
+	case unix.EIO:
+		return &csi.NodeGetVolumeStatsResponse{
+			VolumeCondition: &csi.VolumeCondition{
+				Abnormal: true,
+				Message:  fmt.Sprintf("I/O error on the filesystem at %s", p),
+			},
+		}, nil
+	case nil:
+	default:
 		return nil, status.Errorf(codes.Internal, "statvfs on %s was failed: %v", p, err)
 	}
 
@@ -466,7 +482,96 @@ func (s *nodeService) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 			Available: int64(sfs.Ffree),
 		})
 	}
-	return &csi.NodeGetVolumeStatsResponse{Usage: usage}, nil
+
+	condition, err := s.volumeCondition(ctx, volID, p, &st)
+	if err != nil {
+		return nil, err
+	}
+	return &csi.NodeGetVolumeStatsResponse{Usage: usage, VolumeCondition: condition}, nil
+}
+
+// volumeCondition checks that the LV still exists in lvmd and the volume at volumePath is backed by it.
+func (s *nodeService) volumeCondition(ctx context.Context, volumeID, volumePath string, st *unix.Stat_t) (*csi.VolumeCondition, error) {
+	lvr, err := s.k8sLVService.GetVolume(ctx, volumeID)
+	deviceClass := topolvm.DefaultDeviceClassName
+	if err == nil {
+		deviceClass = lvr.Spec.DeviceClass
+	} else if err != k8s.ErrVolumeNotFound {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	lv, err := s.getLvFromContext(ctx, deviceClass, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if lv == nil {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("LV %s is not found in lvmd", volumeID),
+		}, nil
+	}
+
+	if (st.Mode & unix.S_IFMT) == unix.S_IFBLK {
+		if st.Rdev != unix.Mkdev(lv.DevMajor, lv.DevMinor) {
+			return &csi.VolumeCondition{
+				Abnormal: true,
+				Message: fmt.Sprintf("device file %s is %d:%d, but LV %s is %d:%d",
+					volumePath, unix.Major(st.Rdev), unix.Minor(st.Rdev), volumeID, lv.DevMajor, lv.DevMinor),
+			}, nil
+		}
+		return &csi.VolumeCondition{Message: "volume is healthy"}, nil
+	}
+
+	infos, err := mountutil.ParseMountInfo(mountInfoPath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse %s: %v", mountInfoPath, err)
+	}
+	return mountCondition(infos, volumePath, lv), nil
+}
+
+// mountCondition returns the condition of the filesystem mounted at target.
+// A filesystem mounted read-write whose superblock is read-only has been remounted
+// read-only by the kernel due to filesystem errors.
+func mountCondition(infos []mountutil.MountInfo, target string, lv *proto.LogicalVolume) *csi.VolumeCondition {
+	var mi *mountutil.MountInfo
+	// the last entry is the one visible at target if mounts are stacked.
+	for i := len(infos) - 1; i >= 0; i-- {
+		if infos[i].MountPoint == target {
+			mi = &infos[i]
+			break
+		}
+	}
+	if mi == nil {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume is not mounted at %s", target),
+		}
+	}
+
+	if uint32(mi.Major) != lv.DevMajor || uint32(mi.Minor) != lv.DevMinor {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message: fmt.Sprintf("%s is mounted from device %d:%d, but LV %s is %d:%d",
+				target, mi.Major, mi.Minor, lv.Name, lv.DevMajor, lv.DevMinor),
+		}
+	}
+
+	if containsOption(mi.MountOptions, "rw") && containsOption(mi.SuperOptions, "ro") {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("filesystem at %s is read-only, possibly due to filesystem errors", target),
+		}
+	}
+
+	return &csi.VolumeCondition{Message: "volume is healthy"}
+}
+
+func containsOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *nodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
@@ -568,6 +673,7 @@ func (s *nodeService) NodeGetCapabilities(context.Context, *csi.NodeGetCapabilit
 	capabilities := []csi.NodeServiceCapability_RPC_Type{
 		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
 		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
 	}
 
 	csiCaps := make([]*csi.NodeServiceCapability, len(capabilities))
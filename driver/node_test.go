@@ -0,0 +1,123 @@
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/lvmdtest"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	mountutil "k8s.io/mount-utils"
+)
+
+func TestMountCondition(t *testing.T) {
+	lv := &proto.LogicalVolume{Name: "vol1", DevMajor: 253, DevMinor: 3}
+	target := "/var/lib/kubelet/pods/uid/volumes/kubernetes.io~csi/pvc/mount"
+
+	cases := []struct {
+		name     string
+		infos    []mountutil.MountInfo
+		abnormal bool
+	}{
+		{
+			name: "healthy",
+			infos: []mountutil.MountInfo{
+				{MountPoint: "/", Major: 8, Minor: 1, MountOptions: []string{"rw"}, SuperOptions: []string{"rw"}},
+				{MountPoint: target, Major: 253, Minor: 3, MountOptions: []string{"rw", "relatime"}, SuperOptions: []string{"rw", "errors=remount-ro"}},
+			},
+			abnormal: false,
+		},
+		{
+			name: "not mounted",
+			infos: []mountutil.MountInfo{
+				{MountPoint: "/", Major: 8, Minor: 1, MountOptions: []string{"rw"}, SuperOptions: []string{"rw"}},
+			},
+			abnormal: true,
+		},
+		{
+			name: "different device",
+			infos: []mountutil.MountInfo{
+				{MountPoint: target, Major: 253, Minor: 4, MountOptions: []string{"rw"}, SuperOptions: []string{"rw"}},
+			},
+			abnormal: true,
+		},
+		{
+			name: "remounted read-only",
+			infos: []mountutil.MountInfo{
+				{MountPoint: target, Major: 253, Minor: 3, MountOptions: []string{"rw"}, SuperOptions: []string{"ro", "errors=remount-ro"}},
+			},
+			abnormal: true,
+		},
+		{
+			name: "read-only mount",
+			infos: []mountutil.MountInfo{
+				{MountPoint: target, Major: 253, Minor: 3, MountOptions: []string{"ro"}, SuperOptions: []string{"ro"}},
+			},
+			abnormal: false,
+		},
+		{
+			name: "stacked mount",
+			infos: []mountutil.MountInfo{
+				{MountPoint: target, Major: 253, Minor: 4, MountOptions: []string{"rw"}, SuperOptions: []string{"rw"}},
+				{MountPoint: target, Major: 253, Minor: 3, MountOptions: []string{"rw"}, SuperOptions: []string{"rw"}},
+			},
+			abnormal: false,
+		},
+	}
+
+	for _, c := range cases {
+		cond := mountCondition(c.infos, target, lv)
+		if cond.Abnormal != c.abnormal {
+			t.Errorf("%s: abnormal should be %v: %s", c.name, c.abnormal, cond.Message)
+		}
+	}
+}
+
+func TestGetLvFromContext(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 20<<30)
+	server, err := lvmdtest.NewServer(t.TempDir(), b, []*lvmd.DeviceClass{{Name: "ssd", VolumeGroup: "vg", Default: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vg.CreateVolume(ctx, "vol1", 1<<30, nil, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewNodeService("node1", conn, nil).(*nodeService)
+	lv, err := s.getLvFromContext(ctx, "ssd", "vol1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv == nil || lv.GetName() != "vol1" || lv.GetSizeGb() != 1 {
+		t.Errorf("unexpected volume: %v", lv)
+	}
+
+	lv, err = s.getLvFromContext(ctx, "ssd", "vol2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv != nil {
+		t.Errorf("expected no volume, but actual %v", lv)
+	}
+
+	_, err = s.getLvFromContext(ctx, "unknown", "vol1")
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected %s for an unknown device-class, but actual %v", codes.Internal, err)
+	}
+}
@@ -106,9 +106,16 @@ func testNode() {
 				vgName,
 			)
 			Expect(err).ShouldNot(HaveOccurred(), "stdout=%s, stderr=%s", targetBytes, stderr)
+			vgFree, err := strconv.ParseUint(strings.TrimSpace(string(targetBytes)), 10, 64)
+			Expect(err).ShouldNot(HaveOccurred())
+			// the spare capacity of "ssd" is 1 GiB.
+			var expected uint64
+			if vgFree > 1<<30 {
+				expected = vgFree - 1<<30
+			}
 			val, ok := node.Annotations[topolvm.CapacityKeyPrefix+"ssd"]
 			Expect(ok).To(Equal(true), "capacity is not annotated: "+node.Name)
-			Expect(val).To(Equal(strings.TrimSpace(string(targetBytes))), "unexpected capacity: "+node.Name)
+			Expect(val).To(Equal(strconv.FormatUint(expected, 10)), "unexpected capacity: "+node.Name)
 		}
 	})
 
@@ -3,10 +3,12 @@ package hook
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/topolvm/topolvm"
 	"github.com/topolvm/topolvm/getter"
+	"github.com/topolvm/topolvm/policy"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -19,6 +21,7 @@ import (
 
 type persistentVolumeClaimMutator struct {
 	getter  *getter.RetryMissingGetter
+	policy  *policy.Checker
 	decoder *admission.Decoder
 }
 
@@ -27,6 +30,7 @@ func PVCMutator(r client.Reader, apiReader client.Reader, dec *admission.Decoder
 	return &webhook.Admission{
 		Handler: &persistentVolumeClaimMutator{
 			getter:  getter.NewRetryMissingGetter(r, apiReader),
+			policy:  policy.NewChecker(r),
 			decoder: dec,
 		},
 	}
@@ -34,6 +38,8 @@ func PVCMutator(r client.Reader, apiReader client.Reader, dec *admission.Decoder
 
 //+kubebuilder:webhook:failurePolicy=fail,matchPolicy=equivalent,groups=core,resources=persistentvolumeclaims,verbs=create,versions=v1,name=pvc-hook.topolvm.cybozu.com,path=/pvc/mutate,mutating=true,sideEffects=none,admissionReviewVersions={v1,v1beta1}
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=deviceclasspolicies,verbs=get;list;watch
 
 // Handle implements admission.Handler interface.
 func (m *persistentVolumeClaimMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
@@ -63,6 +69,18 @@ func (m *persistentVolumeClaimMutator) Handle(ctx context.Context, req admission
 		return admission.Allowed("no request for TopoLVM")
 	}
 
+	dc, ok := sc.Parameters[topolvm.DeviceClassKey]
+	if !ok {
+		dc = topolvm.DefaultDeviceClassAnnotationName
+	}
+	allowed, err := m.policy.Allowed(ctx, req.Namespace, dc)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !allowed {
+		return admission.Denied(fmt.Sprintf("namespace %s is not allowed to use device-class %s", req.Namespace, dc))
+	}
+
 	if controllerutil.ContainsFinalizer(pvc, topolvm.PVCFinalizer) {
 		return admission.Allowed("already added finalizer")
 	}
@@ -4,14 +4,18 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
-	mutatePVCNamespace = "test-mutate-pvc"
-	defaultPVCName     = "test-pvc"
+	mutatePVCNamespace         = "test-mutate-pvc"
+	defaultPVCName             = "test-pvc"
+	restrictedStorageClassName = "topolvm-provisioner-restricted"
 )
 
 func setupMutatePVCResources() {
@@ -20,9 +24,34 @@ func setupMutatePVCResources() {
 	ns.Name = mutatePVCNamespace
 	err := k8sClient.Create(testCtx, ns)
 	Expect(err).ShouldNot(HaveOccurred())
+
+	// StorageClass of a device-class restricted to another namespace
+	sc := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: restrictedStorageClassName,
+		},
+		Provisioner: topolvm.PluginName,
+		Parameters: map[string]string{
+			topolvm.DeviceClassKey: "restricted",
+		},
+	}
+	err = k8sClient.Create(testCtx, sc)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	policy := &topolvmv1.DeviceClassPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "restricted",
+		},
+		Spec: topolvmv1.DeviceClassPolicySpec{
+			DeviceClass: "restricted",
+			Namespaces:  []string{"other"},
+		},
+	}
+	err = k8sClient.Create(testCtx, policy)
+	Expect(err).ShouldNot(HaveOccurred())
 }
 
-func createPVC(sc string, pvcName string) {
+func newPVC(sc string, pvcName string) *corev1.PersistentVolumeClaim {
 	pvc := &corev1.PersistentVolumeClaim{}
 	pvc.Namespace = mutatePVCNamespace
 	pvc.Name = pvcName
@@ -33,7 +62,11 @@ func createPVC(sc string, pvcName string) {
 	pvc.Spec.Resources.Requests = corev1.ResourceList{
 		"storage": *resource.NewQuantity(10<<30, resource.DecimalSI),
 	}
-	err := k8sClient.Create(testCtx, pvc)
+	return pvc
+}
+
+func createPVC(sc string, pvcName string) {
+	err := k8sClient.Create(testCtx, newPVC(sc, pvcName))
 	Expect(err).ShouldNot(HaveOccurred())
 }
 
@@ -101,4 +134,10 @@ var _ = Describe("pvc mutation webhook", func() {
 		hasFinalizer := hasTopoLVMFinalizer(pvc)
 		Expect(hasFinalizer).Should(Equal(true), "finalizer should be set for storageclass=%s", topolvmProvisionerImmediateStorageClassName)
 	})
+
+	It("should reject PVC of a device-class not allowed for the namespace", func() {
+		err := k8sClient.Create(testCtx, newPVC(restrictedStorageClassName, "restricted-pvc"))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("not allowed to use device-class restricted"))
+	})
 })
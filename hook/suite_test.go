@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -45,8 +46,9 @@ const (
 )
 
 var (
-	podMutatingWebhookPath = "/pod/mutate"
-	pvcMutatingWebhookPath = "/pvc/mutate"
+	podMutatingWebhookPath   = "/pod/mutate"
+	pvcMutatingWebhookPath   = "/pvc/mutate"
+	pvcValidatingWebhookPath = "/pvc/validate"
 )
 
 func strPtr(s string) *string { return &s }
@@ -192,6 +194,43 @@ var _ = BeforeSuite(func() {
 				},
 			},
 		},
+		ValidatingWebhooks: []*admissionv1.ValidatingWebhookConfiguration{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "topolvm-hook",
+				},
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "ValidatingWebhookConfiguration",
+					APIVersion: "admissionregistration.k8s.io/v1",
+				},
+				Webhooks: []admissionv1.ValidatingWebhook{
+					{
+						Name:                    "pvc-quota.topolvm.cybozu.com",
+						AdmissionReviewVersions: []string{"v1", "v1beta1"},
+						FailurePolicy:           &failPolicy,
+						ClientConfig: admissionv1.WebhookClientConfig{
+							Service: &admissionv1.ServiceReference{
+								Path: &pvcValidatingWebhookPath,
+							},
+						},
+						Rules: []admissionv1.RuleWithOperations{
+							{
+								Operations: []admissionv1.OperationType{
+									admissionv1.Create,
+									admissionv1.Update,
+								},
+								Rule: admissionv1.Rule{
+									APIGroups:   []string{""},
+									APIVersions: []string{"v1"},
+									Resources:   []string{"persistentvolumeclaims"},
+								},
+							},
+						},
+						SideEffects: &sideEffects,
+					},
+				},
+			},
+		},
 	}
 
 	testEnv = &envtest.Environment{
@@ -207,6 +246,8 @@ var _ = BeforeSuite(func() {
 	scheme := runtime.NewScheme()
 	err = clientgoscheme.AddToScheme(scheme)
 	Expect(err).ToNot(HaveOccurred())
+	err = topolvmv1.AddToScheme(scheme)
+	Expect(err).ToNot(HaveOccurred())
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
 	Expect(err).ToNot(HaveOccurred())
@@ -231,6 +272,7 @@ var _ = BeforeSuite(func() {
 	setupCommonResources()
 	setupMutatePodResources()
 	setupMutatePVCResources()
+	setupValidatePVCResources()
 }, 60)
 
 var _ = AfterSuite(func() {
@@ -0,0 +1,66 @@
+package hook
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/topolvm/topolvm/quota"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type persistentVolumeClaimValidator struct {
+	checker *quota.Checker
+	decoder *admission.Decoder
+}
+
+// PVCValidator creates a validating webhook for PVCs to enforce StorageQuotas.
+func PVCValidator(r client.Reader, dec *admission.Decoder) http.Handler {
+	return &webhook.Admission{
+		Handler: &persistentVolumeClaimValidator{
+			checker: quota.NewChecker(r),
+			decoder: dec,
+		},
+	}
+}
+
+//+kubebuilder:webhook:failurePolicy=fail,matchPolicy=equivalent,groups=core,resources=persistentvolumeclaims,verbs=create;update,versions=v1,name=pvc-quota.topolvm.cybozu.com,path=/pvc/validate,mutating=false,sideEffects=none,admissionReviewVersions={v1,v1beta1}
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=storagequotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=logicalvolumes,verbs=get;list;watch
+
+// Handle implements admission.Handler interface.
+//
+// The check is not atomic with the admission of other PVCs.  Requests processed
+// concurrently see the usage without each other, so they may exceed the quota together.
+func (v *persistentVolumeClaimValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := v.decoder.Decode(req, pvc)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1.Update {
+		old := &corev1.PersistentVolumeClaim{}
+		err := v.decoder.DecodeRaw(req.OldObject, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Only expansion can increase the usage.
+		if pvc.Spec.Resources.Requests.Storage().Cmp(*old.Spec.Resources.Requests.Storage()) <= 0 {
+			return admission.Allowed("not expanded")
+		}
+	}
+
+	msg, err := v.checker.Check(ctx, pvc)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if msg != "" {
+		return admission.Denied(msg)
+	}
+	return admission.Allowed("within quota")
+}
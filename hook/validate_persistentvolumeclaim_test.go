@@ -0,0 +1,115 @@
+package hook
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const expandableStorageClassName = "topolvm-provisioner-expandable"
+
+func setupValidatePVCResources() {
+	// PVCs can be expanded only with a StorageClass that allows expansion.
+	allowExpansion := true
+	sc := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: expandableStorageClassName,
+		},
+		Provisioner:          topolvm.PluginName,
+		AllowVolumeExpansion: &allowExpansion,
+		Parameters: map[string]string{
+			topolvm.DeviceClassKey: "ssd",
+		},
+	}
+	err := k8sClient.Create(testCtx, sc)
+	Expect(err).ShouldNot(HaveOccurred())
+}
+
+// createQuotaNamespace creates a namespace with a StorageQuota of 15 GiB for device-class ssd.
+func createQuotaNamespace(name string) {
+	ns := &corev1.Namespace{}
+	ns.Name = name
+	err := k8sClient.Create(testCtx, ns)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	quota := &topolvmv1.StorageQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "quota",
+			Namespace: name,
+		},
+		Spec: topolvmv1.StorageQuotaSpec{
+			Hard: map[string]resource.Quantity{
+				"ssd": resource.MustParse("15Gi"),
+			},
+		},
+	}
+	err = k8sClient.Create(testCtx, quota)
+	Expect(err).ShouldNot(HaveOccurred())
+}
+
+func newQuotaPVC(namespace, sc, name string, sizeGb int64) *corev1.PersistentVolumeClaim {
+	pvc := newPVC(sc, name)
+	pvc.Namespace = namespace
+	pvc.Spec.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: *resource.NewQuantity(sizeGb<<30, resource.BinarySI),
+	}
+	return pvc
+}
+
+var _ = Describe("pvc validation webhook", func() {
+	It("should allow PVCs within the quota", func() {
+		createQuotaNamespace("test-quota-allowed")
+		err := k8sClient.Create(testCtx, newQuotaPVC("test-quota-allowed", topolvmProvisionerStorageClassName, "pvc1", 10))
+		Expect(err).ShouldNot(HaveOccurred())
+		err = k8sClient.Create(testCtx, newQuotaPVC("test-quota-allowed", topolvmProvisioner2StorageClassName, "pvc2", 10))
+		Expect(err).ShouldNot(HaveOccurred(), "other device-classes should not be limited")
+	})
+
+	It("should reject creation of a PVC over the quota", func() {
+		createQuotaNamespace("test-quota-create")
+		err := k8sClient.Create(testCtx, newQuotaPVC("test-quota-create", topolvmProvisionerStorageClassName, "pvc1", 10))
+		Expect(err).ShouldNot(HaveOccurred())
+		err = k8sClient.Create(testCtx, newQuotaPVC("test-quota-create", topolvmProvisionerStorageClassName, "pvc2", 10))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("exceeded quota quota for device-class ssd"))
+	})
+
+	It("should reject expansion of a PVC over the quota", func() {
+		createQuotaNamespace("test-quota-expand")
+		err := k8sClient.Create(testCtx, newQuotaPVC("test-quota-expand", topolvmProvisionerStorageClassName, "pvc1", 10))
+		Expect(err).ShouldNot(HaveOccurred())
+		pvc := newQuotaPVC("test-quota-expand", expandableStorageClassName, "pvc2", 1)
+		err = k8sClient.Create(testCtx, pvc)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// only bound PVCs can be expanded.
+		pvc.Status.Phase = corev1.ClaimBound
+		err = k8sClient.Status().Update(testCtx, pvc)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("expanding within the quota")
+		pvc2 := pvc.DeepCopy()
+		pvc2.Spec.Resources.Requests[corev1.ResourceStorage] = *resource.NewQuantity(5<<30, resource.BinarySI)
+		err = k8sClient.Patch(testCtx, pvc2, client.MergeFrom(pvc))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("expanding over the quota")
+		pvc3 := pvc2.DeepCopy()
+		pvc3.Spec.Resources.Requests[corev1.ResourceStorage] = *resource.NewQuantity(6<<30, resource.BinarySI)
+		err = k8sClient.Patch(testCtx, pvc3, client.MergeFrom(pvc2))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("exceeded quota quota for device-class ssd"))
+	})
+
+	It("should not limit PVCs whose StorageClass is not for TopoLVM", func() {
+		createQuotaNamespace("test-quota-other")
+		err := k8sClient.Create(testCtx, newQuotaPVC("test-quota-other", hostLocalStorageClassName, "pvc1", 100))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+})
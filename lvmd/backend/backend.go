@@ -0,0 +1,126 @@
+// Package backend defines the LVM operations that lvmd depends on.
+//
+// NewLVM returns the implementation that runs LVM commands on the host.
+// Package fake provides an in-memory implementation for tests.
+package backend
+
+import (
+	"context"
+
+	"github.com/topolvm/topolvm/lvmd/command"
+)
+
+// ErrNotFound is returned when a VG, LV, thin pool or PV is not found.
+var ErrNotFound = command.ErrNotFound
+
+// Backend provides volume groups.
+type Backend interface {
+	// FindVolumeGroup finds a named volume group.
+	FindVolumeGroup(ctx context.Context, name string) (VolumeGroup, error)
+	// ListVolumeGroups lists all volume groups.
+	ListVolumeGroups(ctx context.Context) ([]VolumeGroup, error)
+}
+
+// VolumeGroup represents a volume group.
+type VolumeGroup interface {
+	// Name returns the volume group name.
+	Name() string
+	// Size returns the capacity of the volume group in bytes.
+	Size(ctx context.Context) (uint64, error)
+	// Free returns the free space of the volume group in bytes.
+	Free(ctx context.Context) (uint64, error)
+
+	// FindVolume finds a named logical volume in this volume group.
+	FindVolume(ctx context.Context, name string) (LogicalVolume, error)
+	// ListVolumes lists all logical volumes in this volume group except thin pools.
+	ListVolumes(ctx context.Context) ([]LogicalVolume, error)
+	// CreateVolume creates a logical volume of size bytes in this volume group.
+	CreateVolume(ctx context.Context, name string, size uint64, tags []string, stripe uint, stripeSize string) (LogicalVolume, error)
+
+	// FindPool finds a named thin pool in this volume group.
+	FindPool(ctx context.Context, name string) (ThinPool, error)
+	// ListPools lists all thin pools in this volume group.
+	ListPools(ctx context.Context) ([]ThinPool, error)
+	// CreatePool creates a thin pool of size bytes in this volume group.
+	CreatePool(ctx context.Context, name string, size uint64) (ThinPool, error)
+
+	// FindPhysicalVolume finds a named physical volume in this volume group.
+	FindPhysicalVolume(ctx context.Context, name string) (PhysicalVolume, error)
+	// ListPhysicalVolumes lists all physical volumes in this volume group.
+	ListPhysicalVolumes(ctx context.Context) ([]PhysicalVolume, error)
+}
+
+// LogicalVolume represents a logical volume.
+type LogicalVolume interface {
+	// Name returns the volume name.
+	Name() string
+	// FullName returns the VG prefixed volume name.
+	FullName() string
+	// Path returns the path to the device of the volume.
+	Path() string
+	// Size returns the size of the volume in bytes.
+	Size() uint64
+	// IsSnapshot returns true if the volume is a snapshot.
+	IsSnapshot() bool
+	// IsThin returns true if the volume is a thin volume.
+	IsThin() bool
+	// MajorNumber returns the device major number.
+	MajorNumber() uint32
+	// MinorNumber returns the device minor number.
+	MinorNumber() uint32
+	// Tags returns the tags of the volume.
+	Tags() []string
+
+	// IsOpen returns true if the device of the volume is opened, e.g. mounted.
+	IsOpen(ctx context.Context) (bool, error)
+	// Resize expands the volume to newSize bytes.
+	Resize(ctx context.Context, newSize uint64) error
+	// Remove removes the volume.
+	Remove(ctx context.Context) error
+	// Discard discards or zero-fills the range of the volume.
+	Discard(ctx context.Context, offset, length uint64, zero bool) error
+	// AddTags adds tags to the volume.
+	AddTags(ctx context.Context, tags ...string) error
+	// DelTags removes tags from the volume.
+	DelTags(ctx context.Context, tags ...string) error
+	// Rename renames the volume.
+	Rename(ctx context.Context, name string) error
+}
+
+// ThinPool represents a thin pool.
+type ThinPool interface {
+	// Name returns the thin pool name.
+	Name() string
+	// FullName returns the VG prefixed thin pool name.
+	FullName() string
+	// Size returns the size of the thin pool in bytes.
+	Size() uint64
+	// Resize changes the size of the thin pool to newSize bytes.
+	Resize(ctx context.Context, newSize uint64) error
+	// ListVolumes lists all thin volumes in this thin pool.
+	ListVolumes(ctx context.Context) ([]LogicalVolume, error)
+	// CreateVolume creates a thin volume of size bytes in this thin pool.
+	CreateVolume(ctx context.Context, name string, size uint64) (LogicalVolume, error)
+}
+
+// PhysicalVolume represents a physical volume of a volume group.
+type PhysicalVolume interface {
+	// Name returns the physical volume name, i.e. the device path.
+	Name() string
+	// Size returns the size of the physical volume in bytes.
+	Size() uint64
+	// Free returns the free space of the physical volume in bytes.
+	Free() uint64
+	// Used returns the allocated space of the physical volume in bytes.
+	Used() uint64
+
+	// Move moves all allocated extents to the other physical volumes of the volume group.
+	// progress is called periodically with the number of moved bytes and the total bytes.
+	Move(ctx context.Context, progress func(moved, total uint64) error) error
+	// SetAllocatable allows or disallows allocating new extents on the physical volume.
+	SetAllocatable(ctx context.Context, allocatable bool) error
+	// Reduce removes the physical volume from the volume group.
+	Reduce(ctx context.Context) error
+	// Remove wipes the LVM label of the physical volume.
+	Remove(ctx context.Context) error
+}
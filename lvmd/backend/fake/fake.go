@@ -0,0 +1,825 @@
+// Package fake provides an in-memory backend.Backend for tests.
+//
+// The fake keeps volume groups, logical volumes, thin pools and physical volumes in memory,
+// so that lvmd services can run without root privileges or loop devices.
+// Failures of operations can be injected with InjectFailure.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/command"
+)
+
+// Op is the name of an operation of the fake backend.
+type Op string
+
+// Operations that failures can be injected into.
+const (
+	OpFindVolumeGroup     Op = "FindVolumeGroup"
+	OpListVolumeGroups    Op = "ListVolumeGroups"
+	OpVGSize              Op = "VolumeGroup.Size"
+	OpVGFree              Op = "VolumeGroup.Free"
+	OpListVolumes         Op = "ListVolumes"
+	OpFindVolume          Op = "FindVolume"
+	OpCreateVolume        Op = "CreateVolume"
+	OpListPools           Op = "ListPools"
+	OpCreatePool          Op = "CreatePool"
+	OpListPhysicalVolumes Op = "ListPhysicalVolumes"
+	OpIsOpen              Op = "LogicalVolume.IsOpen"
+	OpResize              Op = "LogicalVolume.Resize"
+	OpRemove              Op = "LogicalVolume.Remove"
+	OpDiscard             Op = "LogicalVolume.Discard"
+	OpAddTags             Op = "LogicalVolume.AddTags"
+	OpDelTags             Op = "LogicalVolume.DelTags"
+	OpRename              Op = "LogicalVolume.Rename"
+	OpPoolResize          Op = "ThinPool.Resize"
+	OpCreateThinVolume    Op = "ThinPool.CreateVolume"
+	OpMove                Op = "PhysicalVolume.Move"
+	OpReduce              Op = "PhysicalVolume.Reduce"
+	OpRemovePV            Op = "PhysicalVolume.Remove"
+	OpSetAllocatable      Op = "PhysicalVolume.SetAllocatable"
+)
+
+// deviceMajor is the device major number of fake logical volumes.
+const deviceMajor = 253
+
+type failure struct {
+	err error
+	// times is the number of remaining failures.  Zero means that the failure is permanent.
+	times int
+}
+
+// Backend is an in-memory implementation of backend.Backend.
+// The zero value is not usable; use New.
+type Backend struct {
+	mu        sync.Mutex
+	vgs       []*volumeGroup
+	failures  map[Op]*failure
+	nextMinor uint32
+}
+
+var _ backend.Backend = &Backend{}
+
+// New returns an empty fake backend.
+func New() *Backend {
+	return &Backend{
+		failures: make(map[Op]*failure),
+	}
+}
+
+// AddPhysicalVolume adds a physical volume of size bytes to the volume group vgName.
+// The volume group is created if it does not exist.
+func (b *Backend) AddPhysicalVolume(vgName, pvName string, size uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	vg := b.findVG(vgName)
+	if vg == nil {
+		vg = &volumeGroup{b: b, name: vgName}
+		b.vgs = append(b.vgs, vg)
+	}
+	vg.pvs = append(vg.pvs, &physicalVolume{vg: vg, name: pvName, size: size})
+}
+
+// Allocatable returns true if new extents can be allocated on the physical volume.
+func (b *Backend) Allocatable(vgName, pvName string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	vg := b.findVG(vgName)
+	if vg == nil {
+		return false, backend.ErrNotFound
+	}
+	for _, pv := range vg.pvs {
+		if pv.name == pvName {
+			return !pv.unallocatable, nil
+		}
+	}
+	return false, backend.ErrNotFound
+}
+
+// SetOpen sets whether the logical volume is opened.
+func (b *Backend) SetOpen(vgName, lvName string, open bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	vg := b.findVG(vgName)
+	if vg == nil {
+		return backend.ErrNotFound
+	}
+	lv := vg.findLV(lvName)
+	if lv == nil {
+		return backend.ErrNotFound
+	}
+	lv.open = open
+	return nil
+}
+
+// InjectFailure makes op fail with err.  If times is positive, op fails only the next times calls.
+// Otherwise, op keeps failing until ClearFailures is called.
+func (b *Backend) InjectFailure(op Op, err error, times int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures[op] = &failure{err: err, times: times}
+}
+
+// ClearFailures removes all injected failures.
+func (b *Backend) ClearFailures() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = make(map[Op]*failure)
+}
+
+// check returns an error if ctx is done or a failure is injected into op.
+// The caller must hold b.mu.
+func (b *Backend) check(ctx context.Context, op Op) error {
+	if err := ctx.Err(); err != nil {
+		return &command.LVMError{Command: string(op), Err: err}
+	}
+	f, ok := b.failures[op]
+	if !ok {
+		return nil
+	}
+	if f.times > 0 {
+		f.times--
+		if f.times == 0 {
+			delete(b.failures, op)
+		}
+	}
+	return f.err
+}
+
+func (b *Backend) findVG(name string) *volumeGroup {
+	for _, vg := range b.vgs {
+		if vg.name == name {
+			return vg
+		}
+	}
+	return nil
+}
+
+// FindVolumeGroup implements backend.Backend.
+func (b *Backend) FindVolumeGroup(ctx context.Context, name string) (backend.VolumeGroup, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(ctx, OpFindVolumeGroup); err != nil {
+		return nil, err
+	}
+	vg := b.findVG(name)
+	if vg == nil {
+		return nil, backend.ErrNotFound
+	}
+	return vg, nil
+}
+
+// ListVolumeGroups implements backend.Backend.
+func (b *Backend) ListVolumeGroups(ctx context.Context) ([]backend.VolumeGroup, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(ctx, OpListVolumeGroups); err != nil {
+		return nil, err
+	}
+	ret := make([]backend.VolumeGroup, len(b.vgs))
+	for i, vg := range b.vgs {
+		ret[i] = vg
+	}
+	return ret, nil
+}
+
+func lvmError(cmd, format string, args ...interface{}) error {
+	return &command.LVMError{
+		Command: cmd,
+		Stderr:  fmt.Sprintf(format, args...),
+		Err:     errors.New("exit status 5"),
+	}
+}
+
+type volumeGroup struct {
+	b     *Backend
+	name  string
+	pvs   []*physicalVolume
+	lvs   []*logicalVolume
+	pools []*thinPool
+}
+
+func (g *volumeGroup) findLV(name string) *logicalVolume {
+	for _, lv := range g.lvs {
+		if lv.name == name {
+			return lv
+		}
+	}
+	return nil
+}
+
+func (g *volumeGroup) findPool(name string) *thinPool {
+	for _, pool := range g.pools {
+		if pool.name == name {
+			return pool
+		}
+	}
+	return nil
+}
+
+func (g *volumeGroup) size() uint64 {
+	var size uint64
+	for _, pv := range g.pvs {
+		size += pv.size
+	}
+	return size
+}
+
+func (g *volumeGroup) free() uint64 {
+	var free uint64
+	for _, pv := range g.pvs {
+		free += pv.free()
+	}
+	return free
+}
+
+// allocations returns the extents allocated in this volume group.
+func (g *volumeGroup) allocations() []map[string]uint64 {
+	var ret []map[string]uint64
+	for _, lv := range g.lvs {
+		if lv.pool == nil {
+			ret = append(ret, lv.extents)
+		}
+	}
+	for _, pool := range g.pools {
+		ret = append(ret, pool.extents)
+	}
+	return ret
+}
+
+// allocate allocates size bytes from allocatable physical volumes other than exclude, and records them in extents.
+func (g *volumeGroup) allocate(extents map[string]uint64, size uint64, exclude string) error {
+	var free uint64
+	for _, pv := range g.pvs {
+		if pv.name != exclude && !pv.unallocatable {
+			free += pv.free()
+		}
+	}
+	if free < size {
+		return lvmError("lvcreate", "Volume group \"%s\" has insufficient free space: %d bytes required, %d bytes available.", g.name, size, free)
+	}
+	for _, pv := range g.pvs {
+		if size == 0 {
+			break
+		}
+		if pv.name == exclude || pv.unallocatable {
+			continue
+		}
+		n := pv.free()
+		if n > size {
+			n = size
+		}
+		extents[pv.name] += n
+		size -= n
+	}
+	return nil
+}
+
+func (g *volumeGroup) Name() string {
+	return g.name
+}
+
+func (g *volumeGroup) Size(ctx context.Context) (uint64, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpVGSize); err != nil {
+		return 0, err
+	}
+	return g.size(), nil
+}
+
+func (g *volumeGroup) Free(ctx context.Context) (uint64, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpVGFree); err != nil {
+		return 0, err
+	}
+	return g.free(), nil
+}
+
+func (g *volumeGroup) FindVolume(ctx context.Context, name string) (backend.LogicalVolume, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpFindVolume); err != nil {
+		return nil, err
+	}
+	if lv := g.findLV(name); lv != nil {
+		return lv, nil
+	}
+	return nil, backend.ErrNotFound
+}
+
+func (g *volumeGroup) ListVolumes(ctx context.Context) ([]backend.LogicalVolume, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpListVolumes); err != nil {
+		return nil, err
+	}
+	ret := make([]backend.LogicalVolume, len(g.lvs))
+	for i, lv := range g.lvs {
+		ret[i] = lv
+	}
+	return ret, nil
+}
+
+func (g *volumeGroup) CreateVolume(ctx context.Context, name string, size uint64, tags []string, stripe uint, stripeSize string) (backend.LogicalVolume, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpCreateVolume); err != nil {
+		return nil, err
+	}
+	if g.findLV(name) != nil || g.findPool(name) != nil {
+		return nil, lvmError("lvcreate", "Logical Volume \"%s\" already exists in volume group \"%s\"", name, g.name)
+	}
+	if stripe > uint(len(g.pvs)) {
+		return nil, lvmError("lvcreate", "Number of stripes (%d) must not exceed number of physical volumes (%d)", stripe, len(g.pvs))
+	}
+	// the size is rounded down to GiB like command.VolumeGroup.CreateVolume
+	size = size >> 30 << 30
+	lv := &logicalVolume{
+		vg:      g,
+		name:    name,
+		size:    size,
+		tags:    append([]string(nil), tags...),
+		minor:   g.b.nextMinor,
+		extents: make(map[string]uint64),
+	}
+	if err := g.allocate(lv.extents, size, ""); err != nil {
+		return nil, err
+	}
+	g.b.nextMinor++
+	g.lvs = append(g.lvs, lv)
+	return lv, nil
+}
+
+func (g *volumeGroup) FindPool(ctx context.Context, name string) (backend.ThinPool, error) {
+	pools, err := g.ListPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pool := range pools {
+		if pool.Name() == name {
+			return pool, nil
+		}
+	}
+	return nil, backend.ErrNotFound
+}
+
+func (g *volumeGroup) ListPools(ctx context.Context) ([]backend.ThinPool, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpListPools); err != nil {
+		return nil, err
+	}
+	ret := make([]backend.ThinPool, len(g.pools))
+	for i, pool := range g.pools {
+		ret[i] = pool
+	}
+	return ret, nil
+}
+
+func (g *volumeGroup) CreatePool(ctx context.Context, name string, size uint64) (backend.ThinPool, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpCreatePool); err != nil {
+		return nil, err
+	}
+	if g.findLV(name) != nil || g.findPool(name) != nil {
+		return nil, lvmError("lvcreate", "Logical Volume \"%s\" already exists in volume group \"%s\"", name, g.name)
+	}
+	size = size >> 30 << 30
+	pool := &thinPool{
+		vg:      g,
+		name:    name,
+		size:    size,
+		extents: make(map[string]uint64),
+	}
+	if err := g.allocate(pool.extents, size, ""); err != nil {
+		return nil, err
+	}
+	g.pools = append(g.pools, pool)
+	return pool, nil
+}
+
+func (g *volumeGroup) FindPhysicalVolume(ctx context.Context, name string) (backend.PhysicalVolume, error) {
+	pvs, err := g.ListPhysicalVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pv := range pvs {
+		if pv.Name() == name {
+			return pv, nil
+		}
+	}
+	return nil, backend.ErrNotFound
+}
+
+func (g *volumeGroup) ListPhysicalVolumes(ctx context.Context) ([]backend.PhysicalVolume, error) {
+	g.b.mu.Lock()
+	defer g.b.mu.Unlock()
+	if err := g.b.check(ctx, OpListPhysicalVolumes); err != nil {
+		return nil, err
+	}
+	ret := make([]backend.PhysicalVolume, len(g.pvs))
+	for i, pv := range g.pvs {
+		// return a snapshot like command.VolumeGroup.ListPhysicalVolumes
+		ret[i] = &physicalVolumeHandle{pv: pv, size: pv.size, free: pv.free()}
+	}
+	return ret, nil
+}
+
+type logicalVolume struct {
+	vg      *volumeGroup
+	name    string
+	size    uint64
+	tags    []string
+	minor   uint32
+	open    bool
+	pool    *thinPool
+	removed bool
+	// extents maps the names of physical volumes to the bytes allocated on them.
+	extents map[string]uint64
+}
+
+func (l *logicalVolume) Name() string {
+	l.vg.b.mu.Lock()
+	defer l.vg.b.mu.Unlock()
+	return l.name
+}
+
+func (l *logicalVolume) FullName() string {
+	l.vg.b.mu.Lock()
+	defer l.vg.b.mu.Unlock()
+	return l.vg.name + "/" + l.name
+}
+
+func (l *logicalVolume) Path() string {
+	l.vg.b.mu.Lock()
+	defer l.vg.b.mu.Unlock()
+	return "/dev/" + l.vg.name + "/" + l.name
+}
+
+func (l *logicalVolume) Size() uint64 {
+	l.vg.b.mu.Lock()
+	defer l.vg.b.mu.Unlock()
+	return l.size
+}
+
+func (l *logicalVolume) IsSnapshot() bool {
+	return false
+}
+
+func (l *logicalVolume) IsThin() bool {
+	return l.pool != nil
+}
+
+func (l *logicalVolume) MajorNumber() uint32 {
+	return deviceMajor
+}
+
+func (l *logicalVolume) MinorNumber() uint32 {
+	return l.minor
+}
+
+func (l *logicalVolume) Tags() []string {
+	l.vg.b.mu.Lock()
+	defer l.vg.b.mu.Unlock()
+	return append([]string(nil), l.tags...)
+}
+
+// begin locks the backend and checks the failure of op and the existence of the volume.
+// The caller must unlock the backend if begin succeeds.
+func (l *logicalVolume) begin(ctx context.Context, op Op) error {
+	l.vg.b.mu.Lock()
+	if err := l.vg.b.check(ctx, op); err != nil {
+		l.vg.b.mu.Unlock()
+		return err
+	}
+	if l.removed {
+		l.vg.b.mu.Unlock()
+		return lvmError("lvs", "Failed to find logical volume \"%s/%s\"", l.vg.name, l.name)
+	}
+	return nil
+}
+
+func (l *logicalVolume) IsOpen(ctx context.Context) (bool, error) {
+	if err := l.begin(ctx, OpIsOpen); err != nil {
+		return false, err
+	}
+	defer l.vg.b.mu.Unlock()
+	return l.open, nil
+}
+
+func (l *logicalVolume) Resize(ctx context.Context, newSize uint64) error {
+	if err := l.begin(ctx, OpResize); err != nil {
+		return err
+	}
+	defer l.vg.b.mu.Unlock()
+	if l.size > newSize {
+		return fmt.Errorf("volume cannot be shrunk")
+	}
+	if l.size == newSize {
+		return nil
+	}
+	if l.pool == nil {
+		if err := l.vg.allocate(l.extents, newSize-l.size, ""); err != nil {
+			return err
+		}
+	}
+	l.size = newSize
+	return nil
+}
+
+func (l *logicalVolume) Remove(ctx context.Context) error {
+	if err := l.begin(ctx, OpRemove); err != nil {
+		return err
+	}
+	defer l.vg.b.mu.Unlock()
+	for i, lv := range l.vg.lvs {
+		if lv == l {
+			l.vg.lvs = append(l.vg.lvs[:i], l.vg.lvs[i+1:]...)
+			break
+		}
+	}
+	l.removed = true
+	l.extents = nil
+	return nil
+}
+
+func (l *logicalVolume) Discard(ctx context.Context, offset, length uint64, zero bool) error {
+	if err := l.begin(ctx, OpDiscard); err != nil {
+		return err
+	}
+	defer l.vg.b.mu.Unlock()
+	if offset+length > l.size {
+		return lvmError("blkdiscard", "%s: offset %d and length %d exceed the device size", l.name, offset, length)
+	}
+	return nil
+}
+
+func (l *logicalVolume) AddTags(ctx context.Context, tags ...string) error {
+	if err := l.begin(ctx, OpAddTags); err != nil {
+		return err
+	}
+	defer l.vg.b.mu.Unlock()
+	for _, tag := range tags {
+		if !containsString(l.tags, tag) {
+			l.tags = append(l.tags, tag)
+		}
+	}
+	return nil
+}
+
+func (l *logicalVolume) DelTags(ctx context.Context, tags ...string) error {
+	if err := l.begin(ctx, OpDelTags); err != nil {
+		return err
+	}
+	defer l.vg.b.mu.Unlock()
+	var remaining []string
+	for _, tag := range l.tags {
+		if !containsString(tags, tag) {
+			remaining = append(remaining, tag)
+		}
+	}
+	l.tags = remaining
+	return nil
+}
+
+func (l *logicalVolume) Rename(ctx context.Context, name string) error {
+	if err := l.begin(ctx, OpRename); err != nil {
+		return err
+	}
+	defer l.vg.b.mu.Unlock()
+	if l.vg.findLV(name) != nil || l.vg.findPool(name) != nil {
+		return lvmError("lvrename", "Logical Volume \"%s\" already exists in volume group \"%s\"", name, l.vg.name)
+	}
+	l.name = name
+	return nil
+}
+
+type thinPool struct {
+	vg      *volumeGroup
+	name    string
+	size    uint64
+	extents map[string]uint64
+}
+
+func (t *thinPool) Name() string {
+	return t.name
+}
+
+func (t *thinPool) FullName() string {
+	return t.vg.name + "/" + t.name
+}
+
+func (t *thinPool) Size() uint64 {
+	t.vg.b.mu.Lock()
+	defer t.vg.b.mu.Unlock()
+	return t.size
+}
+
+func (t *thinPool) Resize(ctx context.Context, newSize uint64) error {
+	t.vg.b.mu.Lock()
+	defer t.vg.b.mu.Unlock()
+	if err := t.vg.b.check(ctx, OpPoolResize); err != nil {
+		return err
+	}
+	if t.size >= newSize {
+		return nil
+	}
+	if err := t.vg.allocate(t.extents, newSize-t.size, ""); err != nil {
+		return err
+	}
+	t.size = newSize
+	return nil
+}
+
+func (t *thinPool) ListVolumes(ctx context.Context) ([]backend.LogicalVolume, error) {
+	t.vg.b.mu.Lock()
+	defer t.vg.b.mu.Unlock()
+	if err := t.vg.b.check(ctx, OpListVolumes); err != nil {
+		return nil, err
+	}
+	var ret []backend.LogicalVolume
+	for _, lv := range t.vg.lvs {
+		if lv.pool == t {
+			ret = append(ret, lv)
+		}
+	}
+	return ret, nil
+}
+
+func (t *thinPool) CreateVolume(ctx context.Context, name string, size uint64) (backend.LogicalVolume, error) {
+	t.vg.b.mu.Lock()
+	defer t.vg.b.mu.Unlock()
+	if err := t.vg.b.check(ctx, OpCreateThinVolume); err != nil {
+		return nil, err
+	}
+	if t.vg.findLV(name) != nil || t.vg.findPool(name) != nil {
+		return nil, lvmError("lvcreate", "Logical Volume \"%s\" already exists in volume group \"%s\"", name, t.vg.name)
+	}
+	lv := &logicalVolume{
+		vg:    t.vg,
+		name:  name,
+		size:  size >> 30 << 30,
+		minor: t.vg.b.nextMinor,
+		pool:  t,
+	}
+	t.vg.b.nextMinor++
+	t.vg.lvs = append(t.vg.lvs, lv)
+	return lv, nil
+}
+
+type physicalVolume struct {
+	vg            *volumeGroup
+	name          string
+	size          uint64
+	removed       bool
+	unallocatable bool
+}
+
+func (p *physicalVolume) used() uint64 {
+	var used uint64
+	for _, extents := range p.vg.allocations() {
+		used += extents[p.name]
+	}
+	return used
+}
+
+func (p *physicalVolume) free() uint64 {
+	return p.size - p.used()
+}
+
+// physicalVolumeHandle is a physical volume returned from ListPhysicalVolumes.
+// Size and Free are those at the time of listing.
+type physicalVolumeHandle struct {
+	pv   *physicalVolume
+	size uint64
+	free uint64
+}
+
+func (h *physicalVolumeHandle) Name() string {
+	return h.pv.name
+}
+
+func (h *physicalVolumeHandle) Size() uint64 {
+	return h.size
+}
+
+func (h *physicalVolumeHandle) Free() uint64 {
+	return h.free
+}
+
+func (h *physicalVolumeHandle) Used() uint64 {
+	return h.size - h.free
+}
+
+func (h *physicalVolumeHandle) Move(ctx context.Context, progress func(moved, total uint64) error) error {
+	b := h.pv.vg.b
+	b.mu.Lock()
+	if err := b.check(ctx, OpMove); err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	g := h.pv.vg
+	total := h.pv.used()
+	if total == 0 {
+		b.mu.Unlock()
+		return nil
+	}
+
+	// move extents one allocation at a time, reporting progress after each.
+	var moved uint64
+	var steps []uint64
+	for _, extents := range g.allocations() {
+		n := extents[h.pv.name]
+		if n == 0 {
+			continue
+		}
+		delete(extents, h.pv.name)
+		if err := g.allocate(extents, n, h.pv.name); err != nil {
+			extents[h.pv.name] = n
+			b.mu.Unlock()
+			return lvmError("pvmove", "Insufficient free space: %d extents needed", n)
+		}
+		moved += n
+		steps = append(steps, moved)
+	}
+	h.free = h.size
+	b.mu.Unlock()
+
+	if progress == nil {
+		return nil
+	}
+	for _, m := range steps {
+		if err := progress(m, total); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *physicalVolumeHandle) Reduce(ctx context.Context) error {
+	b := h.pv.vg.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(ctx, OpReduce); err != nil {
+		return err
+	}
+	g := h.pv.vg
+	if h.pv.used() != 0 {
+		return lvmError("vgreduce", "Physical volume \"%s\" still in use", h.pv.name)
+	}
+	if len(g.pvs) == 1 {
+		return lvmError("vgreduce", "Can't remove final physical volume \"%s\" from volume group \"%s\"", h.pv.name, g.name)
+	}
+	for i, pv := range g.pvs {
+		if pv == h.pv {
+			g.pvs = append(g.pvs[:i], g.pvs[i+1:]...)
+			break
+		}
+	}
+	h.pv.removed = true
+	return nil
+}
+
+func (h *physicalVolumeHandle) Remove(ctx context.Context) error {
+	b := h.pv.vg.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(ctx, OpRemovePV); err != nil {
+		return err
+	}
+	if !h.pv.removed {
+		return lvmError("pvremove", "PV %s is used by VG %s so please use vgreduce first.", h.pv.name, h.pv.vg.name)
+	}
+	return nil
+}
+
+func (h *physicalVolumeHandle) SetAllocatable(ctx context.Context, allocatable bool) error {
+	b := h.pv.vg.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(ctx, OpSetAllocatable); err != nil {
+		return err
+	}
+	h.pv.unallocatable = !allocatable
+	return nil
+}
+
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/backend"
+)
+
+func TestInjectFailure(t *testing.T) {
+	ctx := context.Background()
+	b := New()
+	b.AddPhysicalVolume("vg", "/dev/pv1", 4<<30)
+
+	errInjected := errors.New("injected")
+	b.InjectFailure(OpFindVolumeGroup, errInjected, 2)
+	for i := 0; i < 2; i++ {
+		_, err := b.FindVolumeGroup(ctx, "vg")
+		if err != errInjected {
+			t.Errorf("%d: expected %v, but actual %v", i, errInjected, err)
+		}
+	}
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.InjectFailure(OpCreateVolume, errInjected, 0)
+	for i := 0; i < 3; i++ {
+		_, err := vg.CreateVolume(ctx, "lv", 1<<30, nil, 0, "")
+		if err != errInjected {
+			t.Errorf("%d: expected %v, but actual %v", i, errInjected, err)
+		}
+	}
+	b.ClearFailures()
+	_, err = vg.CreateVolume(ctx, "lv", 1<<30, nil, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = b.FindVolumeGroup(ctx, "none")
+	if err != backend.ErrNotFound {
+		t.Errorf("expected %v, but actual %v", backend.ErrNotFound, err)
+	}
+}
+
+func TestEvictPhysicalVolume(t *testing.T) {
+	ctx := context.Background()
+	b := New()
+	b.AddPhysicalVolume("vg", "/dev/pv1", 2<<30)
+	b.AddPhysicalVolume("vg", "/dev/pv2", 2<<30)
+	b.AddPhysicalVolume("vg", "/dev/pv3", 2<<30)
+
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.CreateVolume(ctx, "lv1", 3<<30, nil, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pv, err := vg.FindPhysicalVolume(ctx, "/dev/pv1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pv.Used() != 2<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(2<<30), pv.Used())
+	}
+	if err := pv.Remove(ctx); err == nil {
+		t.Error("physical volume in a volume group must not be removed")
+	}
+
+	var moved uint64
+	err = pv.Move(ctx, func(m, total uint64) error {
+		moved = m
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 2<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(2<<30), moved)
+	}
+	if err := pv.Reduce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := pv.Remove(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pvs, err := vg.ListPhysicalVolumes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pvs) != 2 {
+		t.Fatalf("expected 2, but actual %d", len(pvs))
+	}
+	free, err := vg.Free(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if free != 1<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(1<<30), free)
+	}
+
+	// the remaining volumes cannot hold the extents of a physical volume.
+	pv, err = vg.FindPhysicalVolume(ctx, "/dev/pv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pv.Move(ctx, nil); err == nil {
+		t.Error("physical volume must not be moved without enough free space")
+	}
+}
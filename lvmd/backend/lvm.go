@@ -0,0 +1,145 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/topolvm/topolvm/lvmd/command"
+)
+
+// NewLVM returns a Backend that runs LVM commands with package command.
+func NewLVM() Backend {
+	return lvmBackend{}
+}
+
+type lvmBackend struct{}
+
+func (lvmBackend) FindVolumeGroup(ctx context.Context, name string) (VolumeGroup, error) {
+	vg, err := command.FindVolumeGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return lvmVolumeGroup{vg}, nil
+}
+
+func (lvmBackend) ListVolumeGroups(ctx context.Context) ([]VolumeGroup, error) {
+	vgs, err := command.ListVolumeGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]VolumeGroup, len(vgs))
+	for i, vg := range vgs {
+		ret[i] = lvmVolumeGroup{vg}
+	}
+	return ret, nil
+}
+
+// lvmVolumeGroup adapts *command.VolumeGroup to VolumeGroup.
+type lvmVolumeGroup struct {
+	*command.VolumeGroup
+}
+
+func (g lvmVolumeGroup) FindVolume(ctx context.Context, name string) (LogicalVolume, error) {
+	lv, err := g.VolumeGroup.FindVolume(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return lv, nil
+}
+
+func (g lvmVolumeGroup) ListVolumes(ctx context.Context) ([]LogicalVolume, error) {
+	lvs, err := g.VolumeGroup.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toLogicalVolumes(lvs), nil
+}
+
+func (g lvmVolumeGroup) CreateVolume(ctx context.Context, name string, size uint64, tags []string, stripe uint, stripeSize string) (LogicalVolume, error) {
+	lv, err := g.VolumeGroup.CreateVolume(ctx, name, size, tags, stripe, stripeSize)
+	if err != nil {
+		return nil, err
+	}
+	return lv, nil
+}
+
+func (g lvmVolumeGroup) FindPool(ctx context.Context, name string) (ThinPool, error) {
+	pool, err := g.VolumeGroup.FindPool(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return lvmThinPool{pool}, nil
+}
+
+func (g lvmVolumeGroup) ListPools(ctx context.Context) ([]ThinPool, error) {
+	pools, err := g.VolumeGroup.ListPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]ThinPool, len(pools))
+	for i, pool := range pools {
+		ret[i] = lvmThinPool{pool}
+	}
+	return ret, nil
+}
+
+func (g lvmVolumeGroup) CreatePool(ctx context.Context, name string, size uint64) (ThinPool, error) {
+	pool, err := g.VolumeGroup.CreatePool(ctx, name, size)
+	if err != nil {
+		return nil, err
+	}
+	return lvmThinPool{pool}, nil
+}
+
+func (g lvmVolumeGroup) FindPhysicalVolume(ctx context.Context, name string) (PhysicalVolume, error) {
+	pv, err := g.VolumeGroup.FindPhysicalVolume(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return pv, nil
+}
+
+func (g lvmVolumeGroup) ListPhysicalVolumes(ctx context.Context) ([]PhysicalVolume, error) {
+	pvs, err := g.VolumeGroup.ListPhysicalVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]PhysicalVolume, len(pvs))
+	for i, pv := range pvs {
+		ret[i] = pv
+	}
+	return ret, nil
+}
+
+// lvmThinPool adapts *command.ThinPool to ThinPool.
+type lvmThinPool struct {
+	*command.ThinPool
+}
+
+func (t lvmThinPool) ListVolumes(ctx context.Context) ([]LogicalVolume, error) {
+	lvs, err := t.ThinPool.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toLogicalVolumes(lvs), nil
+}
+
+func (t lvmThinPool) CreateVolume(ctx context.Context, name string, size uint64) (LogicalVolume, error) {
+	lv, err := t.ThinPool.CreateVolume(ctx, name, size)
+	if err != nil {
+		return nil, err
+	}
+	return lv, nil
+}
+
+func toLogicalVolumes(lvs []*command.LogicalVolume) []LogicalVolume {
+	ret := make([]LogicalVolume, len(lvs))
+	for i, lv := range lvs {
+		ret[i] = lv
+	}
+	return ret
+}
+
+var (
+	_ LogicalVolume  = &command.LogicalVolume{}
+	_ PhysicalVolume = &command.PhysicalVolume{}
+)
@@ -0,0 +1,221 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cybozu-go/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Timeout is the time limit of a command invoked by this package.  Zero means no limit.
+// Commands are also killed when the context passed to them is done.
+var Timeout = 2 * time.Minute
+
+// Timeouts overrides Timeout for commands keyed by their names, such as "lvcreate" or "blkdiscard".
+// Zero means no limit.
+var Timeouts = map[string]time.Duration{}
+
+// unlimitedCommands are commands that may take long and are not limited by Timeout.
+var unlimitedCommands = map[string]bool{
+	"pvmove": true,
+}
+
+// LVMError is returned when a command invoked by this package fails.
+type LVMError struct {
+	// Command is the name of the command, e.g. "lvcreate".
+	Command string
+	Args    []string
+	// Stderr is the error output of the command.
+	Stderr string
+	Err    error
+}
+
+func (e *LVMError) Error() string {
+	msg := fmt.Sprintf("%s failed: %v", e.Command, e.Err)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+func (e *LVMError) Unwrap() error {
+	return e.Err
+}
+
+// lockFailureRe matches the messages of LVM that fail to take a lock, such as
+// "Can't get lock for myvg", "Failed to lock logical volume myvg/lv", and
+// "VG myvg lock failed" of lvmlockd.  Words containing "lock" like "block" are not matched.
+var lockFailureRe = regexp.MustCompile(`\b(can't get lock|can't lock|cannot lock|failed to lock|lock failed|due to failed lock)\b`)
+
+// Code returns the gRPC code for the error.
+func (e *LVMError) Code() codes.Code {
+	switch {
+	case errors.Is(e.Err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(e.Err, context.Canceled):
+		return codes.Canceled
+	}
+
+	stderr := strings.ToLower(e.Stderr)
+	switch {
+	case strings.Contains(stderr, "insufficient free space"):
+		return codes.ResourceExhausted
+	case strings.Contains(stderr, "already exists"):
+		return codes.AlreadyExists
+	case strings.Contains(stderr, "not found"), strings.Contains(stderr, "failed to find"):
+		return codes.NotFound
+	case lockFailureRe.MatchString(stderr):
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
+
+// GRPCStatus returns the gRPC status for the error.
+// This allows the error to be returned from gRPC handlers as it is.
+func (e *LVMError) GRPCStatus() *status.Status {
+	return status.New(e.Code(), e.Error())
+}
+
+// commandName returns the name of the command used in errors, logs and metrics.
+func commandName(cmd string, args []string) string {
+	if cmd == lvm && len(args) > 0 {
+		return args[0]
+	}
+	return path.Base(cmd)
+}
+
+// wrapExecCommand calls cmd with args but wrapped to run
+// on the host
+func wrapExecCommand(ctx context.Context, cmd string, args ...string) *exec.Cmd {
+	if Containerized {
+		args = append([]string{"-m", "-u", "-i", "-n", "-p", "-t", "1", cmd}, args...)
+		cmd = nsenter
+	}
+	c := exec.CommandContext(ctx, cmd, args...)
+	return c
+}
+
+// withTimeout returns a context limited by Timeouts or Timeout for the command.
+func withTimeout(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	timeout, ok := Timeouts[name]
+	if !ok {
+		if unlimitedCommands[name] {
+			return context.WithCancel(ctx)
+		}
+		timeout = Timeout
+	}
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// execution is a running command with its error output captured.
+type execution struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	name   string
+	args   []string
+	cmd    *exec.Cmd
+	stderr bytes.Buffer
+	start  time.Time
+}
+
+// newExecution prepares cmd with args to run on the host.
+func newExecution(ctx context.Context, cmd string, args ...string) *execution {
+	name := commandName(cmd, args)
+	ctx, cancel := withTimeout(ctx, name)
+	e := &execution{
+		ctx:    ctx,
+		cancel: cancel,
+		name:   name,
+		args:   args,
+		cmd:    wrapExecCommand(ctx, cmd, args...),
+	}
+	e.cmd.Stderr = &e.stderr
+	return e
+}
+
+// run runs the command and returns the standard output.
+func (e *execution) run() ([]byte, error) {
+	var stdout bytes.Buffer
+	e.cmd.Stdout = &stdout
+	e.start = time.Now()
+	err := e.finish(e.cmd.Run())
+	return stdout.Bytes(), err
+}
+
+// startPipe starts the command and returns a reader of the standard output.
+// finish must be called after the output is read.
+func (e *execution) startPipe() (io.Reader, error) {
+	stdout, err := e.cmd.StdoutPipe()
+	if err != nil {
+		e.cancel()
+		return nil, err
+	}
+	e.start = time.Now()
+	if err := e.cmd.Start(); err != nil {
+		return nil, e.finish(err)
+	}
+	return stdout, nil
+}
+
+// wait waits for the command started by startPipe.
+func (e *execution) wait() error {
+	return e.finish(e.cmd.Wait())
+}
+
+// kill kills the command started by startPipe and waits for it.
+func (e *execution) kill() {
+	e.cancel()
+	e.cmd.Wait()
+}
+
+func (e *execution) finish(err error) error {
+	defer e.cancel()
+	stderr := strings.TrimSpace(e.stderr.String())
+	if err != nil && e.ctx.Err() != nil {
+		// the command was killed because the context is done.
+		err = e.ctx.Err()
+	}
+	observeCommand(e.name, time.Since(e.start), err)
+
+	if err != nil {
+		return &LVMError{
+			Command: e.name,
+			Args:    e.args,
+			Stderr:  stderr,
+			Err:     err,
+		}
+	}
+	if stderr != "" {
+		log.Warn("command succeeded with warnings", map[string]interface{}{
+			"command": e.name,
+			"args":    e.args,
+			"stderr":  stderr,
+		})
+	}
+	return nil
+}
+
+// CallLVM calls lvm sub-commands.
+// cmd is a name of sub-command.
+// The error output of the command is returned as a part of *LVMError.
+func CallLVM(ctx context.Context, cmd string, args ...string) error {
+	args = append([]string{cmd}, args...)
+	log.Info("invoking LVM command", map[string]interface{}{
+		"args": args,
+	})
+	_, err := newExecution(ctx, lvm, args...).run()
+	return err
+}
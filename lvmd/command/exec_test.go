@@ -0,0 +1,115 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLVMErrorCode(t *testing.T) {
+	exitErr := errors.New("exit status 5")
+	testCases := []struct {
+		err  *LVMError
+		code codes.Code
+	}{
+		{&LVMError{Command: "lvcreate", Err: context.DeadlineExceeded}, codes.DeadlineExceeded},
+		{&LVMError{Command: "lvcreate", Err: context.Canceled}, codes.Canceled},
+		{&LVMError{Command: "lvcreate", Err: exitErr,
+			Stderr: `Volume group "myvg" has insufficient free space (10 extents): 256 required.`}, codes.ResourceExhausted},
+		{&LVMError{Command: "lvcreate", Err: exitErr,
+			Stderr: `Logical Volume "vol1" already exists in volume group "myvg"`}, codes.AlreadyExists},
+		{&LVMError{Command: "lvs", Err: exitErr,
+			Stderr: `Volume group "myvg" not found`}, codes.NotFound},
+		{&LVMError{Command: "lvremove", Err: exitErr,
+			Stderr: `Failed to find logical volume "myvg/vol1"`}, codes.NotFound},
+		{&LVMError{Command: "lvcreate", Err: exitErr,
+			Stderr: `Can't get lock for myvg.`}, codes.Unavailable},
+		{&LVMError{Command: "lvchange", Err: exitErr,
+			Stderr: `Failed to lock logical volume myvg/vol1.`}, codes.Unavailable},
+		{&LVMError{Command: "lvcreate", Err: exitErr,
+			Stderr: `VG myvg lock failed: lock manager is not running.`}, codes.Unavailable},
+		{&LVMError{Command: "lvcreate", Err: exitErr,
+			Stderr: `Cannot access VG myvg due to failed lock.`}, codes.Unavailable},
+		{&LVMError{Command: "lvcreate", Err: exitErr,
+			Stderr: `Device /dev/sdb excluded by a filter: not a block device.`}, codes.Internal},
+		{&LVMError{Command: "pvmove", Err: exitErr,
+			Stderr: `Failed to allocate block of 256 extents.`}, codes.Internal},
+		{&LVMError{Command: "lvcreate", Err: exitErr}, codes.Internal},
+	}
+	for _, tc := range testCases {
+		if code := tc.err.Code(); code != tc.code {
+			t.Errorf("%q: expected %v, but actual %v", tc.err.Stderr, tc.code, code)
+		}
+		if code := status.Code(tc.err); code != tc.code {
+			t.Errorf("%q: status.Code should be %v, but actual %v", tc.err.Stderr, tc.code, code)
+		}
+	}
+}
+
+func TestExecution(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not found")
+	}
+	ctx := context.Background()
+
+	out, err := newExecution(ctx, sh, "-c", "echo hello; echo warning >&2").run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "hello\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	_, err = newExecution(ctx, sh, "-c", "echo boom >&2; exit 3").run()
+	var lvmErr *LVMError
+	if !errors.As(err, &lvmErr) {
+		t.Fatalf("expected *LVMError, but actual %#v", err)
+	}
+	if lvmErr.Command != "sh" || lvmErr.Stderr != "boom" {
+		t.Errorf("unexpected error: %#v", lvmErr)
+	}
+
+	orig := Timeout
+	Timeout = 100 * time.Millisecond
+	defer func() { Timeout = orig }()
+
+	start := time.Now()
+	_, err = newExecution(ctx, sh, "-c", "exec sleep 10").run()
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, but actual %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("command was not killed on timeout")
+	}
+
+	origTimeouts := Timeouts
+	Timeouts = map[string]time.Duration{"sh": 0}
+	defer func() { Timeouts = origTimeouts }()
+	if _, err := newExecution(ctx, sh, "-c", "sleep 0.3").run(); err != nil {
+		t.Errorf("expected the override to disable the timeout, but actual %v", err)
+	}
+
+	Timeout = 0
+	Timeouts = map[string]time.Duration{"sh": 100 * time.Millisecond}
+	start = time.Now()
+	_, err = newExecution(ctx, sh, "-c", "exec sleep 10").run()
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded by the override, but actual %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("command was not killed on the overridden timeout")
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = newExecution(cctx, sh, "-c", "exit 0").run()
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("expected Canceled, but actual %v", err)
+	}
+}
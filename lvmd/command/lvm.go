@@ -1,25 +1,26 @@
 package command
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
-	"os/exec"
 	"path"
+	"regexp"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/cybozu-go/log"
 )
 
 const (
-	nsenter  = "/usr/bin/nsenter"
-	lvm      = "/sbin/lvm"
-	blockdev = "/sbin/blockdev"
-	cowMin   = 50
-	cowMax   = 300
+	nsenter    = "/usr/bin/nsenter"
+	lvm        = "/sbin/lvm"
+	blockdev   = "/sbin/blockdev"
+	blkdiscard = "/sbin/blkdiscard"
+	cowMin     = 50
+	cowMax     = 300
 )
 
 var Containerized bool = false
@@ -27,97 +28,6 @@ var Containerized bool = false
 // ErrNotFound is returned when a VG or LV is not found.
 var ErrNotFound = errors.New("not found")
 
-// wrapExecCommand calls cmd with args but wrapped to run
-// on the host
-func wrapExecCommand(cmd string, args ...string) *exec.Cmd {
-	if Containerized {
-		args = append([]string{"-m", "-u", "-i", "-n", "-p", "-t", "1", cmd}, args...)
-		cmd = nsenter
-	}
-	c := exec.Command(cmd, args...)
-	return c
-}
-
-// CallLVM calls lvm sub-commands.
-// cmd is a name of sub-command.
-func CallLVM(cmd string, args ...string) error {
-	args = append([]string{cmd}, args...)
-	c := wrapExecCommand(lvm, args...)
-	log.Info("invoking LVM command", map[string]interface{}{
-		"args": args,
-	})
-	c.Stderr = os.Stderr
-	return c.Run()
-}
-
-// LVInfo is a map of lv attributes to values.
-type LVInfo map[string]string
-
-func parseOneLine(line string) LVInfo {
-	ret := LVInfo{}
-	line = strings.TrimSpace(line)
-	for _, token := range strings.Split(line, " ") {
-		if len(token) == 0 {
-			continue
-		}
-		// assume token is "k=v"
-		kv := strings.Split(token, "=")
-		k, v := kv[0], kv[1]
-		// k[5:] removes "LVM2_" prefix.
-		k = strings.ToLower(k[5:])
-		// assume v is 'some-value'
-		v = strings.Trim(v, "'")
-		ret[k] = v
-	}
-	return ret
-}
-
-// parseLines parses output from lvm.
-func parseLines(output string) []LVInfo {
-	ret := []LVInfo{}
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if len(line) == 0 {
-			continue
-		}
-		info := parseOneLine(line)
-		ret = append(ret, info)
-	}
-	return ret
-}
-
-// parseOutput calls lvm family and parses output from it.
-//
-// cmd is a command name of lvm family.
-// fields are comma separated field names.
-// args is optional arguments for lvm command.
-func parseOutput(cmd, fields string, args ...string) ([]LVInfo, error) {
-	arg := []string{
-		cmd, "-o", fields,
-		"--noheadings", "--separator= ",
-		"--units=b", "--nosuffix",
-		"--unbuffered", "--nameprefixes",
-	}
-	arg = append(arg, args...)
-	c := wrapExecCommand(lvm, arg...)
-	c.Stderr = os.Stderr
-	stdout, err := c.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-	if err := c.Start(); err != nil {
-		return nil, err
-	}
-	out, err := io.ReadAll(stdout)
-	if err != nil {
-		return nil, err
-	}
-	if err := c.Wait(); err != nil {
-		return nil, err
-	}
-	return parseLines(string(out)), nil
-}
-
 // VolumeGroup represents a volume group of linux lvm.
 type VolumeGroup struct {
 	name string
@@ -129,57 +39,48 @@ func (g *VolumeGroup) Name() string {
 }
 
 // Size returns the capacity of the volume group in bytes.
-func (g *VolumeGroup) Size() (uint64, error) {
-	infoList, err := parseOutput("vgs", "vg_size", g.name)
-	if err != nil {
-		return 0, err
-	}
-
-	if len(infoList) != 1 {
-		return 0, errors.New("volume group not found: " + g.name)
-	}
-
-	info := infoList[0]
-	vgSize, err := strconv.ParseUint(info["vg_size"], 10, 64)
+func (g *VolumeGroup) Size(ctx context.Context) (uint64, error) {
+	r, err := g.record(ctx)
 	if err != nil {
 		return 0, err
 	}
-	return vgSize, nil
+	return r.size, nil
 }
 
 // Free returns the free space of the volume group in bytes.
-func (g *VolumeGroup) Free() (uint64, error) {
-	infoList, err := parseOutput("vgs", "vg_free", g.name)
+func (g *VolumeGroup) Free(ctx context.Context) (uint64, error) {
+	r, err := g.record(ctx)
 	if err != nil {
 		return 0, err
 	}
+	return r.free, nil
+}
 
-	if len(infoList) != 1 {
-		return 0, errors.New("volume group not found: " + g.name)
-	}
-
-	info := infoList[0]
-	vgFree, err := strconv.ParseUint(info["vg_free"], 10, 64)
+func (g *VolumeGroup) record(ctx context.Context) (vgRecord, error) {
+	records, err := listVGRecords(ctx, g.name)
 	if err != nil {
-		return 0, err
+		return vgRecord{}, err
+	}
+	if len(records) != 1 {
+		return vgRecord{}, errors.New("volume group not found: " + g.name)
 	}
-	return vgFree, nil
+	return records[0], nil
 }
 
 // CreateVolumeGroup calls "vgcreate" to create a volume group.
 // name is for creating volume name. device is path to a PV.
-func CreateVolumeGroup(name, device string) (*VolumeGroup, error) {
-	err := CallLVM("vgcreate", "-ff", "-y", name, device)
+func CreateVolumeGroup(ctx context.Context, name, device string) (*VolumeGroup, error) {
+	err := CallLVM(ctx, "vgcreate", "-ff", "-y", name, device)
 	if err != nil {
 		return nil, err
 	}
-	return FindVolumeGroup(name)
+	return FindVolumeGroup(ctx, name)
 }
 
 // FindVolumeGroup finds a named volume group.
 // name is volume group name to look up.
-func FindVolumeGroup(name string) (*VolumeGroup, error) {
-	groups, err := ListVolumeGroups()
+func FindVolumeGroup(ctx context.Context, name string) (*VolumeGroup, error) {
+	groups, err := ListVolumeGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -192,25 +93,27 @@ func FindVolumeGroup(name string) (*VolumeGroup, error) {
 }
 
 // ListVolumeGroups lists all volume groups.
-func ListVolumeGroups() ([]*VolumeGroup, error) {
-	infoList, err := parseOutput("vgs", "vg_name")
+func ListVolumeGroups(ctx context.Context) ([]*VolumeGroup, error) {
+	records, err := listVGRecords(ctx)
 	if err != nil {
 		return nil, err
 	}
 	groups := []*VolumeGroup{}
-	for _, info := range infoList {
-		groups = append(groups, &VolumeGroup{info["vg_name"]})
+	for _, r := range records {
+		groups = append(groups, &VolumeGroup{r.name})
 	}
 	return groups, nil
 }
 
 // FindVolume finds a named logical volume in this volume group.
-func (g *VolumeGroup) FindVolume(name string) (*LogicalVolume, error) {
-	volumes, err := g.ListVolumes()
+//
+// Only the named volume is reported by lvs, so this does not scan all volumes in the group.
+func (g *VolumeGroup) FindVolume(ctx context.Context, name string) (*LogicalVolume, error) {
+	records, err := listLVRecords(ctx, "--select", "lv_name="+name, g.Name())
 	if err != nil {
 		return nil, err
 	}
-	for _, volume := range volumes {
+	for _, volume := range g.volumes(records) {
 		if volume.Name() == name {
 			return volume, nil
 		}
@@ -219,67 +122,61 @@ func (g *VolumeGroup) FindVolume(name string) (*LogicalVolume, error) {
 }
 
 // ListVolumes lists all logical volumes in this volume group.
-func (g *VolumeGroup) ListVolumes() ([]*LogicalVolume, error) {
-	infoList, err := parseOutput(
-		"lvs",
-		"lv_name,lv_path,lv_size,lv_kernel_major,lv_kernel_minor,origin,origin_size,pool_lv,thin_count,lv_tags",
-		g.Name())
+func (g *VolumeGroup) ListVolumes(ctx context.Context) ([]*LogicalVolume, error) {
+	records, err := listLVRecords(ctx, g.Name())
 	if err != nil {
 		return nil, err
 	}
+	return g.volumes(records), nil
+}
+
+// volumes returns the logical volumes in records except for thin pools.
+func (g *VolumeGroup) volumes(records []lvRecord) []*LogicalVolume {
 	var ret []*LogicalVolume
 	lvNameSet := make(map[string]struct{})
-	for _, info := range infoList {
-		if len(info["thin_count"]) > 0 {
+	for _, r := range records {
+		if r.isPool {
 			continue
 		}
 		// Avoid listing duplicate LVs divided with segments
-		if _, ok := lvNameSet[info["lv_name"]]; ok {
+		if _, ok := lvNameSet[r.name]; ok {
 			continue
 		}
-		lvNameSet[info["lv_name"]] = struct{}{}
-		size, err := strconv.ParseUint(info["lv_size"], 10, 64)
-		if err != nil {
-			return nil, err
-		}
+		lvNameSet[r.name] = struct{}{}
+		size := r.size
 		var origin *string
-		if len(info["origin"]) > 0 {
-			originName := info["origin"]
+		if len(r.origin) > 0 {
+			originName := r.origin
 			origin = &originName
 		}
 		var pool *string
-		if len(info["pool_lv"]) > 0 {
-			poolLv := info["pool_lv"]
+		if len(r.pool) > 0 {
+			poolLv := r.pool
 			pool = &poolLv
 		}
 		if origin != nil && pool == nil {
 			// this volume is a snapshot, but not a thin volume.
-			size, err = strconv.ParseUint(info["origin_size"], 10, 64)
-			if err != nil {
-				return nil, err
-			}
+			size = r.originSize
 		}
-		major, _ := strconv.ParseUint(info["lv_kernel_major"], 10, 32)
-		minor, _ := strconv.ParseUint(info["lv_kernel_minor"], 10, 32)
 		ret = append(ret, newLogicalVolume(
-			info["lv_name"],
-			info["lv_path"],
+			r.name,
+			r.path,
 			g,
 			size,
 			origin,
 			pool,
-			uint32(major),
-			uint32(minor),
-			strings.Split(info["lv_tags"], ","),
+			r.major,
+			r.minor,
+			r.tags,
 		))
 	}
-	return ret, nil
+	return ret
 }
 
 // CreateVolume creates logical volume in this volume group.
 // name is a name of creating volume. size is volume size in bytes. volTags is a
 // list of tags to add to the volume.
-func (g *VolumeGroup) CreateVolume(name string, size uint64, tags []string, stripe uint, stripeSize string) (*LogicalVolume, error) {
+func (g *VolumeGroup) CreateVolume(ctx context.Context, name string, size uint64, tags []string, stripe uint, stripeSize string) (*LogicalVolume, error) {
 	lvcreateArgs := []string{"-n", name, "-L", fmt.Sprintf("%vg", size>>30), "-W", "y", "-y"}
 	for _, tag := range tags {
 		lvcreateArgs = append(lvcreateArgs, "--addtag")
@@ -294,15 +191,50 @@ func (g *VolumeGroup) CreateVolume(name string, size uint64, tags []string, stri
 	}
 	lvcreateArgs = append(lvcreateArgs, g.Name())
 
-	if err := CallLVM("lvcreate", lvcreateArgs...); err != nil {
+	if err := CallLVM(ctx, "lvcreate", lvcreateArgs...); err != nil {
+		return nil, err
+	}
+	return g.FindVolume(ctx, name)
+}
+
+// FindPhysicalVolume finds a named physical volume in this volume group.
+func (g *VolumeGroup) FindPhysicalVolume(ctx context.Context, name string) (*PhysicalVolume, error) {
+	pvs, err := g.ListPhysicalVolumes(ctx)
+	if err != nil {
 		return nil, err
 	}
-	return g.FindVolume(name)
+	for _, pv := range pvs {
+		if pv.Name() == name {
+			return pv, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ListPhysicalVolumes lists all physical volumes in this volume group.
+func (g *VolumeGroup) ListPhysicalVolumes(ctx context.Context) ([]*PhysicalVolume, error) {
+	records, err := listPVRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*PhysicalVolume
+	for _, r := range records {
+		if r.vg != g.name {
+			continue
+		}
+		ret = append(ret, &PhysicalVolume{
+			name: r.name,
+			vg:   g,
+			size: r.size,
+			free: r.free,
+		})
+	}
+	return ret, nil
 }
 
 // FindPool finds a named thin pool in this volume group.
-func (g *VolumeGroup) FindPool(name string) (*ThinPool, error) {
-	pools, err := g.ListPools()
+func (g *VolumeGroup) FindPool(ctx context.Context, name string) (*ThinPool, error) {
+	pools, err := g.ListPools(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -315,32 +247,140 @@ func (g *VolumeGroup) FindPool(name string) (*ThinPool, error) {
 }
 
 // ListPools lists all thin pool volumes in this volume group.
-func (g *VolumeGroup) ListPools() ([]*ThinPool, error) {
-	infoList, err := parseOutput("lvs", "lv_name,lv_size,thin_count", g.Name())
+func (g *VolumeGroup) ListPools(ctx context.Context) ([]*ThinPool, error) {
+	records, err := listLVRecords(ctx, g.Name())
 	if err != nil {
 		return nil, err
 	}
 	ret := []*ThinPool{}
-	for _, info := range infoList {
-		if len(info["thin_count"]) == 0 {
+	for _, r := range records {
+		if !r.isPool {
 			continue
 		}
-		lvSize, err := strconv.ParseUint(info["lv_size"], 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		ret = append(ret, newThinPool(info["lv_name"], g, lvSize))
+		ret = append(ret, newThinPool(r.name, g, r.size))
 	}
 	return ret, nil
 }
 
 // CreatePool creates a pool for thin-provisioning volumes.
-func (g *VolumeGroup) CreatePool(name string, size uint64) (*ThinPool, error) {
-	if err := CallLVM("lvcreate", "-T", fmt.Sprintf("%v/%v", g.Name(), name),
+func (g *VolumeGroup) CreatePool(ctx context.Context, name string, size uint64) (*ThinPool, error) {
+	if err := CallLVM(ctx, "lvcreate", "-T", fmt.Sprintf("%v/%v", g.Name(), name),
 		"--size", fmt.Sprintf("%vg", size>>30)); err != nil {
 		return nil, err
 	}
-	return g.FindPool(name)
+	return g.FindPool(ctx, name)
+}
+
+// pvmoveInterval is the interval in seconds at which pvmove reports the progress.
+const pvmoveInterval = "5"
+
+// pvmoveProgressRe matches progress lines of pvmove such as "/dev/sdb: Moved: 12.50%".
+var pvmoveProgressRe = regexp.MustCompile(`Moved:\s*([0-9.]+)%`)
+
+// parsePVMoveProgress returns the percentage of moved extents in a line of pvmove output.
+func parsePVMoveProgress(line string) (float64, bool) {
+	m := pvmoveProgressRe.FindStringSubmatch(line)
+	if m == nil {
+		return 0, false
+	}
+	percent, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return percent, true
+}
+
+// PhysicalVolume represents a physical volume of a volume group.
+type PhysicalVolume struct {
+	name string
+	vg   *VolumeGroup
+	size uint64
+	free uint64
+}
+
+// Name returns the physical volume name, i.e. the device path.
+func (p *PhysicalVolume) Name() string {
+	return p.name
+}
+
+// VG returns a volume group in which the physical volume is.
+func (p *PhysicalVolume) VG() *VolumeGroup {
+	return p.vg
+}
+
+// Size returns the size of the physical volume in bytes.
+func (p *PhysicalVolume) Size() uint64 {
+	return p.size
+}
+
+// Free returns the free space of the physical volume in bytes.
+func (p *PhysicalVolume) Free() uint64 {
+	return p.free
+}
+
+// Used returns the allocated space of the physical volume in bytes.
+func (p *PhysicalVolume) Used() uint64 {
+	return p.size - p.free
+}
+
+// Move calls "pvmove" to move all allocated extents to the other physical volumes of the volume group.
+// progress is called periodically with the number of moved bytes and the total bytes.
+// If progress returns an error or ctx is done, Move kills "pvmove" and returns the error.
+// Extents already moved stay on the other physical volumes, and LVM records the unfinished
+// move in the metadata, so it can be resumed by calling Move again or aborted by "pvmove --abort".
+// Move is not limited by Timeout.
+func (p *PhysicalVolume) Move(ctx context.Context, progress func(moved, total uint64) error) error {
+	total := p.Used()
+	if total == 0 {
+		return nil
+	}
+
+	args := []string{"pvmove", "-i", pvmoveInterval, p.name}
+	log.Info("invoking LVM command", map[string]interface{}{
+		"args": args,
+	})
+	e := newExecution(ctx, lvm, args...)
+	stdout, err := e.startPipe()
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		percent, ok := parsePVMoveProgress(scanner.Text())
+		if !ok || progress == nil {
+			continue
+		}
+		if err := progress(uint64(float64(total)*percent/100), total); err != nil {
+			e.kill()
+			return err
+		}
+	}
+	if err := e.wait(); err != nil {
+		return err
+	}
+	p.free = p.size
+	return nil
+}
+
+// SetAllocatable calls "pvchange -x" to allow or disallow allocating new extents on this physical volume.
+func (p *PhysicalVolume) SetAllocatable(ctx context.Context, allocatable bool) error {
+	flag := "n"
+	if allocatable {
+		flag = "y"
+	}
+	return CallLVM(ctx, "pvchange", "-x", flag, p.name)
+}
+
+// Reduce calls "vgreduce" to remove this physical volume from the volume group.
+func (p *PhysicalVolume) Reduce(ctx context.Context) error {
+	return CallLVM(ctx, "vgreduce", p.vg.Name(), p.name)
+}
+
+// Remove calls "pvremove" to wipe the LVM label of this physical volume.
+// The physical volume must have been removed from the volume group.
+func (p *PhysicalVolume) Remove(ctx context.Context) error {
+	return CallLVM(ctx, "pvremove", p.name)
 }
 
 // ThinPool represents a lvm thin pool.
@@ -386,11 +426,11 @@ func (t *ThinPool) Size() uint64 {
 }
 
 // Resize the thin pool capacity.
-func (t *ThinPool) Resize(newSize uint64) error {
+func (t *ThinPool) Resize(ctx context.Context, newSize uint64) error {
 	if t.size == newSize {
 		return nil
 	}
-	if err := CallLVM("lvresize", "-f", "-L", fmt.Sprintf("%vb", newSize), t.fullname); err != nil {
+	if err := CallLVM(ctx, "lvresize", "-f", "-L", fmt.Sprintf("%vb", newSize), t.fullname); err != nil {
 		return err
 	}
 	t.size = newSize
@@ -398,8 +438,8 @@ func (t *ThinPool) Resize(newSize uint64) error {
 }
 
 // ListVolumes lists all volumes in this thin pool.
-func (t *ThinPool) ListVolumes() ([]*LogicalVolume, error) {
-	volumes, err := t.vg.ListVolumes()
+func (t *ThinPool) ListVolumes(ctx context.Context) ([]*LogicalVolume, error) {
+	volumes, err := t.vg.ListVolumes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -413,11 +453,11 @@ func (t *ThinPool) ListVolumes() ([]*LogicalVolume, error) {
 }
 
 // CreateVolume creates a thin volume from this pool.
-func (t *ThinPool) CreateVolume(name string, size uint64) (*LogicalVolume, error) {
-	if err := CallLVM("lvcreate", "-T", t.fullname, "-n", name, "-V", fmt.Sprintf("%vg", size>>30)); err != nil {
+func (t *ThinPool) CreateVolume(ctx context.Context, name string, size uint64) (*LogicalVolume, error) {
+	if err := CallLVM(ctx, "lvcreate", "-T", t.fullname, "-n", name, "-V", fmt.Sprintf("%vg", size>>30)); err != nil {
 		return nil, err
 	}
-	return t.vg.FindVolume(name)
+	return t.vg.FindVolume(ctx, name)
 }
 
 // LogicalVolume represents a logical volume.
@@ -482,11 +522,11 @@ func (l *LogicalVolume) IsSnapshot() bool {
 }
 
 // Origin returns logical volume instance if this is a snapshot, or nil if not.
-func (l *LogicalVolume) Origin() (*LogicalVolume, error) {
+func (l *LogicalVolume) Origin(ctx context.Context) (*LogicalVolume, error) {
 	if l.origin == nil {
 		return nil, nil
 	}
-	return l.vg.FindVolume(*l.origin)
+	return l.vg.FindVolume(ctx, *l.origin)
 }
 
 // IsThin checks if the volume is thin volume or not.
@@ -495,11 +535,11 @@ func (l *LogicalVolume) IsThin() bool {
 }
 
 // Pool returns thin pool if this is a thin pool, or nil if not.
-func (l *LogicalVolume) Pool() (*ThinPool, error) {
+func (l *LogicalVolume) Pool(ctx context.Context) (*ThinPool, error) {
 	if l.pool == nil {
 		return nil, nil
 	}
-	return l.vg.FindPool(*l.pool)
+	return l.vg.FindPool(ctx, *l.pool)
 }
 
 // MajorNumber returns the device major number.
@@ -517,12 +557,24 @@ func (l *LogicalVolume) Tags() []string {
 	return l.tags
 }
 
+// IsOpen returns true if the device of this volume is opened, e.g. mounted.
+func (l *LogicalVolume) IsOpen(ctx context.Context) (bool, error) {
+	records, err := listLVRecords(ctx, l.fullname)
+	if err != nil {
+		return false, err
+	}
+	if len(records) == 0 {
+		return false, ErrNotFound
+	}
+	return records[0].open, nil
+}
+
 // Snapshot takes a snapshot of this volume.
 //
 // If this is a thin-provisioning volume, snapshots can be
 // created unconditionally.  Else, snapshots can be created
 // only for non-snapshot volumes.
-func (l *LogicalVolume) Snapshot(name string, cowSize uint64) (*LogicalVolume, error) {
+func (l *LogicalVolume) Snapshot(ctx context.Context, name string, cowSize uint64) (*LogicalVolume, error) {
 	if l.pool == nil {
 		if l.IsSnapshot() {
 			return nil, fmt.Errorf("snapshot of snapshot")
@@ -542,17 +594,17 @@ func (l *LogicalVolume) Snapshot(name string, cowSize uint64) (*LogicalVolume, e
 		if l.size < (gbSize << 30) {
 			gbSize = (l.size >> 30) << 30
 		}
-		if err := CallLVM("lvcreate", "-s", "-n", name, "-L", fmt.Sprintf("%vg", gbSize), l.path); err != nil {
+		if err := CallLVM(ctx, "lvcreate", "-s", "-n", name, "-L", fmt.Sprintf("%vg", gbSize), l.path); err != nil {
 			return nil, err
 		}
 
 		time.Sleep(2 * time.Second)
-		snapLV, err := l.vg.FindVolume(name)
+		snapLV, err := l.vg.FindVolume(ctx, name)
 		if err != nil {
 			return nil, err
 		}
 		// without this, wrong data may read from the snapshot.
-		if err := wrapExecCommand(blockdev, "--flushbufs", snapLV.path).Run(); err != nil {
+		if _, err := newExecution(ctx, blockdev, "--flushbufs", snapLV.path).run(); err != nil {
 			return nil, err
 		}
 		return snapLV, nil
@@ -564,22 +616,22 @@ func (l *LogicalVolume) Snapshot(name string, cowSize uint64) (*LogicalVolume, e
 	} else {
 		lvcreateArgs = []string{"-s", "-k", "n", "-n", name, l.fullname}
 	}
-	if err := CallLVM("lvcreate", lvcreateArgs...); err != nil {
+	if err := CallLVM(ctx, "lvcreate", lvcreateArgs...); err != nil {
 		return nil, err
 	}
-	return l.vg.FindVolume(name)
+	return l.vg.FindVolume(ctx, name)
 }
 
 // Resize this volume.
 // newSize is a new size of this volume in bytes.
-func (l *LogicalVolume) Resize(newSize uint64) error {
+func (l *LogicalVolume) Resize(ctx context.Context, newSize uint64) error {
 	if l.size > newSize {
 		return fmt.Errorf("volume cannot be shrunk")
 	}
 	if l.size == newSize {
 		return nil
 	}
-	if err := CallLVM("lvresize", "-L", fmt.Sprintf("%vb", newSize), l.fullname); err != nil {
+	if err := CallLVM(ctx, "lvresize", "-L", fmt.Sprintf("%vb", newSize), l.fullname); err != nil {
 		return err
 	}
 	l.size = newSize
@@ -587,14 +639,73 @@ func (l *LogicalVolume) Resize(newSize uint64) error {
 }
 
 // Remove this volume.
-func (l *LogicalVolume) Remove() error {
-	return CallLVM("lvremove", "-f", l.path)
+func (l *LogicalVolume) Remove(ctx context.Context) error {
+	return CallLVM(ctx, "lvremove", "-f", l.path)
+}
+
+// Discard discards the range of this volume starting at offset for length bytes.
+// If zero is true, the range is filled with zeroes instead.
+func (l *LogicalVolume) Discard(ctx context.Context, offset, length uint64, zero bool) error {
+	args := []string{"-o", strconv.FormatUint(offset, 10), "-l", strconv.FormatUint(length, 10)}
+	if zero {
+		args = append(args, "-z")
+	}
+	args = append(args, l.path)
+	_, err := newExecution(ctx, blkdiscard, args...).run()
+	return err
+}
+
+// AddTags adds tags to this volume.
+// This method also updates Tags().
+func (l *LogicalVolume) AddTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	var args []string
+	for _, tag := range tags {
+		args = append(args, "--addtag", tag)
+	}
+	args = append(args, l.fullname)
+	if err := CallLVM(ctx, "lvchange", args...); err != nil {
+		return err
+	}
+	l.tags = append(l.tags, tags...)
+	return nil
+}
+
+// DelTags removes tags from this volume.
+// This method also updates Tags().
+func (l *LogicalVolume) DelTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	var args []string
+	for _, tag := range tags {
+		args = append(args, "--deltag", tag)
+	}
+	args = append(args, l.fullname)
+	if err := CallLVM(ctx, "lvchange", args...); err != nil {
+		return err
+	}
+
+	removed := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		removed[tag] = true
+	}
+	var remaining []string
+	for _, tag := range l.tags {
+		if !removed[tag] {
+			remaining = append(remaining, tag)
+		}
+	}
+	l.tags = remaining
+	return nil
 }
 
 // Rename this volume.
 // This method also updates properties such as Name() or Path().
-func (l *LogicalVolume) Rename(name string) error {
-	if err := CallLVM("lvrename", l.vg.Name(), l.name, name); err != nil {
+func (l *LogicalVolume) Rename(ctx context.Context, name string) error {
+	if err := CallLVM(ctx, "lvrename", l.vg.Name(), l.name, name); err != nil {
 		return err
 	}
 	l.fullname = fullName(name, l.vg)
@@ -0,0 +1,27 @@
+package command
+
+import "testing"
+
+func TestParsePVMoveProgress(t *testing.T) {
+	testCases := []struct {
+		line    string
+		percent float64
+		ok      bool
+	}{
+		{"  /dev/sdb: Moved: 12.50%", 12.5, true},
+		{"  /dev/sdb: Moved: 100.00%", 100, true},
+		{"  /dev/loop1: Moved:0.00%", 0, true},
+		{"  No data to move for myvg.", 0, false},
+		{"", 0, false},
+	}
+	for _, tc := range testCases {
+		percent, ok := parsePVMoveProgress(tc.line)
+		if ok != tc.ok {
+			t.Errorf("%q: expected ok=%v, but actual %v", tc.line, tc.ok, ok)
+			continue
+		}
+		if percent != tc.percent {
+			t.Errorf("%q: expected %v, but actual %v", tc.line, tc.percent, percent)
+		}
+	}
+}
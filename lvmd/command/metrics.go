@@ -0,0 +1,37 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "topolvm"
+
+var commandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metricsNamespace,
+	Subsystem: "lvmd",
+	Name:      "command_duration_seconds",
+	Help:      "Latency of commands invoked by lvmd",
+	Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+}, []string{"command", "result"})
+
+func init() {
+	prometheus.MustRegister(commandDuration)
+}
+
+// observeCommand records the duration of a command.
+func observeCommand(name string, d time.Duration, err error) {
+	result := "success"
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result = "timeout"
+	case errors.Is(err, context.Canceled):
+		result = "canceled"
+	case err != nil:
+		result = "failure"
+	}
+	commandDuration.WithLabelValues(name, result).Observe(d.Seconds())
+}
@@ -0,0 +1,370 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cybozu-go/log"
+)
+
+// Fields of reports used in this package.
+const (
+	vgFields = "vg_name,vg_size,vg_free"
+	lvFields = "lv_name,lv_path,lv_size,lv_kernel_major,lv_kernel_minor,origin,origin_size,pool_lv,thin_count,lv_tags,lv_device_open"
+	pvFields = "pv_name,vg_name,pv_size,pv_free"
+)
+
+// jsonReportVersion is the first LVM version that supports "--reportformat json".
+var jsonReportVersion = [3]int{2, 2, 158}
+
+// versionProbeTimeout is the time limit of "lvm version" to probe JSON report support.
+const versionProbeTimeout = 10 * time.Second
+
+var (
+	jsonReportOnce      sync.Once
+	jsonReportSupported bool
+)
+
+// LVInfo is a map of report fields to values.
+type LVInfo map[string]string
+
+// parseOutput calls lvm family and parses output from it.
+//
+// cmd is a command name of lvm family.
+// fields are comma separated field names.
+// args is optional arguments for lvm command.
+//
+// The report is requested in JSON.  LVM older than 2.02.158 does not support JSON reports,
+// so the name-prefixed format is used for them instead.
+func parseOutput(ctx context.Context, cmd, fields string, args ...string) ([]LVInfo, error) {
+	arg := []string{
+		cmd, "-o", fields,
+		"--units=b", "--nosuffix",
+	}
+	useJSON := supportsJSONReport()
+	if useJSON {
+		arg = append(arg, "--reportformat", "json")
+	} else {
+		arg = append(arg, "--noheadings", "--unbuffered", "--nameprefixes")
+	}
+	arg = append(arg, args...)
+	out, err := newExecution(ctx, lvm, arg...).run()
+	if err != nil {
+		return nil, err
+	}
+	if useJSON {
+		return decodeJSONReport(out)
+	}
+	return decodeNamePrefixedReport(out)
+}
+
+// supportsJSONReport returns true if the installed LVM supports JSON reports.
+// If the version cannot be determined, JSON reports are assumed to be supported.
+//
+// The version is probed only once, so it does not use the context of the first caller;
+// its cancellation would decide the result for all the later callers.
+func supportsJSONReport() bool {
+	jsonReportOnce.Do(func() {
+		jsonReportSupported = true
+		ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+		defer cancel()
+		out, err := newExecution(ctx, lvm, "version").run()
+		if err != nil {
+			log.Warn("failed to get LVM version", map[string]interface{}{
+				log.FnError: err,
+			})
+			return
+		}
+		version, ok := parseLVMVersion(string(out))
+		if !ok {
+			return
+		}
+		jsonReportSupported = !versionLess(version, jsonReportVersion)
+		if !jsonReportSupported {
+			log.Info("LVM does not support JSON reports", map[string]interface{}{
+				"version": fmt.Sprintf("%d.%02d.%d", version[0], version[1], version[2]),
+			})
+		}
+	})
+	return jsonReportSupported
+}
+
+var lvmVersionRe = regexp.MustCompile(`LVM version:\s*(\d+)\.(\d+)\.(\d+)`)
+
+// parseLVMVersion parses the output of "lvm version".
+func parseLVMVersion(out string) ([3]int, bool) {
+	var version [3]int
+	m := lvmVersionRe.FindStringSubmatch(out)
+	if m == nil {
+		return version, false
+	}
+	for i := range version {
+		v, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return version, false
+		}
+		version[i] = v
+	}
+	return version, true
+}
+
+func versionLess(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// decodeJSONReport decodes the output of lvm commands with "--reportformat json".
+//
+// The output looks like:
+//
+//	{"report": [{"vg": [{"vg_name": "myvg", "vg_size": "1073741824"}]}]}
+func decodeJSONReport(data []byte) ([]LVInfo, error) {
+	var r struct {
+		Report []map[string][]LVInfo `json:"report"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode LVM report: %w", err)
+	}
+	ret := []LVInfo{}
+	for _, report := range r.Report {
+		for _, rows := range report {
+			ret = append(ret, rows...)
+		}
+	}
+	return ret, nil
+}
+
+// decodeNamePrefixedReport decodes the output of lvm commands with "--nameprefixes --noheadings".
+//
+// Each line looks like:
+//
+//	LVM2_LV_NAME='foo' LVM2_LV_TAGS='a=b,c'
+//
+// Values are quoted, and may contain spaces and '='.
+func decodeNamePrefixedReport(data []byte) ([]LVInfo, error) {
+	ret := []LVInfo{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		info, err := decodeNamePrefixedLine(string(line))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, info)
+	}
+	return ret, nil
+}
+
+func decodeNamePrefixedLine(line string) (LVInfo, error) {
+	ret := LVInfo{}
+	rest := line
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			return ret, nil
+		}
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 || !strings.HasPrefix(rest, "LVM2_") {
+			return nil, fmt.Errorf("malformed LVM report: %q", line)
+		}
+		// removes "LVM2_" prefix.
+		key := strings.ToLower(rest[5:eq])
+		rest = rest[eq+1:]
+
+		if !strings.HasPrefix(rest, "'") {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			ret[key] = rest[:end]
+			rest = rest[end:]
+			continue
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(rest); i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+				value.WriteByte(rest[i])
+				continue
+			}
+			if rest[i] == '\'' {
+				break
+			}
+			value.WriteByte(rest[i])
+		}
+		if i >= len(rest) {
+			return nil, fmt.Errorf("unterminated value in LVM report: %q", line)
+		}
+		ret[key] = value.String()
+		rest = rest[i+1:]
+	}
+}
+
+// parseSize parses a number field of reports.
+// Fields that do not apply to the object are reported as empty, and parsed as zero.
+func parseSize(info LVInfo, key string) (uint64, error) {
+	v := info[key]
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s in LVM report: %w", key, err)
+	}
+	return n, nil
+}
+
+// parseTags parses a tag list of reports.
+// LVM does not allow commas in tags, so the list is separated by commas.
+func parseTags(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// vgRecord is a row of the report of volume groups.
+type vgRecord struct {
+	name string
+	size uint64
+	free uint64
+}
+
+func newVGRecord(info LVInfo) (vgRecord, error) {
+	size, err := parseSize(info, "vg_size")
+	if err != nil {
+		return vgRecord{}, err
+	}
+	free, err := parseSize(info, "vg_free")
+	if err != nil {
+		return vgRecord{}, err
+	}
+	return vgRecord{name: info["vg_name"], size: size, free: free}, nil
+}
+
+// listVGRecords lists volume groups.  args restrict the volume groups to be listed.
+func listVGRecords(ctx context.Context, args ...string) ([]vgRecord, error) {
+	infoList, err := parseOutput(ctx, "vgs", vgFields, args...)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]vgRecord, 0, len(infoList))
+	for _, info := range infoList {
+		r, err := newVGRecord(info)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
+
+// lvRecord is a row of the report of logical volumes.
+type lvRecord struct {
+	name       string
+	path       string
+	size       uint64
+	major      uint32
+	minor      uint32
+	origin     string
+	originSize uint64
+	pool       string
+	isPool     bool
+	tags       []string
+	open       bool
+}
+
+func newLVRecord(info LVInfo) (lvRecord, error) {
+	size, err := parseSize(info, "lv_size")
+	if err != nil {
+		return lvRecord{}, err
+	}
+	originSize, err := parseSize(info, "origin_size")
+	if err != nil {
+		return lvRecord{}, err
+	}
+	// inactive volumes have -1 as device numbers.
+	major, _ := strconv.ParseUint(info["lv_kernel_major"], 10, 32)
+	minor, _ := strconv.ParseUint(info["lv_kernel_minor"], 10, 32)
+	return lvRecord{
+		name:       info["lv_name"],
+		path:       info["lv_path"],
+		size:       size,
+		major:      uint32(major),
+		minor:      uint32(minor),
+		origin:     info["origin"],
+		originSize: originSize,
+		pool:       info["pool_lv"],
+		isPool:     len(info["thin_count"]) > 0,
+		tags:       parseTags(info["lv_tags"]),
+		open:       info["lv_device_open"] == "open",
+	}, nil
+}
+
+// listLVRecords lists logical volumes.  args restrict the logical volumes to be listed.
+func listLVRecords(ctx context.Context, args ...string) ([]lvRecord, error) {
+	infoList, err := parseOutput(ctx, "lvs", lvFields, args...)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]lvRecord, 0, len(infoList))
+	for _, info := range infoList {
+		r, err := newLVRecord(info)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
+
+// pvRecord is a row of the report of physical volumes.
+type pvRecord struct {
+	name string
+	vg   string
+	size uint64
+	free uint64
+}
+
+func newPVRecord(info LVInfo) (pvRecord, error) {
+	size, err := parseSize(info, "pv_size")
+	if err != nil {
+		return pvRecord{}, err
+	}
+	free, err := parseSize(info, "pv_free")
+	if err != nil {
+		return pvRecord{}, err
+	}
+	return pvRecord{name: info["pv_name"], vg: info["vg_name"], size: size, free: free}, nil
+}
+
+// listPVRecords lists physical volumes.  args restrict the physical volumes to be listed.
+func listPVRecords(ctx context.Context, args ...string) ([]pvRecord, error) {
+	infoList, err := parseOutput(ctx, "pvs", pvFields, args...)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]pvRecord, 0, len(infoList))
+	for _, info := range infoList {
+		r, err := newPVRecord(info)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
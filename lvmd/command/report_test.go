@@ -0,0 +1,200 @@
+package command
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+var expectedLVRecords = []lvRecord{
+	{
+		name: "vol1", path: "/dev/myvg1/vol1", size: 1 << 30, major: 253, minor: 0,
+		tags: []string{"topolvm.cybozu.com/owned"}, open: true,
+	},
+	{
+		name: "trash-vol2", path: "/dev/myvg1/trash-vol2", size: 2 << 30, major: 253, minor: 1,
+		tags: []string{
+			"topolvm.cybozu.com/owned",
+			"topolvm.cybozu.com/trashed-at=1650000000",
+			"topolvm.cybozu.com/imported-from=old vol",
+		},
+	},
+	{
+		name: "snap1", path: "/dev/myvg1/snap1", size: 50 << 30, major: 253, minor: 3,
+		origin: "vol1", originSize: 1 << 30,
+	},
+	{
+		name: "pool0", size: 4 << 30, isPool: true,
+	},
+	{
+		name: "thin1", path: "/dev/myvg1/thin1", size: 5 << 30, pool: "pool0",
+	},
+}
+
+func toLVRecords(t *testing.T, infoList []LVInfo) []lvRecord {
+	var ret []lvRecord
+	for _, info := range infoList {
+		r, err := newLVRecord(info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+func TestDecodeJSONReport(t *testing.T) {
+	infoList, err := decodeJSONReport(readFixture(t, "lvs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvs := toLVRecords(t, infoList)
+	if !reflect.DeepEqual(lvs, expectedLVRecords) {
+		t.Errorf("unexpected LVs: %#v", lvs)
+	}
+
+	infoList, err = decodeJSONReport(readFixture(t, "vgs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vgs []vgRecord
+	for _, info := range infoList {
+		r, err := newVGRecord(info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vgs = append(vgs, r)
+	}
+	expectedVGs := []vgRecord{
+		{name: "myvg1", size: 21470642176, free: 15028191232},
+		{name: "myvg2", size: 10733223936, free: 10733223936},
+	}
+	if !reflect.DeepEqual(vgs, expectedVGs) {
+		t.Errorf("unexpected VGs: %#v", vgs)
+	}
+
+	infoList, err = decodeJSONReport(readFixture(t, "pvs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pvs []pvRecord
+	for _, info := range infoList {
+		r, err := newPVRecord(info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pvs = append(pvs, r)
+	}
+	expectedPVs := []pvRecord{
+		{name: "/dev/loop0", vg: "myvg1", size: 10733223936, free: 4290772992},
+		{name: "/dev/loop1", vg: "myvg1", size: 10 << 30, free: 10 << 30},
+		{name: "/dev/loop2", size: 1 << 30, free: 1 << 30},
+	}
+	if !reflect.DeepEqual(pvs, expectedPVs) {
+		t.Errorf("unexpected PVs: %#v", pvs)
+	}
+
+	infoList, err = decodeJSONReport([]byte(`{"report": [{"lv": []}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infoList) != 0 {
+		t.Errorf("unexpected report: %#v", infoList)
+	}
+
+	_, err = decodeJSONReport([]byte(`  LVM2_LV_NAME='vol1'`))
+	if err == nil {
+		t.Error("non-JSON report should be an error")
+	}
+}
+
+func TestDecodeNamePrefixedReport(t *testing.T) {
+	infoList, err := decodeNamePrefixedReport(readFixture(t, "lvs.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvs := toLVRecords(t, infoList)
+	if !reflect.DeepEqual(lvs, expectedLVRecords) {
+		t.Errorf("unexpected LVs: %#v", lvs)
+	}
+
+	info, err := decodeNamePrefixedLine(`LVM2_LV_NAME='it\'s' LVM2_LV_SIZE=1024`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(info, LVInfo{"lv_name": "it's", "lv_size": "1024"}) {
+		t.Errorf("unexpected info: %#v", info)
+	}
+
+	for _, line := range []string{
+		`LVM2_LV_NAME='vol1`,
+		`lv_name='vol1'`,
+		`LVM2_LV_NAME`,
+	} {
+		if _, err := decodeNamePrefixedLine(line); err == nil {
+			t.Errorf("%q should be an error", line)
+		}
+	}
+}
+
+func TestNewLVRecordInvalidSize(t *testing.T) {
+	_, err := newLVRecord(LVInfo{"lv_name": "vol1", "lv_size": "1.00g"})
+	if err == nil {
+		t.Error("invalid size should be an error")
+	}
+}
+
+func TestParseLVMVersion(t *testing.T) {
+	testCases := []struct {
+		output  string
+		version [3]int
+		ok      bool
+		json    bool
+	}{
+		{
+			output:  "  LVM version:     2.03.11(2) (2021-01-08)\n  Library version: 1.02.175 (2021-01-08)\n",
+			version: [3]int{2, 3, 11},
+			ok:      true,
+			json:    true,
+		},
+		{
+			output:  "  LVM version:     2.02.158(2) (2016-06-28)\n",
+			version: [3]int{2, 2, 158},
+			ok:      true,
+			json:    true,
+		},
+		{
+			output:  "  LVM version:     2.02.133(2) (2015-10-30)\n",
+			version: [3]int{2, 2, 133},
+			ok:      true,
+			json:    false,
+		},
+		{
+			output: "unknown",
+		},
+	}
+	for _, tc := range testCases {
+		version, ok := parseLVMVersion(tc.output)
+		if ok != tc.ok {
+			t.Errorf("%q: expected ok=%v, but actual %v", tc.output, tc.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if version != tc.version {
+			t.Errorf("%q: expected %v, but actual %v", tc.output, tc.version, version)
+		}
+		if json := !versionLess(version, jsonReportVersion); json != tc.json {
+			t.Errorf("%q: expected json=%v, but actual %v", tc.output, tc.json, json)
+		}
+	}
+}
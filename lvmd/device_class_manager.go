@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/spare"
 )
 
 // ErrNotFound is returned when a VG or LV is not found.
@@ -17,6 +19,16 @@ const defaultSpareGB = 10
 //   https://github.com/kubernetes/apimachinery/blob/v0.18.3/pkg/util/validation/validation.go#L42
 var qualifiedNameRegexp = regexp.MustCompile("^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$")
 
+// Wipe policies for logical volumes
+const (
+	// WipePolicyDiscard discards all blocks of a logical volume
+	WipePolicyDiscard = "discard"
+	// WipePolicyZero fills a logical volume with zeroes
+	WipePolicyZero = "zero"
+	// WipePolicyDiscardOrZero discards all blocks, or fills them with zeroes if discard is not supported
+	WipePolicyDiscardOrZero = "discard-or-zero"
+)
+
 // This regexp is used to check StripeSize format
 var stripeSizeRegexp = regexp.MustCompile("(?i)^([0-9]*)(k|m|g|t|p|e|b|s)?$")
 
@@ -30,18 +42,44 @@ type DeviceClass struct {
 	Default bool `json:"default"`
 	// SpareGB is storage capacity in GiB to be spared
 	SpareGB *uint64 `json:"spare-gb"`
+	// Spare is storage capacity to be spared as a percentage of the volume group size or a quantity
+	Spare string `json:"spare"`
 	// Stripe is the number of stripes in the logical volume
 	Stripe *uint `json:"stripe"`
 	// StripeSize is the amount of data that is written to one device before moving to the next device
 	StripeSize string `json:"stripe-size"`
+	// TrashRetention is the duration for which removed logical volumes are kept in the trash
+	TrashRetention string `json:"trash-retention"`
+	// WipePolicy is how logical volumes are wiped before removal
+	WipePolicy string `json:"wipe-policy"`
 }
 
-// GetSpare returns spare in bytes for the device-class
-func (c DeviceClass) GetSpare() uint64 {
+// GetSpare returns spare for the device-class
+func (c DeviceClass) GetSpare() spare.Spare {
+	if c.Spare != "" {
+		s, err := spare.Parse(c.Spare)
+		if err == nil {
+			return s
+		}
+	}
 	if c.SpareGB == nil {
-		return defaultSpareGB << 30
+		return spare.FromBytes(defaultSpareGB << 30)
+	}
+	return spare.FromBytes(*c.SpareGB << 30)
+}
+
+// GetTrashRetention returns how long removed logical volumes are kept in the trash.
+// Zero means that logical volumes are removed immediately.
+// Invalid values are rejected by ValidateDeviceClasses.
+func (c DeviceClass) GetTrashRetention() time.Duration {
+	if c.TrashRetention == "" {
+		return 0
 	}
-	return *c.SpareGB << 30
+	d, err := time.ParseDuration(c.TrashRetention)
+	if err != nil {
+		return 0
+	}
+	return d
 }
 
 // ValidateDeviceClasses validates device-classes
@@ -75,9 +113,32 @@ func ValidateDeviceClasses(deviceClasses []*DeviceClass) error {
 		}
 		dcNames[dc.Name] = true
 		vgNames[dc.VolumeGroup] = true
+		if dc.Spare != "" {
+			if dc.SpareGB != nil {
+				return fmt.Errorf("spare and spare-gb should not be specified together: %s", dc.Name)
+			}
+			if _, err := spare.Parse(dc.Spare); err != nil {
+				return fmt.Errorf("%v: %s", err, dc.Name)
+			}
+		}
 		if dc.StripeSize != "" && !stripeSizeRegexp.MatchString(dc.StripeSize) {
 			return fmt.Errorf("stripe-size format is \"Size[k|UNIT]\": %s", dc.Name)
 		}
+		if dc.TrashRetention != "" {
+			d, err := time.ParseDuration(dc.TrashRetention)
+			if err != nil {
+				return fmt.Errorf("trash-retention should be a duration like \"24h\": %s", dc.Name)
+			}
+			if d < 0 {
+				return fmt.Errorf("trash-retention should not be negative: %s", dc.Name)
+			}
+		}
+		switch dc.WipePolicy {
+		case "", WipePolicyDiscard, WipePolicyZero, WipePolicyDiscardOrZero:
+		default:
+			return fmt.Errorf("wipe-policy should be one of %q, %q or %q: %s",
+				WipePolicyDiscard, WipePolicyZero, WipePolicyDiscardOrZero, dc.Name)
+		}
 	}
 	if countDefault != 1 {
 		return errors.New("should have only one default device-class")
@@ -118,6 +179,15 @@ func (m DeviceClassManager) DeviceClass(dcName string) (*DeviceClass, error) {
 	return nil, ErrNotFound
 }
 
+// DeviceClasses returns all the device-classes
+func (m DeviceClassManager) DeviceClasses() []*DeviceClass {
+	dcs := make([]*DeviceClass, 0, len(m.deviceClassByName))
+	for _, dc := range m.deviceClassByName {
+		dcs = append(dcs, dc)
+	}
+	return dcs
+}
+
 // FindDeviceClassByVGName returns the device-class with the volume group name
 func (m DeviceClassManager) FindDeviceClassByVGName(vgName string) (*DeviceClass, error) {
 	if v, ok := m.deviceClassByVGName[vgName]; ok {
@@ -7,6 +7,7 @@ import (
 
 func TestValidateDeviceClasses(t *testing.T) {
 	stripe := uint(2)
+	spareGB := uint64(10)
 
 	cases := []struct {
 		deviceClasses []*DeviceClass
@@ -124,6 +125,110 @@ func TestValidateDeviceClasses(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:           "trash",
+					VolumeGroup:    "node1-myvg1",
+					TrashRetention: "72h",
+					Default:        true,
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:           "invalid-trash-retention",
+					VolumeGroup:    "node1-myvg1",
+					TrashRetention: "3days",
+					Default:        true,
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:           "negative-trash-retention",
+					VolumeGroup:    "node1-myvg1",
+					TrashRetention: "-1h",
+					Default:        true,
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "discard",
+					VolumeGroup: "node1-myvg1",
+					WipePolicy:  WipePolicyDiscard,
+					Default:     true,
+				},
+				{
+					Name:        "zero",
+					VolumeGroup: "node1-myvg2",
+					WipePolicy:  WipePolicyZero,
+				},
+				{
+					Name:        "discard-or-zero",
+					VolumeGroup: "node1-myvg3",
+					WipePolicy:  WipePolicyDiscardOrZero,
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "invalid-wipe-policy",
+					VolumeGroup: "node1-myvg1",
+					WipePolicy:  "shred",
+					Default:     true,
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "spare-percent",
+					VolumeGroup: "node1-myvg1",
+					Spare:       "5%",
+					Default:     true,
+				},
+				{
+					Name:        "spare-quantity",
+					VolumeGroup: "node1-myvg2",
+					Spare:       "100Gi",
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "invalid-spare",
+					VolumeGroup: "node1-myvg1",
+					Spare:       "150%",
+					Default:     true,
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "spare-and-spare-gb",
+					VolumeGroup: "node1-myvg1",
+					Spare:       "5%",
+					SpareGB:     &spareGB,
+					Default:     true,
+				},
+			},
+			valid: false,
+		},
 	}
 
 	for i, c := range cases {
@@ -155,6 +260,11 @@ func TestDeviceClassManager(t *testing.T) {
 			VolumeGroup: "ssd-vg",
 			Default:     true,
 		},
+		{
+			Name:        "nvme",
+			VolumeGroup: "nvme-vg",
+			Spare:       "5%",
+		},
 	}
 	manager := NewDeviceClassManager(deviceClasses)
 
@@ -162,7 +272,7 @@ func TestDeviceClassManager(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if dc.GetSpare() != spare50gb<<30 {
+	if dc.GetSpare().Bytes(0) != spare50gb<<30 {
 		t.Error("hdd1's spare should be 50GB")
 	}
 
@@ -175,7 +285,7 @@ func TestDeviceClassManager(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if dc.GetSpare() != spare100gb<<30 {
+	if dc.GetSpare().Bytes(0) != spare100gb<<30 {
 		t.Error("hdd2's spare should be 100GB")
 	}
 
@@ -191,7 +301,15 @@ func TestDeviceClassManager(t *testing.T) {
 	if dc.Name != "ssd" {
 		t.Fatal("wrong device-class found")
 	}
-	if dc.GetSpare() != defaultSpareGB<<30 {
+	if dc.GetSpare().Bytes(0) != defaultSpareGB<<30 {
 		t.Error("ssd's spare should be default")
 	}
+
+	dc, err = manager.DeviceClass("nvme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dc.GetSpare().Bytes(200<<30) != 10<<30 {
+		t.Error("nvme's spare should be 5% of the volume group")
+	}
 }
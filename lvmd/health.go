@@ -0,0 +1,41 @@
+package lvmd
+
+import (
+	"context"
+
+	"github.com/cybozu-go/log"
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// UpdateHealth checks the volume groups of device-classes and updates the serving statuses of server.
+// The status of each device-class is reported under its name, and the status of lvmd as a whole
+// is reported under the empty name.  lvmd is serving only if all volume groups are available.
+func UpdateHealth(ctx context.Context, b backend.Backend, manager *DeviceClassManager, server *health.Server) {
+	vgs, err := b.ListVolumeGroups(ctx)
+	if err != nil {
+		log.Error("failed to list volume groups for health checking", map[string]interface{}{
+			log.FnError: err,
+		})
+	}
+	found := make(map[string]bool)
+	for _, vg := range vgs {
+		found[vg.Name()] = true
+	}
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	for _, dc := range manager.DeviceClasses() {
+		st := healthpb.HealthCheckResponse_SERVING
+		if !found[dc.VolumeGroup] {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+			overall = healthpb.HealthCheckResponse_NOT_SERVING
+			log.Warn("volume group is not available", map[string]interface{}{
+				"device_class": dc.Name,
+				"volume_group": dc.VolumeGroup,
+			})
+		}
+		server.SetServingStatus(dc.Name, st)
+	}
+	server.SetServingStatus("", overall)
+}
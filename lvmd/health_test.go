@@ -0,0 +1,50 @@
+package lvmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestUpdateHealth(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg1", "/dev/fake1", 10<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{
+		{Name: "ssd", VolumeGroup: "vg1", Default: true},
+		{Name: "hdd", VolumeGroup: "vg2"},
+	})
+	server := health.NewServer()
+
+	check := func(service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		res, err := server.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.GetStatus() != expected {
+			t.Errorf("%q: expected %s, but actual %s", service, expected, res.GetStatus())
+		}
+	}
+
+	UpdateHealth(ctx, b, manager, server)
+	check("", healthpb.HealthCheckResponse_NOT_SERVING)
+	check("ssd", healthpb.HealthCheckResponse_SERVING)
+	check("hdd", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	b.AddPhysicalVolume("vg2", "/dev/fake2", 10<<30)
+	UpdateHealth(ctx, b, manager, server)
+	check("", healthpb.HealthCheckResponse_SERVING)
+	check("ssd", healthpb.HealthCheckResponse_SERVING)
+	check("hdd", healthpb.HealthCheckResponse_SERVING)
+
+	b.InjectFailure(fake.OpListVolumeGroups, errors.New("injected"), 1)
+	UpdateHealth(ctx, b, manager, server)
+	check("", healthpb.HealthCheckResponse_NOT_SERVING)
+	check("ssd", healthpb.HealthCheckResponse_NOT_SERVING)
+	check("hdd", healthpb.HealthCheckResponse_NOT_SERVING)
+}
@@ -0,0 +1,279 @@
+package lvmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxLVEvents is the number of LV events kept to resume watchers.
+const maxLVEvents = 1024
+
+type lvEventRecord struct {
+	seq   uint64
+	event *proto.LVEvent
+}
+
+// lvEventLog detects changes of logical volumes and the usage of pools by comparing
+// snapshots of them, and keeps the recent changes so that watchers can resume from a token.
+//
+// A token is "<epoch>:<seq>" where epoch is random for each lvmd process.
+// Tokens issued by other processes are therefore rejected.
+type lvEventLog struct {
+	epoch      string
+	seq        uint64
+	evictedSeq uint64
+	snapshots  map[string]map[string]*proto.LogicalVolume
+	pools      map[string]*proto.PoolUsage
+	history    []lvEventRecord
+	// initialized is true after the first snapshots of all device-classes are taken.
+	initialized bool
+}
+
+func newLVEventLog() *lvEventLog {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return &lvEventLog{
+		epoch:     hex.EncodeToString(b),
+		snapshots: make(map[string]map[string]*proto.LogicalVolume),
+		pools:     make(map[string]*proto.PoolUsage),
+	}
+}
+
+func (l *lvEventLog) token(seq uint64) string {
+	return l.epoch + ":" + strconv.FormatUint(seq, 10)
+}
+
+// parseToken returns the sequence number in token.
+// It returns OutOfRange if the events after token are no longer available.
+func (l *lvEventLog) parseToken(token string) (uint64, error) {
+	fields := strings.SplitN(token, ":", 2)
+	if len(fields) != 2 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid resume token: %s", token)
+	}
+	seq, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid resume token: %s", token)
+	}
+	if fields[0] != l.epoch || seq > l.seq || seq < l.evictedSeq {
+		return 0, status.Errorf(codes.OutOfRange, "resume token expired: %s", token)
+	}
+	return seq, nil
+}
+
+func (l *lvEventLog) append(typ proto.LVEvent_Type, dc string, lv *proto.LogicalVolume) {
+	l.record(&proto.LVEvent{Type: typ, DeviceClass: dc, Volume: lv})
+}
+
+func (l *lvEventLog) record(ev *proto.LVEvent) {
+	l.seq++
+	l.history = append(l.history, lvEventRecord{
+		seq:   l.seq,
+		event: ev,
+	})
+	if len(l.history) > maxLVEvents {
+		l.evictedSeq = l.history[0].seq
+		l.history = l.history[1:]
+	}
+}
+
+// update replaces the snapshot of the device-class and records the differences.
+// Until the log is initialized, the first snapshot of each device-class is taken as
+// the baseline and records nothing.  After that, a device-class without a snapshot,
+// e.g. one whose volume group has become available, records all its volumes as created.
+func (l *lvEventLog) update(dc string, lvs []*proto.LogicalVolume, pool *proto.PoolUsage) {
+	current := make(map[string]*proto.LogicalVolume, len(lvs))
+	for _, lv := range lvs {
+		current[lv.Name] = lv
+	}
+	prev, ok := l.snapshots[dc]
+	prevPool := l.pools[dc]
+	l.snapshots[dc] = current
+	l.pools[dc] = pool
+	if !ok && !l.initialized {
+		return
+	}
+
+	for _, name := range sortedNames(current) {
+		lv := current[name]
+		old, ok := prev[name]
+		if !ok {
+			l.append(proto.LVEvent_CREATED, dc, lv)
+			continue
+		}
+		if old.SizeGb != lv.SizeGb {
+			l.append(proto.LVEvent_RESIZED, dc, lv)
+		}
+		if !equalTags(old.Tags, lv.Tags) {
+			l.append(proto.LVEvent_TAGS_CHANGED, dc, lv)
+		}
+	}
+	for _, name := range sortedNames(prev) {
+		if _, ok := current[name]; !ok {
+			l.append(proto.LVEvent_REMOVED, dc, prev[name])
+		}
+	}
+	if prevPool.GetUsedBytes() != pool.GetUsedBytes() || prevPool.GetSizeBytes() != pool.GetSizeBytes() {
+		l.record(&proto.LVEvent{Type: proto.LVEvent_POOL_USAGE_CHANGED, DeviceClass: dc, Pool: pool})
+	}
+}
+
+// forget removes the snapshot of the device-class and records all its volumes as removed.
+// It is called when the volume group of the device-class disappears.
+func (l *lvEventLog) forget(dc string) {
+	prev, ok := l.snapshots[dc]
+	if !ok {
+		return
+	}
+	delete(l.snapshots, dc)
+	delete(l.pools, dc)
+	for _, name := range sortedNames(prev) {
+		l.append(proto.LVEvent_REMOVED, dc, prev[name])
+	}
+}
+
+// since returns the events recorded after seq.
+func (l *lvEventLog) since(seq uint64) []*proto.LVEvent {
+	i := sort.Search(len(l.history), func(i int) bool {
+		return l.history[i].seq > seq
+	})
+	events := make([]*proto.LVEvent, 0, len(l.history)-i)
+	for _, r := range l.history[i:] {
+		events = append(events, r.event)
+	}
+	return events
+}
+
+// all returns CREATED events for all volumes in the snapshots,
+// followed by a POOL_USAGE_CHANGED event with the current usage for each device-class.
+func (l *lvEventLog) all() []*proto.LVEvent {
+	dcs := make([]string, 0, len(l.snapshots))
+	for dc := range l.snapshots {
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+
+	var events []*proto.LVEvent
+	for _, dc := range dcs {
+		lvs := l.snapshots[dc]
+		for _, name := range sortedNames(lvs) {
+			events = append(events, &proto.LVEvent{Type: proto.LVEvent_CREATED, DeviceClass: dc, Volume: lvs[name]})
+		}
+		events = append(events, &proto.LVEvent{Type: proto.LVEvent_POOL_USAGE_CHANGED, DeviceClass: dc, Pool: l.pools[dc]})
+	}
+	return events
+}
+
+// lvWatcher is the state of a watcher that requested LV events.
+type lvWatcher struct {
+	// started is false until the first response is sent.
+	started bool
+	// token is the token requested by the watcher.
+	token string
+	// cursor is the sequence number of the last event sent.
+	cursor uint64
+}
+
+// refreshLVEvents takes snapshots of the logical volumes and the usage of the volume groups
+// of all device-classes.
+// The volumes of device-classes whose volume group is not found are recorded as removed.
+// Device-classes whose volume group cannot be looked up for other errors are skipped,
+// so that their changes are detected once the volume group is available again.
+// The caller must hold s.evMu.
+func (s *vgService) refreshLVEvents(ctx context.Context) error {
+	for _, dc := range s.dcManager.DeviceClasses() {
+		vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
+		if err == backend.ErrNotFound {
+			s.events.forget(dc.Name)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		lvs, err := vg.ListVolumes(ctx)
+		if err != nil {
+			return statusError(err)
+		}
+		vols := make([]*proto.LogicalVolume, len(lvs))
+		for i, lv := range lvs {
+			vols[i] = toProtoLogicalVolume(lv)
+		}
+		vgFree, err := vg.Free(ctx)
+		if err != nil {
+			return statusError(err)
+		}
+		vgSize, err := vg.Size(ctx)
+		if err != nil {
+			return statusError(err)
+		}
+		s.events.update(dc.Name, vols, &proto.PoolUsage{UsedBytes: vgSize - vgFree, SizeBytes: vgSize})
+	}
+	s.events.initialized = true
+	return nil
+}
+
+// lvEvents refreshes the snapshots and fills the events for w into res.
+func (s *vgService) lvEvents(ctx context.Context, w *lvWatcher, res *proto.WatchResponse) error {
+	s.evMu.Lock()
+	defer s.evMu.Unlock()
+
+	if err := s.refreshLVEvents(ctx); err != nil {
+		return err
+	}
+	if !w.started {
+		w.started = true
+		if w.token == "" {
+			res.Events = s.events.all()
+			w.cursor = s.events.seq
+			res.ResumeToken = s.events.token(w.cursor)
+			return nil
+		}
+		seq, err := s.events.parseToken(w.token)
+		if err != nil {
+			return err
+		}
+		w.cursor = seq
+	}
+	if w.cursor < s.events.evictedSeq {
+		return status.Error(codes.OutOfRange, "too many LV events to follow")
+	}
+	res.Events = s.events.since(w.cursor)
+	w.cursor = s.events.seq
+	res.ResumeToken = s.events.token(w.cursor)
+	return nil
+}
+
+func sortedNames(m map[string]*proto.LogicalVolume) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,229 @@
+package lvmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLVEventsWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 10<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "vg", Default: true}})
+	svc, _ := NewVGService(manager, b)
+	s := svc.(*vgService)
+
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.CreateVolume(ctx, "lv1", 1<<30, nil, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// For POOL_USAGE_CHANGED, name is empty and size is the used GiB of the pool.
+	type event struct {
+		typ  proto.LVEvent_Type
+		name string
+		size uint64
+	}
+	next := func(w *lvWatcher, expected ...event) string {
+		t.Helper()
+		res := &proto.WatchResponse{}
+		if err := s.lvEvents(ctx, w, res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Events) != len(expected) {
+			t.Fatalf("expected %d events, but actual %v", len(expected), res.Events)
+		}
+		for i, ev := range res.Events {
+			actual := event{ev.Type, ev.GetVolume().GetName(), ev.GetVolume().GetSizeGb()}
+			if ev.Type == proto.LVEvent_POOL_USAGE_CHANGED {
+				actual.size = ev.GetPool().GetUsedBytes() >> 30
+			}
+			if actual != expected[i] || ev.DeviceClass != "ssd" {
+				t.Errorf("event %d: expected %v, but actual %v", i, expected[i], ev)
+			}
+		}
+		return res.ResumeToken
+	}
+
+	// A watcher without a token receives all existing volumes.
+	w1 := &lvWatcher{}
+	token := next(w1, event{proto.LVEvent_CREATED, "lv1", 1}, event{proto.LVEvent_POOL_USAGE_CHANGED, "", 1})
+	next(w1)
+
+	_, err = vg.CreateVolume(ctx, "lv2", 2<<30, nil, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lv1, err := vg.FindVolume(ctx, "lv1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = lv1.Resize(ctx, 3<<30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = lv1.AddTags(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next(w1,
+		event{proto.LVEvent_RESIZED, "lv1", 3},
+		event{proto.LVEvent_TAGS_CHANGED, "lv1", 3},
+		event{proto.LVEvent_CREATED, "lv2", 2},
+		event{proto.LVEvent_POOL_USAGE_CHANGED, "", 5},
+	)
+
+	lv2, err := vg.FindVolume(ctx, "lv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = lv2.Remove(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next(w1, event{proto.LVEvent_REMOVED, "lv2", 2}, event{proto.LVEvent_POOL_USAGE_CHANGED, "", 3})
+
+	// Adding a physical volume changes only the usage of the pool.
+	b.AddPhysicalVolume("vg", "/dev/fake2", 10<<30)
+	next(w1, event{proto.LVEvent_POOL_USAGE_CHANGED, "", 3})
+	if size := s.events.pools["ssd"].GetSizeBytes(); size != 20<<30 {
+		t.Errorf("expected the pool size to be %d, but actual %d", uint64(20<<30), size)
+	}
+
+	// A watcher resuming from a token receives the events after the token.
+	w2 := &lvWatcher{token: token}
+	next(w2,
+		event{proto.LVEvent_RESIZED, "lv1", 3},
+		event{proto.LVEvent_TAGS_CHANGED, "lv1", 3},
+		event{proto.LVEvent_CREATED, "lv2", 2},
+		event{proto.LVEvent_POOL_USAGE_CHANGED, "", 5},
+		event{proto.LVEvent_REMOVED, "lv2", 2},
+		event{proto.LVEvent_POOL_USAGE_CHANGED, "", 3},
+		event{proto.LVEvent_POOL_USAGE_CHANGED, "", 3},
+	)
+	next(w2)
+
+	testCases := []struct {
+		token string
+		code  codes.Code
+	}{
+		{"foo", codes.InvalidArgument},
+		{s.events.epoch + ":bar", codes.InvalidArgument},
+		{"0123456789abcdef:0", codes.OutOfRange},
+		{s.events.token(s.events.seq + 1), codes.OutOfRange},
+	}
+	for _, tc := range testCases {
+		err := s.lvEvents(ctx, &lvWatcher{token: tc.token}, &proto.WatchResponse{})
+		if status.Code(err) != tc.code {
+			t.Errorf("%s: expected %s, but actual %v", tc.token, tc.code, err)
+		}
+	}
+
+	// Tokens become invalid when the history is evicted.
+	for i := 0; i <= maxLVEvents; i++ {
+		s.events.append(proto.LVEvent_UNKNOWN, "ssd", &proto.LogicalVolume{})
+	}
+	err = s.lvEvents(ctx, &lvWatcher{token: token}, &proto.WatchResponse{})
+	if status.Code(err) != codes.OutOfRange {
+		t.Errorf("expected %s, but actual %v", codes.OutOfRange, err)
+	}
+	err = s.lvEvents(ctx, w1, &proto.WatchResponse{})
+	if status.Code(err) != codes.OutOfRange {
+		t.Errorf("expected %s, but actual %v", codes.OutOfRange, err)
+	}
+}
+
+func TestLVEventsVolumeGroupAvailability(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg1", "/dev/fake1", 10<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{
+		{Name: "ssd", VolumeGroup: "vg1", Default: true},
+		{Name: "hdd", VolumeGroup: "vg2"},
+	})
+	svc, _ := NewVGService(manager, b)
+	s := svc.(*vgService)
+
+	createVolume := func(vgName, name string, size uint64) {
+		t.Helper()
+		vg, err := b.FindVolumeGroup(ctx, vgName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = vg.CreateVolume(ctx, name, size, nil, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// For POOL_USAGE_CHANGED, name is empty and size is the used GiB of the pool.
+	type event struct {
+		typ  proto.LVEvent_Type
+		dc   string
+		name string
+		size uint64
+	}
+	next := func(w *lvWatcher, expected ...event) {
+		t.Helper()
+		res := &proto.WatchResponse{}
+		if err := s.lvEvents(ctx, w, res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Events) != len(expected) {
+			t.Fatalf("expected %d events, but actual %v", len(expected), res.Events)
+		}
+		for i, ev := range res.Events {
+			actual := event{ev.Type, ev.DeviceClass, ev.GetVolume().GetName(), ev.GetVolume().GetSizeGb()}
+			if ev.Type == proto.LVEvent_POOL_USAGE_CHANGED {
+				actual.size = ev.GetPool().GetUsedBytes() >> 30
+			}
+			if actual != expected[i] {
+				t.Errorf("event %d: expected %v, but actual %v", i, expected[i], ev)
+			}
+		}
+	}
+
+	createVolume("vg1", "lv1", 1<<30)
+	w := &lvWatcher{}
+	next(w, event{proto.LVEvent_CREATED, "ssd", "lv1", 1}, event{proto.LVEvent_POOL_USAGE_CHANGED, "ssd", "", 1})
+
+	// The volumes of a volume group that becomes available are reported as created.
+	b.AddPhysicalVolume("vg2", "/dev/fake2", 10<<30)
+	createVolume("vg2", "lv2", 2<<30)
+	next(w, event{proto.LVEvent_CREATED, "hdd", "lv2", 2}, event{proto.LVEvent_POOL_USAGE_CHANGED, "hdd", "", 2})
+
+	// Changes while volume groups cannot be looked up are reported afterwards.
+	b.InjectFailure(fake.OpFindVolumeGroup, errors.New("timeout"), 2)
+	next(w)
+	createVolume("vg2", "lv3", 3<<30)
+	next(w, event{proto.LVEvent_CREATED, "hdd", "lv3", 3}, event{proto.LVEvent_POOL_USAGE_CHANGED, "hdd", "", 5})
+
+	// The volumes of a volume group that disappears are reported as removed,
+	// and reported as created again when it comes back.
+	b.InjectFailure(fake.OpFindVolumeGroup, backend.ErrNotFound, 0)
+	next(w,
+		event{proto.LVEvent_REMOVED, "ssd", "lv1", 1},
+		event{proto.LVEvent_REMOVED, "hdd", "lv2", 2},
+		event{proto.LVEvent_REMOVED, "hdd", "lv3", 3},
+	)
+	next(w)
+	b.ClearFailures()
+	next(w,
+		event{proto.LVEvent_CREATED, "ssd", "lv1", 1},
+		event{proto.LVEvent_POOL_USAGE_CHANGED, "ssd", "", 1},
+		event{proto.LVEvent_CREATED, "hdd", "lv2", 2},
+		event{proto.LVEvent_CREATED, "hdd", "lv3", 3},
+		event{proto.LVEvent_POOL_USAGE_CHANGED, "hdd", "", 5},
+	)
+}
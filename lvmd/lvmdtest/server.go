@@ -0,0 +1,72 @@
+// Package lvmdtest serves lvmd backed by the fake backend for tests of its clients.
+package lvmdtest
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+
+	"github.com/topolvm/topolvm/lvmd"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Server is lvmd serving on a UNIX domain socket with the fake backend.
+type Server struct {
+	// Backend is the fake backend of lvmd.  Tests can change it while the server is running.
+	Backend *fake.Backend
+	// Socket is the path of the UNIX domain socket.
+	Socket string
+
+	server *grpc.Server
+}
+
+// NewServer starts lvmd for deviceClasses with b on "lvmd.sock" in dir.
+// The caller should call Close to stop the server.
+func NewServer(dir string, b *fake.Backend, deviceClasses []*lvmd.DeviceClass) (*Server, error) {
+	if err := lvmd.ValidateDeviceClasses(deviceClasses); err != nil {
+		return nil, err
+	}
+
+	socket := filepath.Join(dir, "lvmd.sock")
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := lvmd.NewDeviceClassManager(deviceClasses)
+	vgService, notifier := lvmd.NewVGService(manager, b)
+	healthServer := health.NewServer()
+	lvmd.UpdateHealth(context.Background(), b, manager, healthServer)
+
+	server := grpc.NewServer()
+	proto.RegisterVGServiceServer(server, vgService)
+	proto.RegisterLVServiceServer(server, lvmd.NewLVService(manager, b, notifier))
+	proto.RegisterPVServiceServer(server, lvmd.NewPVService(manager, b, notifier))
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+
+	return &Server{
+		Backend: b,
+		Socket:  socket,
+		server:  server,
+	}, nil
+}
+
+// Dial connects to the server as topolvm-node and lvmctl do.
+func (s *Server) Dial() (*grpc.ClientConn, error) {
+	dialer := &net.Dialer{}
+	dialFunc := func(ctx context.Context, a string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", a)
+	}
+	return grpc.Dial(s.Socket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialFunc))
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.server.Stop()
+}
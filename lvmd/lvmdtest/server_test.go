@@ -0,0 +1,69 @@
+package lvmdtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm/lvmd"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestServer(t *testing.T) {
+	b := fake.New()
+	// 10 GiB is free after the default spare capacity.
+	b.AddPhysicalVolume("vg", "/dev/fake1", 20<<30)
+	s, err := NewServer(t.TempDir(), b, []*lvmd.DeviceClass{{Name: "ssd", VolumeGroup: "vg", Default: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	conn, err := s.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = proto.NewLVServiceClient(conn).CreateLV(ctx, &proto.CreateLVRequest{Name: "lv1", DeviceClass: "ssd", SizeGb: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := proto.NewVGServiceClient(conn).GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetFreeBytes() != 8<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(8<<30), res.GetFreeBytes())
+	}
+
+	// the backend is shared with the test.
+	vg, err := s.Backend.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vg.FindVolume(ctx, "lv1"); err != nil {
+		t.Error(err)
+	}
+
+	pvs, err := proto.NewPVServiceClient(conn).GetPVList(ctx, &proto.GetPVListRequest{DeviceClass: "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pvs.GetVolumes()) != 1 {
+		t.Errorf("expected 1 physical volume, but actual %v", pvs.GetVolumes())
+	}
+
+	health, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected %s, but actual %s", healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+	}
+}
@@ -2,18 +2,23 @@ package lvmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/cybozu-go/log"
-	"github.com/topolvm/topolvm/lvmd/command"
+	"github.com/topolvm/topolvm/lvmd/backend"
 	"github.com/topolvm/topolvm/lvmd/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// importedFromTagPrefix is the prefix of the tag that records the original name of an imported logical volume.
+const importedFromTagPrefix = "topolvm.cybozu.com/imported-from="
+
 // NewLVService creates a new LVServiceServer
-func NewLVService(mapper *DeviceClassManager, notifyFunc func()) proto.LVServiceServer {
+func NewLVService(mapper *DeviceClassManager, b backend.Backend, notifyFunc func()) proto.LVServiceServer {
 	return &lvService{
 		mapper:     mapper,
+		backend:    b,
 		notifyFunc: notifyFunc,
 	}
 }
@@ -21,6 +26,7 @@ func NewLVService(mapper *DeviceClassManager, notifyFunc func()) proto.LVService
 type lvService struct {
 	proto.UnimplementedLVServiceServer
 	mapper     *DeviceClassManager
+	backend    backend.Backend
 	notifyFunc func()
 }
 
@@ -31,22 +37,51 @@ func (s *lvService) notify() {
 	s.notifyFunc()
 }
 
-func (s *lvService) CreateLV(_ context.Context, req *proto.CreateLVRequest) (*proto.CreateLVResponse, error) {
+func (s *lvService) CreateLV(ctx context.Context, req *proto.CreateLVRequest) (*proto.CreateLVResponse, error) {
 	dc, err := s.mapper.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
 	}
-	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	requested := req.GetSizeGb() << 30
-	free, err := vg.Free()
+
+	// CreateLV is idempotent so that clients can retry it safely.
+	existing, err := s.findExistingLV(ctx, dc, vg, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.Size()>>30 != req.GetSizeGb() {
+			log.Error("logical volume already exists with a different size", map[string]interface{}{
+				"name":      req.GetName(),
+				"requested": requested,
+				"current":   existing.Size(),
+			})
+			return nil, status.Errorf(codes.AlreadyExists, "logical volume %s already exists with a different size: current=%d, requested=%d", req.GetName(), existing.Size(), requested)
+		}
+		log.Info("LV already exists", map[string]interface{}{
+			"name": req.GetName(),
+			"size": existing.Size(),
+		})
+		return &proto.CreateLVResponse{
+			Volume: &proto.LogicalVolume{
+				Name:     existing.Name(),
+				SizeGb:   existing.Size() >> 30,
+				DevMajor: existing.MajorNumber(),
+				DevMinor: existing.MinorNumber(),
+			},
+		}, nil
+	}
+
+	free, err := vg.Free(ctx)
 	if err != nil {
 		log.Error("failed to free VG", map[string]interface{}{
 			log.FnError: err,
 		})
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 
 	if free < requested {
@@ -62,14 +97,14 @@ func (s *lvService) CreateLV(_ context.Context, req *proto.CreateLVRequest) (*pr
 		stripe = *dc.Stripe
 	}
 
-	lv, err := vg.CreateVolume(req.GetName(), requested, req.GetTags(), stripe, dc.StripeSize)
+	lv, err := vg.CreateVolume(ctx, req.GetName(), requested, req.GetTags(), stripe, dc.StripeSize)
 	if err != nil {
 		log.Error("failed to create volume", map[string]interface{}{
 			"name":      req.GetName(),
 			"requested": requested,
 			"tags":      req.GetTags(),
 		})
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 	s.notify()
 
@@ -88,21 +123,66 @@ func (s *lvService) CreateLV(_ context.Context, req *proto.CreateLVRequest) (*pr
 	}, nil
 }
 
-func (s *lvService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*proto.Empty, error) {
+// findExistingLV returns the named LV in vg of dc, or nil if it does not exist.
+// If the LV exists in another device-class, it returns AlreadyExists.
+func (s *lvService) findExistingLV(ctx context.Context, dc *DeviceClass, vg backend.VolumeGroup, name string) (backend.LogicalVolume, error) {
+	lv, err := vg.FindVolume(ctx, name)
+	if err == nil {
+		return lv, nil
+	}
+	if err != backend.ErrNotFound {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      name,
+		})
+		return nil, statusError(err)
+	}
+
+	for _, other := range s.mapper.DeviceClasses() {
+		if other.VolumeGroup == dc.VolumeGroup {
+			continue
+		}
+		otherVG, err := s.backend.FindVolumeGroup(ctx, other.VolumeGroup)
+		if err != nil {
+			continue
+		}
+		_, err = otherVG.FindVolume(ctx, name)
+		if err == backend.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			log.Error("failed to find volume", map[string]interface{}{
+				log.FnError:    err,
+				"name":         name,
+				"device_class": other.Name,
+			})
+			return nil, statusError(err)
+		}
+		log.Error("logical volume already exists in another device-class", map[string]interface{}{
+			"name":         name,
+			"device_class": other.Name,
+		})
+		return nil, status.Errorf(codes.AlreadyExists, "logical volume %s already exists in device-class %s", name, other.Name)
+	}
+	return nil, nil
+}
+
+// RemoveLV removes the named LV.  Removing a missing LV succeeds so that clients can retry it safely.
+func (s *lvService) RemoveLV(ctx context.Context, req *proto.RemoveLVRequest) (*proto.Empty, error) {
 	dc, err := s.mapper.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
 	}
-	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	lvs, err := vg.ListVolumes()
+	lvs, err := vg.ListVolumes(ctx)
 	if err != nil {
 		log.Error("failed to list volumes", map[string]interface{}{
 			log.FnError: err,
 		})
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 
 	for _, lv := range lvs {
@@ -110,13 +190,41 @@ func (s *lvService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*pr
 			continue
 		}
 
-		err = lv.Remove()
+		if retention := dc.GetTrashRetention(); retention > 0 {
+			err = moveToTrash(ctx, lv, time.Now())
+			if err != nil {
+				log.Error("failed to move volume to the trash", map[string]interface{}{
+					log.FnError: err,
+					"name":      req.GetName(),
+				})
+				return nil, statusError(err)
+			}
+			s.notify()
+
+			log.Info("moved a LV to the trash", map[string]interface{}{
+				"name":      req.GetName(),
+				"retention": retention.String(),
+			})
+			break
+		}
+
+		err = wipe(ctx, lv, dc.WipePolicy, nil)
+		if err != nil {
+			log.Error("failed to wipe volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      lv.Name(),
+				"policy":    dc.WipePolicy,
+			})
+			return nil, statusError(err)
+		}
+
+		err = lv.Remove(ctx)
 		if err != nil {
 			log.Error("failed to remove volume", map[string]interface{}{
 				log.FnError: err,
 				"name":      lv.Name(),
 			})
-			return nil, status.Error(codes.Internal, err.Error())
+			return nil, statusError(err)
 		}
 		s.notify()
 
@@ -129,17 +237,17 @@ func (s *lvService) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*pr
 	return &proto.Empty{}, nil
 }
 
-func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*proto.Empty, error) {
+func (s *lvService) ResizeLV(ctx context.Context, req *proto.ResizeLVRequest) (*proto.Empty, error) {
 	dc, err := s.mapper.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
 	}
-	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	lv, err := vg.FindVolume(req.GetName())
-	if err == command.ErrNotFound {
+	lv, err := vg.FindVolume(ctx, req.GetName())
+	if err == backend.ErrNotFound {
 		log.Error("logical volume is not found", map[string]interface{}{
 			log.FnError: err,
 			"name":      req.GetName(),
@@ -151,12 +259,16 @@ func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*pr
 			log.FnError: err,
 			"name":      req.GetName(),
 		})
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 
 	requested := req.GetSizeGb() << 30
 	current := lv.Size()
 
+	// ResizeLV is idempotent so that clients can retry it safely.
+	if current>>30 == req.GetSizeGb() {
+		return &proto.Empty{}, nil
+	}
 	if requested < current {
 		log.Error("shrinking volume size is not allowed", map[string]interface{}{
 			log.FnError: err,
@@ -167,13 +279,13 @@ func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*pr
 		return nil, status.Error(codes.OutOfRange, "shrinking volume size is not allowed")
 	}
 
-	free, err := vg.Free()
+	free, err := vg.Free(ctx)
 	if err != nil {
 		log.Error("failed to free VG", map[string]interface{}{
 			log.FnError: err,
 			"name":      req.GetName(),
 		})
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 	if free < (requested - current) {
 		log.Error("no enough space left on VG", map[string]interface{}{
@@ -186,7 +298,7 @@ func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*pr
 		return nil, status.Errorf(codes.ResourceExhausted, "no enough space left on VG: free=%d, requested=%d", free, requested-current)
 	}
 
-	err = lv.Resize(requested)
+	err = lv.Resize(ctx, requested)
 	if err != nil {
 		log.Error("failed to resize LV", map[string]interface{}{
 			log.FnError: err,
@@ -195,7 +307,7 @@ func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*pr
 			"current":   current,
 			"free":      free,
 		})
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 	s.notify()
 
@@ -206,3 +318,227 @@ func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*pr
 
 	return &proto.Empty{}, nil
 }
+
+func (s *lvService) RestoreLV(ctx context.Context, req *proto.RestoreLVRequest) (*proto.RestoreLVResponse, error) {
+	dc, err := s.mapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	lv, err := vg.FindVolume(ctx, trashName(req.GetOriginalName()))
+	if err == backend.ErrNotFound {
+		log.Error("logical volume is not found in the trash", map[string]interface{}{
+			log.FnError:     err,
+			"original_name": req.GetOriginalName(),
+		})
+		return nil, status.Errorf(codes.NotFound, "logical volume %s is not found in the trash", req.GetOriginalName())
+	}
+	if err != nil {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError:     err,
+			"original_name": req.GetOriginalName(),
+		})
+		return nil, statusError(err)
+	}
+	if _, ok := trashedAt(lv); !ok {
+		return nil, status.Errorf(codes.NotFound, "logical volume %s is not found in the trash", req.GetOriginalName())
+	}
+	if req.GetSizeGb() != 0 && lv.Size()>>30 != req.GetSizeGb() {
+		return nil, status.Errorf(codes.InvalidArgument, "logical volume %s in the trash has %d GiB, but %d GiB is requested",
+			req.GetOriginalName(), lv.Size()>>30, req.GetSizeGb())
+	}
+
+	_, err = vg.FindVolume(ctx, req.GetName())
+	if err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "logical volume %s already exists", req.GetName())
+	}
+	if err != backend.ErrNotFound {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return nil, statusError(err)
+	}
+
+	err = restoreFromTrash(ctx, lv, req.GetName())
+	if err != nil {
+		log.Error("failed to restore volume from the trash", map[string]interface{}{
+			log.FnError:     err,
+			"name":          req.GetName(),
+			"original_name": req.GetOriginalName(),
+		})
+		return nil, statusError(err)
+	}
+	s.notify()
+
+	log.Info("restored a LV from the trash", map[string]interface{}{
+		"name":          req.GetName(),
+		"original_name": req.GetOriginalName(),
+	})
+
+	return &proto.RestoreLVResponse{
+		Volume: &proto.LogicalVolume{
+			Name:     lv.Name(),
+			SizeGb:   lv.Size() >> 30,
+			DevMajor: lv.MajorNumber(),
+			DevMinor: lv.MinorNumber(),
+			Tags:     lv.Tags(),
+		},
+	}, nil
+}
+
+func (s *lvService) WipeLV(req *proto.WipeLVRequest, server proto.LVService_WipeLVServer) error {
+	ctx := server.Context()
+	dc, err := s.mapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	// Volumes moved to the trash are wiped when they are purged.
+	if dc.WipePolicy == "" || dc.GetTrashRetention() > 0 {
+		return nil
+	}
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
+	if err != nil {
+		return statusError(err)
+	}
+	lv, err := vg.FindVolume(ctx, req.GetName())
+	if err == backend.ErrNotFound {
+		log.Error("logical volume is not found", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return status.Errorf(codes.NotFound, "logical volume %s is not found", req.GetName())
+	}
+	if err != nil {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return statusError(err)
+	}
+
+	err = wipe(ctx, lv, dc.WipePolicy, func(wiped, total uint64) error {
+		return server.Send(&proto.WipeLVResponse{
+			WipedBytes: wiped,
+			TotalBytes: total,
+		})
+	})
+	if err != nil {
+		log.Error("failed to wipe volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+			"policy":    dc.WipePolicy,
+		})
+		return statusError(err)
+	}
+
+	log.Info("wiped a LV", map[string]interface{}{
+		"name":   req.GetName(),
+		"policy": dc.WipePolicy,
+	})
+	return nil
+}
+
+func (s *lvService) ImportLV(ctx context.Context, req *proto.ImportLVRequest) (*proto.ImportLVResponse, error) {
+	dc, err := s.mapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	lv, err := vg.FindVolume(ctx, req.GetSourceName())
+	if err == backend.ErrNotFound {
+		log.Error("logical volume is not found", map[string]interface{}{
+			log.FnError:   err,
+			"source_name": req.GetSourceName(),
+		})
+		return nil, status.Errorf(codes.NotFound, "logical volume %s is not found", req.GetSourceName())
+	}
+	if err != nil {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError:   err,
+			"source_name": req.GetSourceName(),
+		})
+		return nil, statusError(err)
+	}
+
+	if _, ok := trashedAt(lv); ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "logical volume %s is in the trash", req.GetSourceName())
+	}
+	if lv.IsSnapshot() || lv.IsThin() {
+		return nil, status.Errorf(codes.FailedPrecondition, "logical volume %s is not a linear volume", req.GetSourceName())
+	}
+	if lv.Size()%(1<<30) != 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "size of logical volume %s is not a multiple of 1 GiB: %d", req.GetSourceName(), lv.Size())
+	}
+	if req.GetSizeGb() != 0 && lv.Size()>>30 != req.GetSizeGb() {
+		return nil, status.Errorf(codes.InvalidArgument, "logical volume %s has %d GiB, but %d GiB is requested",
+			req.GetSourceName(), lv.Size()>>30, req.GetSizeGb())
+	}
+	open, err := lv.IsOpen(ctx)
+	if err != nil {
+		log.Error("failed to check whether volume is open", map[string]interface{}{
+			log.FnError:   err,
+			"source_name": req.GetSourceName(),
+		})
+		return nil, statusError(err)
+	}
+	if open {
+		return nil, status.Errorf(codes.FailedPrecondition, "logical volume %s is in use", req.GetSourceName())
+	}
+
+	_, err = vg.FindVolume(ctx, req.GetName())
+	if err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "logical volume %s already exists", req.GetName())
+	}
+	if err != backend.ErrNotFound {
+		log.Error("failed to find volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return nil, statusError(err)
+	}
+
+	tags := append([]string{importedFromTagPrefix + req.GetSourceName()}, req.GetTags()...)
+	err = lv.AddTags(ctx, tags...)
+	if err != nil {
+		log.Error("failed to tag volume", map[string]interface{}{
+			log.FnError:   err,
+			"source_name": req.GetSourceName(),
+		})
+		return nil, statusError(err)
+	}
+	err = lv.Rename(ctx, req.GetName())
+	if err != nil {
+		log.Error("failed to rename volume", map[string]interface{}{
+			log.FnError:   err,
+			"name":        req.GetName(),
+			"source_name": req.GetSourceName(),
+		})
+		return nil, statusError(err)
+	}
+	s.notify()
+
+	log.Info("imported a LV", map[string]interface{}{
+		"name":        req.GetName(),
+		"source_name": req.GetSourceName(),
+		"size":        lv.Size(),
+	})
+
+	return &proto.ImportLVResponse{
+		Volume: &proto.LogicalVolume{
+			Name:     lv.Name(),
+			SizeGb:   lv.Size() >> 30,
+			DevMajor: lv.MajorNumber(),
+			DevMinor: lv.MinorNumber(),
+			Tags:     lv.Tags(),
+		},
+	}, nil
+}
@@ -2,10 +2,14 @@ package lvmd
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"testing"
 
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
 	"github.com/topolvm/topolvm/lvmd/command"
 	"github.com/topolvm/topolvm/lvmd/proto"
 	"google.golang.org/grpc/codes"
@@ -13,6 +17,7 @@ import (
 )
 
 func TestLVService(t *testing.T) {
+	ctx := context.Background()
 	uid := os.Getuid()
 	if uid != 0 {
 		t.Skip("run as root")
@@ -30,7 +35,7 @@ func TestLVService(t *testing.T) {
 	}
 	defer CleanLoopbackVG(vgName, []string{loop}, []string{vgName})
 
-	vg, err := command.FindVolumeGroup(vgName)
+	vg, err := command.FindVolumeGroup(ctx, vgName)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -39,7 +44,7 @@ func TestLVService(t *testing.T) {
 	notifier := func() {
 		count++
 	}
-	lvService := NewLVService(NewDeviceClassManager([]*DeviceClass{{Name: vgName, VolumeGroup: vgName}}), notifier)
+	lvService := NewLVService(NewDeviceClassManager([]*DeviceClass{{Name: vgName, VolumeGroup: vgName}}), backend.NewLVM(), notifier)
 	res, err := lvService.CreateLV(context.Background(), &proto.CreateLVRequest{
 		Name:        "test1",
 		DeviceClass: vgName,
@@ -62,7 +67,7 @@ func TestLVService(t *testing.T) {
 	if err != nil {
 		t.Error("failed to create logical volume")
 	}
-	lv, err := vg.FindVolume("test1")
+	lv, err := vg.FindVolume(ctx, "test1")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -97,7 +102,7 @@ func TestLVService(t *testing.T) {
 	if count != 2 {
 		t.Errorf("unexpected count: %d", count)
 	}
-	lv, err = vg.FindVolume("test1")
+	lv, err = vg.FindVolume(ctx, "test1")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -128,9 +133,333 @@ func TestLVService(t *testing.T) {
 	if count != 3 {
 		t.Errorf("unexpected count: %d", count)
 	}
-	_, err = vg.FindVolume("test1")
-	if err != command.ErrNotFound {
+	_, err = vg.FindVolume(ctx, "test1")
+	if err != backend.ErrNotFound {
 		t.Error("unexpected error: ", err)
 	}
 
 }
+
+func TestLVServiceWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	vgName := "fake"
+	b := fake.New()
+	b.AddPhysicalVolume(vgName, "/dev/fake1", 2<<30)
+	b.AddPhysicalVolume(vgName, "/dev/fake2", 2<<30)
+
+	var count int
+	notifier := func() {
+		count++
+	}
+	b.AddPhysicalVolume("other", "/dev/fake3", 4<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{
+		{Name: vgName, VolumeGroup: vgName},
+		{Name: "other", VolumeGroup: "other"},
+	})
+	lvService := NewLVService(manager, b, notifier)
+
+	res, err := lvService.CreateLV(ctx, &proto.CreateLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+		SizeGb:      3,
+		Tags:        []string{"testtag1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("is not notified: %d", count)
+	}
+	if res.GetVolume().GetSizeGb() != 3 {
+		t.Errorf(`res.Volume.SizeGb != 3: %d`, res.GetVolume().GetSizeGb())
+	}
+
+	// retrying the same request returns the existing volume.
+	res2, err := lvService.CreateLV(ctx, &proto.CreateLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+		SizeGb:      3,
+		Tags:        []string{"testtag1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res2.GetVolume().GetDevMinor() != res.GetVolume().GetDevMinor() {
+		t.Errorf("expected %v, but actual %v", res.GetVolume(), res2.GetVolume())
+	}
+	if count != 1 {
+		t.Errorf("unexpected count: %d", count)
+	}
+
+	_, err = lvService.CreateLV(ctx, &proto.CreateLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+		SizeGb:      1,
+	})
+	if code := status.Code(err); code != codes.AlreadyExists {
+		t.Errorf(`code is not codes.AlreadyExists: %s`, code)
+	}
+
+	_, err = lvService.CreateLV(ctx, &proto.CreateLVRequest{
+		Name:        "test1",
+		DeviceClass: "other",
+		SizeGb:      3,
+	})
+	if code := status.Code(err); code != codes.AlreadyExists {
+		t.Errorf(`code is not codes.AlreadyExists: %s`, code)
+	}
+
+	_, err = lvService.ResizeLV(ctx, &proto.ResizeLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+		SizeGb:      5,
+	})
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Errorf(`code is not codes.ResouceExhausted: %s`, code)
+	}
+
+	b.InjectFailure(fake.OpResize, &command.LVMError{Command: "lvresize", Stderr: "Can't get lock for fake", Err: errors.New("exit status 5")}, 1)
+	_, err = lvService.ResizeLV(ctx, &proto.ResizeLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+		SizeGb:      4,
+	})
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf(`code is not codes.Unavailable: %s`, code)
+	}
+	if count != 1 {
+		t.Errorf("unexpected count: %d", count)
+	}
+	_, err = lvService.ResizeLV(ctx, &proto.ResizeLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+		SizeGb:      4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected count: %d", count)
+	}
+
+	// resizing to the current size succeeds without resizing.
+	b.InjectFailure(fake.OpResize, errors.New("unexpected resize"), 0)
+	_, err = lvService.ResizeLV(ctx, &proto.ResizeLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+		SizeGb:      4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.ClearFailures()
+	if count != 2 {
+		t.Errorf("unexpected count: %d", count)
+	}
+
+	_, err = lvService.RemoveLV(ctx, &proto.RemoveLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("unexpected count: %d", count)
+	}
+
+	// removing a missing volume succeeds.
+	_, err = lvService.RemoveLV(ctx, &proto.RemoveLVRequest{
+		Name:        "test1",
+		DeviceClass: vgName,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("unexpected count: %d", count)
+	}
+	vg, err := b.FindVolumeGroup(ctx, vgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.FindVolume(ctx, "test1")
+	if err != backend.ErrNotFound {
+		t.Error("unexpected error: ", err)
+	}
+	free, err := vg.Free(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if free != 4<<30 {
+		t.Errorf("unexpected free bytes: %d", free)
+	}
+}
+
+func TestImportLVWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 20<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "vg"}})
+
+	var count int
+	lvService := NewLVService(manager, b, func() {
+		count++
+	})
+
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tags := range map[string][]string{
+		"data":      {"foo"},
+		"opened":    nil,
+		"exists":    nil,
+		"trash-old": {topolvm.TrashedAtTagPrefix + "1650000000"},
+	} {
+		_, err := vg.CreateVolume(ctx, name, 2<<30, tags, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = b.SetOpen("vg", "opened", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := vg.CreatePool(ctx, "pool", 4<<30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pool.CreateVolume(ctx, "thin", 2<<30)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		source string
+		sizeGb uint64
+		code   codes.Code
+	}{
+		{"new", "missing", 2, codes.NotFound},
+		{"new", "trash-old", 2, codes.FailedPrecondition},
+		{"new", "thin", 2, codes.FailedPrecondition},
+		{"new", "opened", 2, codes.FailedPrecondition},
+		{"new", "data", 3, codes.InvalidArgument},
+		{"exists", "data", 2, codes.AlreadyExists},
+	}
+	for _, tc := range testCases {
+		_, err := lvService.ImportLV(ctx, &proto.ImportLVRequest{
+			Name:        tc.name,
+			SourceName:  tc.source,
+			DeviceClass: "ssd",
+			SizeGb:      tc.sizeGb,
+		})
+		if code := status.Code(err); code != tc.code {
+			t.Errorf("%s: expected %s, but actual %v", tc.source, tc.code, err)
+		}
+	}
+	if count != 0 {
+		t.Errorf("unexpected count: %d", count)
+	}
+
+	res, err := lvService.ImportLV(ctx, &proto.ImportLVRequest{
+		Name:        "new",
+		SourceName:  "data",
+		DeviceClass: "ssd",
+		SizeGb:      2,
+		Tags:        []string{topolvm.OwnedTag},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("is not notified: %d", count)
+	}
+	if res.Volume.Name != "new" || res.Volume.SizeGb != 2 {
+		t.Errorf("unexpected volume: %v", res.Volume)
+	}
+	_, err = vg.FindVolume(ctx, "data")
+	if err != backend.ErrNotFound {
+		t.Errorf("source volume should be renamed: %v", err)
+	}
+	lv, err := vg.FindVolume(ctx, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"foo", importedFromTagPrefix + "data", topolvm.OwnedTag} {
+		if !containsTag(lv.Tags(), tag) {
+			t.Errorf("tag %s is not found: %v", tag, lv.Tags())
+		}
+	}
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+type wipeLVServer struct {
+	proto.LVService_WipeLVServer
+	ctx context.Context
+}
+
+func (s *wipeLVServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *wipeLVServer) Send(*proto.WipeLVResponse) error {
+	return nil
+}
+
+func TestFindVolumeGroupErrorWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 4<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "vg", Default: true, WipePolicy: WipePolicyDiscard}})
+	lvService := NewLVService(manager, b, nil)
+
+	testCases := []struct {
+		name string
+		call func() error
+	}{
+		{"RemoveLV", func() error {
+			_, err := lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: "lv", DeviceClass: "ssd"})
+			return err
+		}},
+		{"ResizeLV", func() error {
+			_, err := lvService.ResizeLV(ctx, &proto.ResizeLVRequest{Name: "lv", DeviceClass: "ssd", SizeGb: 2})
+			return err
+		}},
+		{"RestoreLV", func() error {
+			_, err := lvService.RestoreLV(ctx, &proto.RestoreLVRequest{Name: "lv", DeviceClass: "ssd", OriginalName: "lv"})
+			return err
+		}},
+		{"ImportLV", func() error {
+			_, err := lvService.ImportLV(ctx, &proto.ImportLVRequest{Name: "lv", DeviceClass: "ssd", SourceName: "data"})
+			return err
+		}},
+		{"WipeLV", func() error {
+			return lvService.WipeLV(&proto.WipeLVRequest{Name: "lv", DeviceClass: "ssd"}, &wipeLVServer{ctx: ctx})
+		}},
+	}
+
+	lockErr := &command.LVMError{Command: "vgs", Stderr: "Can't get lock for vg", Err: errors.New("exit status 5")}
+	for _, tc := range testCases {
+		b.InjectFailure(fake.OpFindVolumeGroup, errors.New("unexpected"), 1)
+		err := tc.call()
+		if _, ok := status.FromError(err); !ok || status.Code(err) != codes.Internal {
+			t.Errorf("%s: expected a status error with %s, but actual %v", tc.name, codes.Internal, err)
+		}
+
+		b.InjectFailure(fake.OpFindVolumeGroup, lockErr, 1)
+		err = tc.call()
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("%s: expected %s, but actual %v", tc.name, codes.Unavailable, err)
+		}
+	}
+}
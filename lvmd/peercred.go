@@ -0,0 +1,293 @@
+package lvmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/cybozu-go/log"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// Roles of peers connecting to the UNIX domain socket, and of clients connecting over TCP.
+const (
+	// RoleReadWrite permits all services.
+	RoleReadWrite = "read-write"
+	// RoleReadOnly permits only VGService except for SetSpare, health checking and server reflection.
+	RoleReadOnly = "read-only"
+)
+
+// readOnlyServices are the services permitted for RoleReadOnly.
+var readOnlyServices = []string{
+	proto.VGService_ServiceDesc.ServiceName,
+	healthpb.Health_ServiceDesc.ServiceName,
+	reflectionpb.ServerReflection_ServiceDesc.ServiceName,
+}
+
+// setSpareMethod changes the state of lvmd even though it belongs to VGService.
+var setSpareMethod = "/" + proto.VGService_ServiceDesc.ServiceName + "/SetSpare"
+
+func isReadOnlyMethod(method string) bool {
+	if method == setSpareMethod {
+		return false
+	}
+	for _, svc := range readOnlyServices {
+		if strings.HasPrefix(method, "/"+svc+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// PeerRule grants a role to processes connecting to the UNIX domain socket.
+// If both UID and GID are specified, a process matches the rule only if it has both.
+type PeerRule struct {
+	// UID is the user ID of the process.
+	UID *uint32 `json:"uid"`
+	// GID is the group ID of the process.
+	GID *uint32 `json:"gid"`
+	// Role is either "read-write" or "read-only".  The default is "read-write".
+	Role string `json:"role"`
+}
+
+func (r *PeerRule) role() string {
+	return roleOrDefault(r.Role)
+}
+
+func roleOrDefault(role string) string {
+	if role == "" {
+		return RoleReadWrite
+	}
+	return role
+}
+
+func validateRole(role string) error {
+	switch roleOrDefault(role) {
+	case RoleReadWrite, RoleReadOnly:
+		return nil
+	}
+	return fmt.Errorf("role should be %q or %q: %s", RoleReadWrite, RoleReadOnly, role)
+}
+
+func (r *PeerRule) matches(info PeerAuthInfo) bool {
+	if r.UID != nil && *r.UID != info.UID {
+		return false
+	}
+	if r.GID != nil && *r.GID != info.GID {
+		return false
+	}
+	return true
+}
+
+// ValidatePeerRules validates rules.
+func ValidatePeerRules(rules []*PeerRule) error {
+	for i, r := range rules {
+		if r.UID == nil && r.GID == nil {
+			return fmt.Errorf("allowed-peers[%d]: uid or gid should be specified", i)
+		}
+		if err := validateRole(r.Role); err != nil {
+			return fmt.Errorf("allowed-peers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ClientRule grants a role to clients connecting over TCP with mutual TLS.
+// A client matches the rule if the common name of its certificate is CommonName.
+type ClientRule struct {
+	// CommonName is the common name of the subject of the client certificate.
+	CommonName string `json:"common-name"`
+	// Role is either "read-write" or "read-only".  The default is "read-write".
+	Role string `json:"role"`
+}
+
+// ValidateClientRules validates rules.
+func ValidateClientRules(rules []*ClientRule) error {
+	for i, r := range rules {
+		if r.CommonName == "" {
+			return fmt.Errorf("allowed-clients[%d]: common-name should be specified", i)
+		}
+		if err := validateRole(r.Role); err != nil {
+			return fmt.Errorf("allowed-clients[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// PeerAuthInfo is the credential of a process connecting to the UNIX domain socket.
+type PeerAuthInfo struct {
+	credentials.CommonAuthInfo
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// AuthType implements credentials.AuthInfo.
+func (PeerAuthInfo) AuthType() string {
+	return "peercred"
+}
+
+type peerCredentials struct{}
+
+// NewPeerCredentials returns credentials for gRPC servers listening on a UNIX domain socket.
+// It does not encrypt connections, but obtains the credentials of peers with SO_PEERCRED.
+func NewPeerCredentials() credentials.TransportCredentials {
+	return peerCredentials{}
+}
+
+func (peerCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("peer credentials are only for servers")
+}
+
+func (peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil, nil, fmt.Errorf("peer credentials are not available for %s connections", conn.LocalAddr().Network())
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	var cred *unix.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if credErr != nil {
+		return nil, nil, credErr
+	}
+	return conn, PeerAuthInfo{
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity},
+		PID:            cred.Pid,
+		UID:            cred.Uid,
+		GID:            cred.Gid,
+	}, nil
+}
+
+func (peerCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+}
+
+func (c peerCredentials) Clone() credentials.TransportCredentials {
+	return c
+}
+
+func (peerCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+type peerAuthorizer struct {
+	peers   []*PeerRule
+	clients []*ClientRule
+}
+
+// NewPeerAuthorizer returns gRPC interceptors that authorize requests by the credentials of peers.
+// The server must be created with NewPeerCredentials.
+func NewPeerAuthorizer(rules []*PeerRule) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	a := &peerAuthorizer{peers: rules}
+	return a.unary, a.stream
+}
+
+// NewClientAuthorizer returns gRPC interceptors that authorize requests by the certificates of clients.
+// The server must be created with TLS credentials that verify client certificates.
+func NewClientAuthorizer(rules []*ClientRule) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	a := &peerAuthorizer{clients: rules}
+	return a.unary, a.stream
+}
+
+// peerRole returns the role granted to info.  If no rules match, it returns an empty string.
+func (a *peerAuthorizer) peerRole(info PeerAuthInfo) string {
+	var role string
+	for _, r := range a.peers {
+		if !r.matches(info) {
+			continue
+		}
+		if r.role() == RoleReadWrite {
+			return RoleReadWrite
+		}
+		role = r.role()
+	}
+	return role
+}
+
+// clientRole returns the role granted to the client of commonName.  If no rules match, it returns an empty string.
+func (a *peerAuthorizer) clientRole(commonName string) string {
+	var role string
+	for _, r := range a.clients {
+		if r.CommonName != commonName {
+			continue
+		}
+		if roleOrDefault(r.Role) == RoleReadWrite {
+			return RoleReadWrite
+		}
+		role = roleOrDefault(r.Role)
+	}
+	return role
+}
+
+func (a *peerAuthorizer) authorize(ctx context.Context, method string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "no peer information")
+	}
+
+	var role, who string
+	var fields map[string]interface{}
+	switch info := p.AuthInfo.(type) {
+	case PeerAuthInfo:
+		role = a.peerRole(info)
+		who = fmt.Sprintf("uid=%d gid=%d", info.UID, info.GID)
+		fields = map[string]interface{}{
+			"pid": info.PID,
+			"uid": info.UID,
+			"gid": info.GID,
+		}
+	case credentials.TLSInfo:
+		if len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+			return status.Error(codes.PermissionDenied, "no verified client certificate")
+		}
+		commonName := info.State.VerifiedChains[0][0].Subject.CommonName
+		role = a.clientRole(commonName)
+		who = fmt.Sprintf("client %q", commonName)
+		fields = map[string]interface{}{
+			"common_name": commonName,
+		}
+	default:
+		return status.Error(codes.PermissionDenied, "no peer credentials")
+	}
+
+	if role == RoleReadWrite || (role == RoleReadOnly && isReadOnlyMethod(method)) {
+		return nil
+	}
+	fields["method"] = method
+	fields["role"] = role
+	log.Warn("denied a request from an unauthorized peer", fields)
+	return status.Errorf(codes.PermissionDenied, "%s is not permitted to call %s", who, method)
+}
+
+func (a *peerAuthorizer) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *peerAuthorizer) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,217 @@
+package lvmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func TestValidatePeerRules(t *testing.T) {
+	testCases := []struct {
+		rules []*PeerRule
+		valid bool
+	}{
+		{nil, true},
+		{[]*PeerRule{{UID: uint32Ptr(0)}}, true},
+		{[]*PeerRule{{GID: uint32Ptr(0), Role: RoleReadOnly}}, true},
+		{[]*PeerRule{{UID: uint32Ptr(0), GID: uint32Ptr(0), Role: RoleReadWrite}}, true},
+		{[]*PeerRule{{Role: RoleReadOnly}}, false},
+		{[]*PeerRule{{UID: uint32Ptr(0), Role: "admin"}}, false},
+	}
+	for i, tc := range testCases {
+		err := ValidatePeerRules(tc.rules)
+		if tc.valid && err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+	}
+}
+
+func TestPeerAuthorizer(t *testing.T) {
+	uid := uint32(os.Getuid())
+	gid := uint32(os.Getgid())
+
+	testCases := []struct {
+		name     string
+		rules    []*PeerRule
+		readable bool
+		writable bool
+	}{
+		{"read-write by uid", []*PeerRule{{UID: &uid}}, true, true},
+		{"read-only by gid", []*PeerRule{{GID: &gid, Role: RoleReadOnly}}, true, false},
+		{"read-write wins", []*PeerRule{{UID: &uid, Role: RoleReadOnly}, {GID: &gid}}, true, true},
+		{"uid and gid", []*PeerRule{{UID: &uid, GID: uint32Ptr(gid + 1)}}, false, false},
+		{"other uid", []*PeerRule{{UID: uint32Ptr(uid + 1)}}, false, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := fake.New()
+			b.AddPhysicalVolume("vg", "/dev/fake1", 10<<30)
+			manager := NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "vg", Default: true}})
+			vgService, notifier := NewVGService(manager, b)
+			unary, stream := NewPeerAuthorizer(tc.rules)
+			server := grpc.NewServer(grpc.Creds(NewPeerCredentials()), grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+			proto.RegisterVGServiceServer(server, vgService)
+			proto.RegisterLVServiceServer(server, NewLVService(manager, b, notifier))
+			healthpb.RegisterHealthServer(server, health.NewServer())
+
+			socket := filepath.Join(t.TempDir(), "lvmd.sock")
+			lis, err := net.Listen("unix", socket)
+			if err != nil {
+				t.Fatal(err)
+			}
+			go server.Serve(lis)
+			defer server.Stop()
+
+			dialer := &net.Dialer{}
+			dialFunc := func(ctx context.Context, a string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", a)
+			}
+			conn, err := grpc.Dial(socket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialFunc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err = proto.NewVGServiceClient(conn).GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: "ssd"})
+			checkPermission(t, "GetFreeBytes", err, tc.readable)
+
+			_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+			checkPermission(t, "Check", err, tc.readable)
+
+			watch, err := proto.NewVGServiceClient(conn).Watch(ctx, &proto.WatchRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = watch.Recv()
+			checkPermission(t, "Watch", err, tc.readable)
+
+			_, err = proto.NewLVServiceClient(conn).CreateLV(ctx, &proto.CreateLVRequest{Name: "lv1", DeviceClass: "ssd", SizeGb: 1})
+			checkPermission(t, "CreateLV", err, tc.writable)
+
+			_, err = proto.NewVGServiceClient(conn).SetSpare(ctx, &proto.SetSpareRequest{DeviceClass: "ssd", Spare: "1%"})
+			checkPermission(t, "SetSpare", err, tc.writable)
+		})
+	}
+}
+
+func TestValidateClientRules(t *testing.T) {
+	testCases := []struct {
+		rules []*ClientRule
+		valid bool
+	}{
+		{[]*ClientRule{{CommonName: "topolvm-node"}}, true},
+		{[]*ClientRule{{CommonName: "monitoring", Role: RoleReadOnly}}, true},
+		{[]*ClientRule{{Role: RoleReadWrite}}, false},
+		{[]*ClientRule{{CommonName: "topolvm-node", Role: "admin"}}, false},
+	}
+	for i, tc := range testCases {
+		err := ValidateClientRules(tc.rules)
+		if tc.valid && err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+	}
+}
+
+func TestClientAuthorizer(t *testing.T) {
+	const (
+		readMethod  = "/proto.VGService/GetFreeBytes"
+		writeMethod = "/proto.LVService/CreateLV"
+	)
+	rules := []*ClientRule{
+		{CommonName: "topolvm-node"},
+		{CommonName: "monitoring", Role: RoleReadOnly},
+		{CommonName: "both", Role: RoleReadOnly},
+		{CommonName: "both"},
+	}
+
+	testCases := []struct {
+		name       string
+		commonName string
+		readable   bool
+		writable   bool
+	}{
+		{"read-write", "topolvm-node", true, true},
+		{"read-only", "monitoring", true, false},
+		{"read-write wins", "both", true, true},
+		{"unknown client", "other", false, false},
+	}
+	unary, stream := NewClientAuthorizer(rules)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &proto.Empty{}, nil
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: tc.commonName}}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				AuthInfo: credentials.TLSInfo{
+					State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+				},
+			})
+
+			_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: readMethod}, handler)
+			checkPermission(t, "GetFreeBytes", err, tc.readable)
+
+			_, err = unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: writeMethod}, handler)
+			checkPermission(t, "CreateLV", err, tc.writable)
+
+			err = stream(nil, &authorizerTestStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/proto.VGService/Watch"},
+				func(interface{}, grpc.ServerStream) error { return nil })
+			checkPermission(t, "Watch", err, tc.readable)
+		})
+	}
+
+	t.Run("no client certificate", func(t *testing.T) {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}})
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: readMethod}, handler)
+		checkPermission(t, "GetFreeBytes", err, false)
+	})
+}
+
+type authorizerTestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizerTestStream) Context() context.Context {
+	return s.ctx
+}
+
+func checkPermission(t *testing.T, method string, err error, permitted bool) {
+	t.Helper()
+	if permitted && err != nil {
+		t.Errorf("%s: unexpected error: %v", method, err)
+	}
+	if !permitted && status.Code(err) != codes.PermissionDenied {
+		t.Errorf("%s: expected %s, but actual %v", method, codes.PermissionDenied, err)
+	}
+}
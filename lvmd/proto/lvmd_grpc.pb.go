@@ -23,11 +23,20 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LVServiceClient interface {
 	// Create a logical volume.
+	// If the volume already exists with the same size in the device-class, it is returned as is.
+	// Otherwise, ALREADY_EXISTS is returned.
 	CreateLV(ctx context.Context, in *CreateLVRequest, opts ...grpc.CallOption) (*CreateLVResponse, error)
-	// Remove a logical volume.
+	// Remove a logical volume.  Removing a missing volume succeeds.
 	RemoveLV(ctx context.Context, in *RemoveLVRequest, opts ...grpc.CallOption) (*Empty, error)
-	// Resize a logical volume.
+	// Resize a logical volume.  Resizing to the current size succeeds without changes.
 	ResizeLV(ctx context.Context, in *ResizeLVRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Restore a logical volume from the trash.
+	RestoreLV(ctx context.Context, in *RestoreLVRequest, opts ...grpc.CallOption) (*RestoreLVResponse, error)
+	// Import an existing logical volume by renaming it.
+	ImportLV(ctx context.Context, in *ImportLVRequest, opts ...grpc.CallOption) (*ImportLVResponse, error)
+	// Wipe a logical volume according to the wipe policy of the device-class.
+	// The progress is streamed while wiping.
+	WipeLV(ctx context.Context, in *WipeLVRequest, opts ...grpc.CallOption) (LVService_WipeLVClient, error)
 }
 
 type lVServiceClient struct {
@@ -65,16 +74,75 @@ func (c *lVServiceClient) ResizeLV(ctx context.Context, in *ResizeLVRequest, opt
 	return out, nil
 }
 
+func (c *lVServiceClient) RestoreLV(ctx context.Context, in *RestoreLVRequest, opts ...grpc.CallOption) (*RestoreLVResponse, error) {
+	out := new(RestoreLVResponse)
+	err := c.cc.Invoke(ctx, "/proto.LVService/RestoreLV", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lVServiceClient) ImportLV(ctx context.Context, in *ImportLVRequest, opts ...grpc.CallOption) (*ImportLVResponse, error) {
+	out := new(ImportLVResponse)
+	err := c.cc.Invoke(ctx, "/proto.LVService/ImportLV", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lVServiceClient) WipeLV(ctx context.Context, in *WipeLVRequest, opts ...grpc.CallOption) (LVService_WipeLVClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LVService_ServiceDesc.Streams[0], "/proto.LVService/WipeLV", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lVServiceWipeLVClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LVService_WipeLVClient interface {
+	Recv() (*WipeLVResponse, error)
+	grpc.ClientStream
+}
+
+type lVServiceWipeLVClient struct {
+	grpc.ClientStream
+}
+
+func (x *lVServiceWipeLVClient) Recv() (*WipeLVResponse, error) {
+	m := new(WipeLVResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // LVServiceServer is the server API for LVService service.
 // All implementations must embed UnimplementedLVServiceServer
 // for forward compatibility
 type LVServiceServer interface {
 	// Create a logical volume.
+	// If the volume already exists with the same size in the device-class, it is returned as is.
+	// Otherwise, ALREADY_EXISTS is returned.
 	CreateLV(context.Context, *CreateLVRequest) (*CreateLVResponse, error)
-	// Remove a logical volume.
+	// Remove a logical volume.  Removing a missing volume succeeds.
 	RemoveLV(context.Context, *RemoveLVRequest) (*Empty, error)
-	// Resize a logical volume.
+	// Resize a logical volume.  Resizing to the current size succeeds without changes.
 	ResizeLV(context.Context, *ResizeLVRequest) (*Empty, error)
+	// Restore a logical volume from the trash.
+	RestoreLV(context.Context, *RestoreLVRequest) (*RestoreLVResponse, error)
+	// Import an existing logical volume by renaming it.
+	ImportLV(context.Context, *ImportLVRequest) (*ImportLVResponse, error)
+	// Wipe a logical volume according to the wipe policy of the device-class.
+	// The progress is streamed while wiping.
+	WipeLV(*WipeLVRequest, LVService_WipeLVServer) error
 	mustEmbedUnimplementedLVServiceServer()
 }
 
@@ -91,6 +159,15 @@ func (UnimplementedLVServiceServer) RemoveLV(context.Context, *RemoveLVRequest)
 func (UnimplementedLVServiceServer) ResizeLV(context.Context, *ResizeLVRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResizeLV not implemented")
 }
+func (UnimplementedLVServiceServer) RestoreLV(context.Context, *RestoreLVRequest) (*RestoreLVResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreLV not implemented")
+}
+func (UnimplementedLVServiceServer) ImportLV(context.Context, *ImportLVRequest) (*ImportLVResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportLV not implemented")
+}
+func (UnimplementedLVServiceServer) WipeLV(*WipeLVRequest, LVService_WipeLVServer) error {
+	return status.Errorf(codes.Unimplemented, "method WipeLV not implemented")
+}
 func (UnimplementedLVServiceServer) mustEmbedUnimplementedLVServiceServer() {}
 
 // UnsafeLVServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +235,63 @@ func _LVService_ResizeLV_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LVService_RestoreLV_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreLVRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVServiceServer).RestoreLV(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVService/RestoreLV",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVServiceServer).RestoreLV(ctx, req.(*RestoreLVRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LVService_ImportLV_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportLVRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVServiceServer).ImportLV(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVService/ImportLV",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVServiceServer).ImportLV(ctx, req.(*ImportLVRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LVService_WipeLV_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WipeLVRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LVServiceServer).WipeLV(m, &lVServiceWipeLVServer{stream})
+}
+
+type LVService_WipeLVServer interface {
+	Send(*WipeLVResponse) error
+	grpc.ServerStream
+}
+
+type lVServiceWipeLVServer struct {
+	grpc.ServerStream
+}
+
+func (x *lVServiceWipeLVServer) Send(m *WipeLVResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // LVService_ServiceDesc is the grpc.ServiceDesc for LVService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,8 +311,22 @@ var LVService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ResizeLV",
 			Handler:    _LVService_ResizeLV_Handler,
 		},
+		{
+			MethodName: "RestoreLV",
+			Handler:    _LVService_RestoreLV_Handler,
+		},
+		{
+			MethodName: "ImportLV",
+			Handler:    _LVService_ImportLV_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WipeLV",
+			Handler:       _LVService_WipeLV_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "lvmd/proto/lvmd.proto",
 }
 
@@ -190,8 +338,10 @@ type VGServiceClient interface {
 	GetLVList(ctx context.Context, in *GetLVListRequest, opts ...grpc.CallOption) (*GetLVListResponse, error)
 	// Get the free space of the volume group in bytes.
 	GetFreeBytes(ctx context.Context, in *GetFreeBytesRequest, opts ...grpc.CallOption) (*GetFreeBytesResponse, error)
-	// Stream the volume group metrics.
-	Watch(ctx context.Context, in *Empty, opts ...grpc.CallOption) (VGService_WatchClient, error)
+	// Stream the volume group metrics, and optionally the changes of logical volumes.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (VGService_WatchClient, error)
+	// Override the spare capacity of a device-class until lvmd restarts.
+	SetSpare(ctx context.Context, in *SetSpareRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type vGServiceClient struct {
@@ -220,7 +370,7 @@ func (c *vGServiceClient) GetFreeBytes(ctx context.Context, in *GetFreeBytesRequ
 	return out, nil
 }
 
-func (c *vGServiceClient) Watch(ctx context.Context, in *Empty, opts ...grpc.CallOption) (VGService_WatchClient, error) {
+func (c *vGServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (VGService_WatchClient, error) {
 	stream, err := c.cc.NewStream(ctx, &VGService_ServiceDesc.Streams[0], "/proto.VGService/Watch", opts...)
 	if err != nil {
 		return nil, err
@@ -252,6 +402,15 @@ func (x *vGServiceWatchClient) Recv() (*WatchResponse, error) {
 	return m, nil
 }
 
+func (c *vGServiceClient) SetSpare(ctx context.Context, in *SetSpareRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.VGService/SetSpare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // VGServiceServer is the server API for VGService service.
 // All implementations must embed UnimplementedVGServiceServer
 // for forward compatibility
@@ -260,8 +419,10 @@ type VGServiceServer interface {
 	GetLVList(context.Context, *GetLVListRequest) (*GetLVListResponse, error)
 	// Get the free space of the volume group in bytes.
 	GetFreeBytes(context.Context, *GetFreeBytesRequest) (*GetFreeBytesResponse, error)
-	// Stream the volume group metrics.
-	Watch(*Empty, VGService_WatchServer) error
+	// Stream the volume group metrics, and optionally the changes of logical volumes.
+	Watch(*WatchRequest, VGService_WatchServer) error
+	// Override the spare capacity of a device-class until lvmd restarts.
+	SetSpare(context.Context, *SetSpareRequest) (*Empty, error)
 	mustEmbedUnimplementedVGServiceServer()
 }
 
@@ -275,9 +436,12 @@ func (UnimplementedVGServiceServer) GetLVList(context.Context, *GetLVListRequest
 func (UnimplementedVGServiceServer) GetFreeBytes(context.Context, *GetFreeBytesRequest) (*GetFreeBytesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFreeBytes not implemented")
 }
-func (UnimplementedVGServiceServer) Watch(*Empty, VGService_WatchServer) error {
+func (UnimplementedVGServiceServer) Watch(*WatchRequest, VGService_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
+func (UnimplementedVGServiceServer) SetSpare(context.Context, *SetSpareRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSpare not implemented")
+}
 func (UnimplementedVGServiceServer) mustEmbedUnimplementedVGServiceServer() {}
 
 // UnsafeVGServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -328,7 +492,7 @@ func _VGService_GetFreeBytes_Handler(srv interface{}, ctx context.Context, dec f
 }
 
 func _VGService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
+	m := new(WatchRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -348,6 +512,24 @@ func (x *vGServiceWatchServer) Send(m *WatchResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _VGService_SetSpare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSpareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VGServiceServer).SetSpare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.VGService/SetSpare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VGServiceServer).SetSpare(ctx, req.(*SetSpareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // VGService_ServiceDesc is the grpc.ServiceDesc for VGService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -363,6 +545,10 @@ var VGService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetFreeBytes",
 			Handler:    _VGService_GetFreeBytes_Handler,
 		},
+		{
+			MethodName: "SetSpare",
+			Handler:    _VGService_SetSpare_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -373,3 +559,159 @@ var VGService_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "lvmd/proto/lvmd.proto",
 }
+
+// PVServiceClient is the client API for PVService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PVServiceClient interface {
+	// Get the list of physical volumes in the volume group.
+	GetPVList(ctx context.Context, in *GetPVListRequest, opts ...grpc.CallOption) (*GetPVListResponse, error)
+	// Move all extents of a physical volume to the other physical volumes.
+	// The progress is streamed while moving.
+	EvictPV(ctx context.Context, in *EvictPVRequest, opts ...grpc.CallOption) (PVService_EvictPVClient, error)
+}
+
+type pVServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPVServiceClient(cc grpc.ClientConnInterface) PVServiceClient {
+	return &pVServiceClient{cc}
+}
+
+func (c *pVServiceClient) GetPVList(ctx context.Context, in *GetPVListRequest, opts ...grpc.CallOption) (*GetPVListResponse, error) {
+	out := new(GetPVListResponse)
+	err := c.cc.Invoke(ctx, "/proto.PVService/GetPVList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVServiceClient) EvictPV(ctx context.Context, in *EvictPVRequest, opts ...grpc.CallOption) (PVService_EvictPVClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PVService_ServiceDesc.Streams[0], "/proto.PVService/EvictPV", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pVServiceEvictPVClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PVService_EvictPVClient interface {
+	Recv() (*EvictPVResponse, error)
+	grpc.ClientStream
+}
+
+type pVServiceEvictPVClient struct {
+	grpc.ClientStream
+}
+
+func (x *pVServiceEvictPVClient) Recv() (*EvictPVResponse, error) {
+	m := new(EvictPVResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PVServiceServer is the server API for PVService service.
+// All implementations must embed UnimplementedPVServiceServer
+// for forward compatibility
+type PVServiceServer interface {
+	// Get the list of physical volumes in the volume group.
+	GetPVList(context.Context, *GetPVListRequest) (*GetPVListResponse, error)
+	// Move all extents of a physical volume to the other physical volumes.
+	// The progress is streamed while moving.
+	EvictPV(*EvictPVRequest, PVService_EvictPVServer) error
+	mustEmbedUnimplementedPVServiceServer()
+}
+
+// UnimplementedPVServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPVServiceServer struct {
+}
+
+func (UnimplementedPVServiceServer) GetPVList(context.Context, *GetPVListRequest) (*GetPVListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPVList not implemented")
+}
+func (UnimplementedPVServiceServer) EvictPV(*EvictPVRequest, PVService_EvictPVServer) error {
+	return status.Errorf(codes.Unimplemented, "method EvictPV not implemented")
+}
+func (UnimplementedPVServiceServer) mustEmbedUnimplementedPVServiceServer() {}
+
+// UnsafePVServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PVServiceServer will
+// result in compilation errors.
+type UnsafePVServiceServer interface {
+	mustEmbedUnimplementedPVServiceServer()
+}
+
+func RegisterPVServiceServer(s grpc.ServiceRegistrar, srv PVServiceServer) {
+	s.RegisterService(&PVService_ServiceDesc, srv)
+}
+
+func _PVService_GetPVList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPVListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVServiceServer).GetPVList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.PVService/GetPVList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVServiceServer).GetPVList(ctx, req.(*GetPVListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVService_EvictPV_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EvictPVRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PVServiceServer).EvictPV(m, &pVServiceEvictPVServer{stream})
+}
+
+type PVService_EvictPVServer interface {
+	Send(*EvictPVResponse) error
+	grpc.ServerStream
+}
+
+type pVServiceEvictPVServer struct {
+	grpc.ServerStream
+}
+
+func (x *pVServiceEvictPVServer) Send(m *EvictPVResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PVService_ServiceDesc is the grpc.ServiceDesc for PVService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PVService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.PVService",
+	HandlerType: (*PVServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPVList",
+			Handler:    _PVService_GetPVList_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EvictPV",
+			Handler:       _PVService_EvictPV_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "lvmd/proto/lvmd.proto",
+}
@@ -0,0 +1,183 @@
+package lvmd
+
+import (
+	"context"
+
+	"github.com/cybozu-go/log"
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewPVService creates a new PVServiceServer
+func NewPVService(mapper *DeviceClassManager, b backend.Backend, notifyFunc func()) proto.PVServiceServer {
+	return &pvService{
+		mapper:     mapper,
+		backend:    b,
+		notifyFunc: notifyFunc,
+	}
+}
+
+type pvService struct {
+	proto.UnimplementedPVServiceServer
+	mapper     *DeviceClassManager
+	backend    backend.Backend
+	notifyFunc func()
+}
+
+func (s *pvService) notify() {
+	if s.notifyFunc == nil {
+		return
+	}
+	s.notifyFunc()
+}
+
+func (s *pvService) GetPVList(ctx context.Context, req *proto.GetPVListRequest) (*proto.GetPVListResponse, error) {
+	dc, err := s.mapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	pvs, err := vg.ListPhysicalVolumes(ctx)
+	if err != nil {
+		log.Error("failed to list physical volumes", map[string]interface{}{
+			log.FnError: err,
+		})
+		return nil, statusError(err)
+	}
+
+	vols := make([]*proto.PhysicalVolume, len(pvs))
+	for i, pv := range pvs {
+		vols[i] = &proto.PhysicalVolume{
+			Name:      pv.Name(),
+			SizeBytes: pv.Size(),
+			FreeBytes: pv.Free(),
+		}
+	}
+	return &proto.GetPVListResponse{Volumes: vols}, nil
+}
+
+// checkEvictable returns an error if the allocated extents of target cannot be moved to the other physical volumes.
+func checkEvictable(target backend.PhysicalVolume, pvs []backend.PhysicalVolume) error {
+	if len(pvs) < 2 {
+		return status.Errorf(codes.FailedPrecondition, "physical volume %s is the only one in the volume group", target.Name())
+	}
+	var free uint64
+	for _, pv := range pvs {
+		if pv.Name() == target.Name() {
+			continue
+		}
+		free += pv.Free()
+	}
+	if free < target.Used() {
+		return status.Errorf(codes.FailedPrecondition,
+			"not enough free space on the other physical volumes: used=%d, free=%d", target.Used(), free)
+	}
+	return nil
+}
+
+func (s *pvService) EvictPV(req *proto.EvictPVRequest, server proto.PVService_EvictPVServer) error {
+	ctx := server.Context()
+	dc, err := s.mapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
+	if err != nil {
+		return statusError(err)
+	}
+	pvs, err := vg.ListPhysicalVolumes(ctx)
+	if err != nil {
+		log.Error("failed to list physical volumes", map[string]interface{}{
+			log.FnError: err,
+		})
+		return statusError(err)
+	}
+	var target backend.PhysicalVolume
+	for _, pv := range pvs {
+		if pv.Name() == req.GetName() {
+			target = pv
+			break
+		}
+	}
+	if target == nil {
+		return status.Errorf(codes.NotFound, "physical volume %s is not found in volume group %s", req.GetName(), vg.Name())
+	}
+	if err := checkEvictable(target, pvs); err != nil {
+		log.Error("physical volume cannot be evicted", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return err
+	}
+
+	// pvmove may allocate extents of the other volumes on the target while moving,
+	// so the target is made unallocatable first.  It stays so after the move to keep it empty.
+	if err := target.SetAllocatable(ctx, false); err != nil {
+		log.Error("failed to disallow allocation on physical volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		return statusError(err)
+	}
+	// restoreAllocatable is called when the eviction fails before the target leaves the volume group.
+	// ctx is not used because the failure may be caused by its cancellation.
+	restoreAllocatable := func() {
+		if err := target.SetAllocatable(context.Background(), true); err != nil {
+			log.Error("failed to allow allocation on physical volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      req.GetName(),
+			})
+		}
+	}
+
+	total := target.Used()
+	err = target.Move(ctx, func(moved, total uint64) error {
+		return server.Send(&proto.EvictPVResponse{
+			MovedBytes: moved,
+			TotalBytes: total,
+		})
+	})
+	if err != nil {
+		log.Error("failed to move physical volume", map[string]interface{}{
+			log.FnError: err,
+			"name":      req.GetName(),
+		})
+		restoreAllocatable()
+		return statusError(err)
+	}
+	s.notify()
+
+	if req.GetRemove() {
+		if err := target.Reduce(ctx); err != nil {
+			log.Error("failed to remove physical volume from volume group", map[string]interface{}{
+				log.FnError: err,
+				"name":      req.GetName(),
+			})
+			restoreAllocatable()
+			return statusError(err)
+		}
+		s.notify()
+		if err := target.Remove(ctx); err != nil {
+			log.Error("failed to remove physical volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      req.GetName(),
+			})
+			return statusError(err)
+		}
+	}
+
+	log.Info("evicted a PV", map[string]interface{}{
+		"name":   req.GetName(),
+		"moved":  total,
+		"remove": req.GetRemove(),
+	})
+	return server.Send(&proto.EvictPVResponse{
+		MovedBytes: total,
+		TotalBytes: total,
+	})
+}
@@ -0,0 +1,267 @@
+package lvmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type evictPVServer struct {
+	proto.PVService_EvictPVServer
+	ctx       context.Context
+	responses []*proto.EvictPVResponse
+}
+
+func (s *evictPVServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *evictPVServer) Send(res *proto.EvictPVResponse) error {
+	s.responses = append(s.responses, res)
+	return nil
+}
+
+func TestPVServiceWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 10<<30)
+	b.AddPhysicalVolume("vg", "/dev/fake2", 5<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "vg", Default: true}})
+	pvService := NewPVService(manager, b, nil)
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.CreateVolume(ctx, "lv1", 12<<30, nil, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = pvService.GetPVList(ctx, &proto.GetPVListRequest{DeviceClass: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected %s for an unknown device-class, but actual %v", codes.NotFound, err)
+	}
+
+	res, err := pvService.GetPVList(ctx, &proto.GetPVListRequest{DeviceClass: "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*proto.PhysicalVolume{
+		{Name: "/dev/fake1", SizeBytes: 10 << 30, FreeBytes: 0},
+		{Name: "/dev/fake2", SizeBytes: 5 << 30, FreeBytes: 3 << 30},
+	}
+	if len(res.GetVolumes()) != len(expected) {
+		t.Fatalf("expected %v, but actual %v", expected, res.GetVolumes())
+	}
+	for i, pv := range res.GetVolumes() {
+		if pv.GetName() != expected[i].Name || pv.GetSizeBytes() != expected[i].SizeBytes || pv.GetFreeBytes() != expected[i].FreeBytes {
+			t.Errorf("expected %v, but actual %v", expected[i], pv)
+		}
+	}
+
+	b.InjectFailure(fake.OpListPhysicalVolumes, errors.New("injected"), 1)
+	_, err = pvService.GetPVList(ctx, &proto.GetPVListRequest{DeviceClass: "ssd"})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected %s, but actual %v", codes.Internal, err)
+	}
+}
+
+func TestEvictPVWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	manager := NewDeviceClassManager([]*DeviceClass{
+		{Name: "ssd", VolumeGroup: "vg", Default: true},
+		{Name: "single", VolumeGroup: "vg-single"},
+	})
+
+	// setup creates "vg" with three PVs of 10 GiB.
+	// "lv1" of 8 GiB and "lv2" of 4 GiB are allocated on /dev/fake1 and /dev/fake2.
+	setup := func(t *testing.T) *fake.Backend {
+		b := fake.New()
+		b.AddPhysicalVolume("vg", "/dev/fake1", 10<<30)
+		b.AddPhysicalVolume("vg", "/dev/fake2", 10<<30)
+		b.AddPhysicalVolume("vg", "/dev/fake3", 10<<30)
+		b.AddPhysicalVolume("vg-single", "/dev/fake4", 10<<30)
+		vg, err := b.FindVolumeGroup(ctx, "vg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := vg.CreateVolume(ctx, "lv1", 8<<30, nil, 0, ""); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := vg.CreateVolume(ctx, "lv2", 4<<30, nil, 0, ""); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	pvFree := func(t *testing.T, b *fake.Backend) map[string]uint64 {
+		t.Helper()
+		vg, err := b.FindVolumeGroup(ctx, "vg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pvs, err := vg.ListPhysicalVolumes(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make(map[string]uint64)
+		for _, pv := range pvs {
+			result[pv.Name()] = pv.Free()
+		}
+		return result
+	}
+
+	t.Run("refused", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			req      *proto.EvictPVRequest
+			prepare  func(t *testing.T, b *fake.Backend)
+			expected codes.Code
+		}{
+			{
+				name:     "unknown device-class",
+				req:      &proto.EvictPVRequest{Name: "/dev/fake1", DeviceClass: "unknown"},
+				expected: codes.NotFound,
+			},
+			{
+				name:     "unknown physical volume",
+				req:      &proto.EvictPVRequest{Name: "/dev/unknown", DeviceClass: "ssd"},
+				expected: codes.NotFound,
+			},
+			{
+				name:     "only one physical volume",
+				req:      &proto.EvictPVRequest{Name: "/dev/fake4", DeviceClass: "single"},
+				expected: codes.FailedPrecondition,
+			},
+			{
+				name: "not enough free space",
+				req:  &proto.EvictPVRequest{Name: "/dev/fake1", DeviceClass: "ssd"},
+				prepare: func(t *testing.T, b *fake.Backend) {
+					vg, err := b.FindVolumeGroup(ctx, "vg")
+					if err != nil {
+						t.Fatal(err)
+					}
+					// only 1 GiB is left on /dev/fake2 and /dev/fake3.
+					if _, err := vg.CreateVolume(ctx, "lv3", 15<<30, nil, 0, ""); err != nil {
+						t.Fatal(err)
+					}
+				},
+				expected: codes.FailedPrecondition,
+			},
+		}
+
+		for _, tc := range testCases {
+			b := setup(t)
+			if tc.prepare != nil {
+				tc.prepare(t, b)
+			}
+			before := pvFree(t, b)
+			server := &evictPVServer{ctx: ctx}
+			err := NewPVService(manager, b, nil).EvictPV(tc.req, server)
+			if status.Code(err) != tc.expected {
+				t.Errorf("%s: expected %s, but actual %v", tc.name, tc.expected, err)
+			}
+			if len(server.responses) != 0 {
+				t.Errorf("%s: expected no progress, but actual %v", tc.name, server.responses)
+			}
+			after := pvFree(t, b)
+			for name, free := range before {
+				if after[name] != free {
+					t.Errorf("%s: expected free bytes of %s to be %d, but actual %d", tc.name, name, free, after[name])
+				}
+			}
+		}
+	})
+
+	t.Run("move", func(t *testing.T) {
+		b := setup(t)
+		var notified int
+		server := &evictPVServer{ctx: ctx}
+		err := NewPVService(manager, b, func() { notified++ }).EvictPV(&proto.EvictPVRequest{Name: "/dev/fake2", DeviceClass: "ssd"}, server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(server.responses) == 0 {
+			t.Fatal("no progress is sent")
+		}
+		last := server.responses[len(server.responses)-1]
+		if last.MovedBytes != 2<<30 || last.TotalBytes != 2<<30 {
+			t.Errorf("expected 2 GiB moved, but actual %v", last)
+		}
+		free := pvFree(t, b)
+		if free["/dev/fake2"] != 10<<30 {
+			t.Errorf("expected /dev/fake2 to be empty, but actual free bytes %d", free["/dev/fake2"])
+		}
+		if free["/dev/fake1"]+free["/dev/fake3"] != 8<<30 {
+			t.Errorf("expected extents to move to the others, but actual %v", free)
+		}
+		if notified != 1 {
+			t.Errorf("expected 1 notification, but actual %d", notified)
+		}
+		allocatable, err := b.Allocatable("vg", "/dev/fake2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allocatable {
+			t.Error("expected /dev/fake2 to be kept unallocatable after the move")
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		b := setup(t)
+		server := &evictPVServer{ctx: ctx}
+		err := NewPVService(manager, b, nil).EvictPV(&proto.EvictPVRequest{Name: "/dev/fake1", DeviceClass: "ssd", Remove: true}, server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		free := pvFree(t, b)
+		if _, ok := free["/dev/fake1"]; ok {
+			t.Errorf("expected /dev/fake1 to be removed, but actual %v", free)
+		}
+		if free["/dev/fake2"]+free["/dev/fake3"] != 8<<30 {
+			t.Errorf("expected extents to move to the others, but actual %v", free)
+		}
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			op      fake.Op
+			remove  bool
+			removed bool
+		}{
+			{name: "set allocatable", op: fake.OpSetAllocatable},
+			{name: "move", op: fake.OpMove},
+			{name: "reduce", op: fake.OpReduce, remove: true},
+			{name: "remove", op: fake.OpRemovePV, remove: true, removed: true},
+		}
+
+		for _, tc := range testCases {
+			b := setup(t)
+			b.InjectFailure(tc.op, errors.New("injected"), 0)
+			server := &evictPVServer{ctx: ctx}
+			err := NewPVService(manager, b, nil).EvictPV(&proto.EvictPVRequest{Name: "/dev/fake1", DeviceClass: "ssd", Remove: tc.remove}, server)
+			if status.Code(err) != codes.Internal {
+				t.Errorf("%s: expected %s, but actual %v", tc.name, codes.Internal, err)
+			}
+			_, ok := pvFree(t, b)["/dev/fake1"]
+			if ok == tc.removed {
+				t.Errorf("%s: expected removed=%v, but actual %v", tc.name, tc.removed, !ok)
+			}
+			if tc.removed {
+				continue
+			}
+			allocatable, err := b.Allocatable("vg", "/dev/fake1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !allocatable {
+				t.Errorf("%s: expected /dev/fake1 to be allocatable again", tc.name)
+			}
+		}
+	})
+}
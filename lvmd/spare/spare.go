@@ -0,0 +1,69 @@
+// Package spare parses spare capacity of volume groups.
+//
+// Spare capacity is either a percentage of the volume group size like "5%",
+// or an absolute quantity like "100Gi".
+package spare
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Spare is spare capacity of a volume group.
+type Spare struct {
+	// percent is the percentage of the volume group size in 1/10000 units, or -1 if bytes is used.
+	percent int64
+	bytes   uint64
+}
+
+// FromBytes returns Spare of an absolute size in bytes.
+func FromBytes(bytes uint64) Spare {
+	return Spare{percent: -1, bytes: bytes}
+}
+
+// Parse parses a percentage like "5%" or "0.5%", or a quantity like "100Gi".
+func Parse(s string) (Spare, error) {
+	if p := strings.TrimSuffix(s, "%"); p != s {
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(p))
+		if !ok || r.Sign() < 0 || r.Cmp(big.NewRat(100, 1)) > 0 {
+			return Spare{}, fmt.Errorf("spare percentage should be between 0%% and 100%%: %s", s)
+		}
+		r.Mul(r, big.NewRat(100, 1))
+		if !r.IsInt() {
+			return Spare{}, fmt.Errorf("spare percentage should have at most two decimal places: %s", s)
+		}
+		return Spare{percent: r.Num().Int64()}, nil
+	}
+
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return Spare{}, fmt.Errorf("spare should be a percentage like \"5%%\" or a quantity like \"100Gi\": %s", s)
+	}
+	if q.Sign() < 0 {
+		return Spare{}, fmt.Errorf("spare should not be negative: %s", s)
+	}
+	return FromBytes(uint64(q.Value())), nil
+}
+
+// Bytes returns the spare capacity in bytes for a volume group of size bytes.
+func (s Spare) Bytes(size uint64) uint64 {
+	if s.percent < 0 {
+		return s.bytes
+	}
+	v := new(big.Int).SetUint64(size)
+	v.Mul(v, big.NewInt(s.percent))
+	v.Quo(v, big.NewInt(10000))
+	return v.Uint64()
+}
+
+// Subtract returns free minus the spare capacity for a volume group of size bytes, or zero if free is smaller.
+func (s Spare) Subtract(free, size uint64) uint64 {
+	spare := s.Bytes(size)
+	if free < spare {
+		return 0
+	}
+	return free - spare
+}
@@ -0,0 +1,53 @@
+package spare
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		size     uint64
+		expected uint64
+		valid    bool
+	}{
+		{"10%", 200 << 30, 20 << 30, true},
+		{"0.5%", 200 << 30, 1 << 30, true},
+		{"100%", 200 << 30, 200 << 30, true},
+		{"0%", 200 << 30, 0, true},
+		{"100Gi", 30 << 40, 100 << 30, true},
+		{"1G", 0, 1000000000, true},
+		{"0", 200 << 30, 0, true},
+		{"101%", 0, 0, false},
+		{"-1%", 0, 0, false},
+		{"0.125%", 0, 0, false},
+		{"abc%", 0, 0, false},
+		{"-1Gi", 0, 0, false},
+		{"10GB", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tc := range testCases {
+		s, err := Parse(tc.input)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("%q: expected an error", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if actual := s.Bytes(tc.size); actual != tc.expected {
+			t.Errorf("%q: expected %d, but actual %d", tc.input, tc.expected, actual)
+		}
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	s := FromBytes(10 << 30)
+	if actual := s.Subtract(15<<30, 100<<30); actual != 5<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(5<<30), actual)
+	}
+	if actual := s.Subtract(5<<30, 100<<30); actual != 0 {
+		t.Errorf("expected 0, but actual %d", actual)
+	}
+}
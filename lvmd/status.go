@@ -0,0 +1,22 @@
+package lvmd
+
+import (
+	"errors"
+
+	"github.com/topolvm/topolvm/lvmd/command"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError returns err as a gRPC status error.
+// Errors of LVM commands are mapped to codes by command.LVMError, and other errors are reported as codes.Internal.
+func statusError(err error) error {
+	var lvmErr *command.LVMError
+	if errors.As(err, &lvmErr) {
+		return lvmErr.GRPCStatus().Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,108 @@
+package lvmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cybozu-go/log"
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/backend"
+)
+
+// trashPrefix is the name prefix of logical volumes moved into the trash.
+const trashPrefix = "trash-"
+
+// trashName returns the name of the logical volume in the trash.
+func trashName(name string) string {
+	return trashPrefix + name
+}
+
+// trashedAt returns the time when lv was moved into the trash.
+// The second return value is false if lv is not in the trash.
+func trashedAt(lv backend.LogicalVolume) (time.Time, bool) {
+	if !strings.HasPrefix(lv.Name(), trashPrefix) {
+		return time.Time{}, false
+	}
+	for _, tag := range lv.Tags() {
+		if !strings.HasPrefix(tag, topolvm.TrashedAtTagPrefix) {
+			continue
+		}
+		sec, err := strconv.ParseInt(tag[len(topolvm.TrashedAtTagPrefix):], 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(sec, 0), true
+	}
+	return time.Time{}, false
+}
+
+// moveToTrash renames lv into the trash and records the current time on it.
+// The logical volume keeps occupying the volume group until it is purged.
+func moveToTrash(ctx context.Context, lv backend.LogicalVolume, now time.Time) error {
+	if err := lv.AddTags(ctx, topolvm.TrashedAtTagPrefix+strconv.FormatInt(now.Unix(), 10)); err != nil {
+		return err
+	}
+	return lv.Rename(ctx, trashName(lv.Name()))
+}
+
+// restoreFromTrash takes lv out of the trash and renames it to name.
+func restoreFromTrash(ctx context.Context, lv backend.LogicalVolume, name string) error {
+	var tags []string
+	for _, tag := range lv.Tags() {
+		if strings.HasPrefix(tag, topolvm.TrashedAtTagPrefix) {
+			tags = append(tags, tag)
+		}
+	}
+	if err := lv.DelTags(ctx, tags...); err != nil {
+		return err
+	}
+	return lv.Rename(ctx, name)
+}
+
+// PurgeTrash removes logical volumes in the trash whose retention period has expired.
+// It returns the number of removed logical volumes.
+func PurgeTrash(ctx context.Context, b backend.Backend, manager *DeviceClassManager, now time.Time) (int, error) {
+	var purged int
+	for _, dc := range manager.DeviceClasses() {
+		retention := dc.GetTrashRetention()
+		if retention == 0 {
+			continue
+		}
+		vg, err := b.FindVolumeGroup(ctx, dc.VolumeGroup)
+		if err != nil {
+			return purged, err
+		}
+		lvs, err := vg.ListVolumes(ctx)
+		if err != nil {
+			return purged, err
+		}
+		for _, lv := range lvs {
+			at, ok := trashedAt(lv)
+			if !ok || now.Sub(at) < retention {
+				continue
+			}
+			if err := wipe(ctx, lv, dc.WipePolicy, nil); err != nil {
+				log.Error("failed to wipe volume in the trash", map[string]interface{}{
+					log.FnError: err,
+					"name":      lv.Name(),
+				})
+				return purged, err
+			}
+			if err := lv.Remove(ctx); err != nil {
+				log.Error("failed to purge volume from the trash", map[string]interface{}{
+					log.FnError: err,
+					"name":      lv.Name(),
+				})
+				return purged, err
+			}
+			purged++
+			log.Info("purged a LV from the trash", map[string]interface{}{
+				"name":       lv.Name(),
+				"trashed_at": at,
+			})
+		}
+	}
+	return purged, nil
+}
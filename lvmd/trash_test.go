@@ -0,0 +1,169 @@
+package lvmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTrashWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 10<<30)
+	b.AddPhysicalVolume("plain", "/dev/fake2", 10<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{
+		{Name: "trash", VolumeGroup: "vg", TrashRetention: "1h"},
+		{Name: "plain", VolumeGroup: "plain"},
+	})
+	lvService := NewLVService(manager, b, func() {})
+
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"lv1", "lv2"} {
+		_, err := lvService.CreateLV(ctx, &proto.CreateLVRequest{Name: name, DeviceClass: "trash", SizeGb: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := time.Now()
+	_, err = lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: "lv1", DeviceClass: "trash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.FindVolume(ctx, "lv1")
+	if err != backend.ErrNotFound {
+		t.Errorf("lv1 should be renamed: %v", err)
+	}
+	trashed, err := vg.FindVolume(ctx, "trash-lv1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at, ok := trashedAt(trashed)
+	if !ok {
+		t.Fatalf("trashed-at tag is not found: %v", trashed.Tags())
+	}
+	if at.Before(before.Truncate(time.Second)) || at.After(time.Now()) {
+		t.Errorf("unexpected trashed-at: %v", at)
+	}
+	free, err := vg.Free(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if free != 6<<30 {
+		t.Errorf("volumes in the trash should occupy the volume group: %d", free)
+	}
+
+	// volumes in the trash are restored only with the same size.
+	_, err = lvService.RestoreLV(ctx, &proto.RestoreLVRequest{Name: "lv3", OriginalName: "lv1", DeviceClass: "trash", SizeGb: 3})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("expected %s, but actual %v", codes.InvalidArgument, err)
+	}
+	_, err = lvService.RestoreLV(ctx, &proto.RestoreLVRequest{Name: "lv2", OriginalName: "lv1", DeviceClass: "trash", SizeGb: 2})
+	if code := status.Code(err); code != codes.AlreadyExists {
+		t.Errorf("expected %s, but actual %v", codes.AlreadyExists, err)
+	}
+	_, err = lvService.RestoreLV(ctx, &proto.RestoreLVRequest{Name: "lv3", OriginalName: "lv2", DeviceClass: "trash", SizeGb: 2})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("expected %s, but actual %v", codes.NotFound, err)
+	}
+	res, err := lvService.RestoreLV(ctx, &proto.RestoreLVRequest{Name: "lv3", OriginalName: "lv1", DeviceClass: "trash", SizeGb: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Volume.Name != "lv3" || res.Volume.SizeGb != 2 {
+		t.Errorf("unexpected volume: %v", res.Volume)
+	}
+	restored, err := vg.FindVolume(ctx, "lv3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := trashedAt(restored); ok {
+		t.Error("restored volume should not be in the trash")
+	}
+	for _, tag := range restored.Tags() {
+		if strings.HasPrefix(tag, topolvm.TrashedAtTagPrefix) {
+			t.Errorf("trashed-at tag remains: %s", tag)
+		}
+	}
+	_, err = lvService.RestoreLV(ctx, &proto.RestoreLVRequest{Name: "lv4", OriginalName: "lv1", DeviceClass: "trash"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("expected %s, but actual %v", codes.NotFound, err)
+	}
+
+	// only expired volumes are purged.
+	now := time.Now()
+	_, err = vg.CreateVolume(ctx, "trash-old", 1<<30, []string{topolvm.TrashedAtTagPrefix + strconv.FormatInt(now.Add(-2*time.Hour).Unix(), 10)}, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.CreateVolume(ctx, "trash-untagged", 1<<30, nil, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: "lv2", DeviceClass: "trash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := PurgeTrash(ctx, b, manager, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1, but actual %d", purged)
+	}
+	for name, exists := range map[string]bool{"trash-old": false, "trash-lv2": true, "trash-untagged": true, "lv3": true} {
+		_, err := vg.FindVolume(ctx, name)
+		if exists && err != nil {
+			t.Errorf("%s should exist: %v", name, err)
+		}
+		if !exists && err != backend.ErrNotFound {
+			t.Errorf("%s should be purged: %v", name, err)
+		}
+	}
+
+	purged, err = PurgeTrash(ctx, b, manager, now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1, but actual %d", purged)
+	}
+	_, err = vg.FindVolume(ctx, "trash-lv2")
+	if err != backend.ErrNotFound {
+		t.Errorf("trash-lv2 should be purged: %v", err)
+	}
+
+	// device-classes without trash-retention remove volumes immediately.
+	_, err = lvService.CreateLV(ctx, &proto.CreateLVRequest{Name: "lv5", DeviceClass: "plain", SizeGb: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: "lv5", DeviceClass: "plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := b.FindVolumeGroup(ctx, "plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvs, err := plain.ListVolumes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != 0 {
+		t.Errorf("unexpected volumes: %v", lvs)
+	}
+}
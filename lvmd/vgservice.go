@@ -5,17 +5,21 @@ import (
 	"sync"
 
 	"github.com/cybozu-go/log"
-	"github.com/topolvm/topolvm/lvmd/command"
+	"github.com/topolvm/topolvm/lvmd/backend"
 	"github.com/topolvm/topolvm/lvmd/proto"
+	"github.com/topolvm/topolvm/lvmd/spare"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 // NewVGService creates a VGServiceServer
-func NewVGService(manager *DeviceClassManager) (proto.VGServiceServer, func()) {
+func NewVGService(manager *DeviceClassManager, b backend.Backend) (proto.VGServiceServer, func()) {
 	svc := &vgService{
 		dcManager: manager,
+		backend:   b,
 		watchers:  make(map[int]chan struct{}),
+		events:    newLVEventLog(),
+		spares:    make(map[string]spare.Spare),
 	}
 
 	return svc, svc.notifyWatchers
@@ -24,85 +28,157 @@ func NewVGService(manager *DeviceClassManager) (proto.VGServiceServer, func()) {
 type vgService struct {
 	proto.UnimplementedVGServiceServer
 	dcManager *DeviceClassManager
+	backend   backend.Backend
 
 	mu             sync.Mutex
 	watcherCounter int
 	watchers       map[int]chan struct{}
+
+	evMu   sync.Mutex
+	events *lvEventLog
+
+	// spares are the spare capacity overridden by SetSpare for each device-class.
+	spareMu sync.Mutex
+	spares  map[string]spare.Spare
+}
+
+// spare returns the spare capacity of dc, overridden by SetSpare if any.
+func (s *vgService) spare(dc *DeviceClass) spare.Spare {
+	s.spareMu.Lock()
+	defer s.spareMu.Unlock()
+	if sp, ok := s.spares[dc.Name]; ok {
+		return sp
+	}
+	return dc.GetSpare()
 }
 
-func (s *vgService) GetLVList(_ context.Context, req *proto.GetLVListRequest) (*proto.GetLVListResponse, error) {
+func toProtoLogicalVolume(lv backend.LogicalVolume) *proto.LogicalVolume {
+	return &proto.LogicalVolume{
+		Name:     lv.Name(),
+		SizeGb:   (lv.Size() + (1 << 30) - 1) >> 30,
+		DevMajor: lv.MajorNumber(),
+		DevMinor: lv.MinorNumber(),
+		Tags:     lv.Tags(),
+	}
+}
+
+func (s *vgService) GetLVList(ctx context.Context, req *proto.GetLVListRequest) (*proto.GetLVListResponse, error) {
 	dc, err := s.dcManager.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
 	}
-	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	lvs, err := vg.ListVolumes()
-	if err != nil {
-		log.Error("failed to list volumes", map[string]interface{}{
-			log.FnError: err,
-		})
-		return nil, status.Error(codes.Internal, err.Error())
+	var lvs []backend.LogicalVolume
+	if req.Name != "" {
+		lv, err := vg.FindVolume(ctx, req.Name)
+		switch {
+		case err == backend.ErrNotFound:
+		case err != nil:
+			log.Error("failed to find volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      req.Name,
+			})
+			return nil, statusError(err)
+		default:
+			lvs = append(lvs, lv)
+		}
+	} else {
+		lvs, err = vg.ListVolumes(ctx)
+		if err != nil {
+			log.Error("failed to list volumes", map[string]interface{}{
+				log.FnError: err,
+			})
+			return nil, statusError(err)
+		}
 	}
 
 	vols := make([]*proto.LogicalVolume, len(lvs))
 	for i, lv := range lvs {
-		vols[i] = &proto.LogicalVolume{
-			Name:     lv.Name(),
-			SizeGb:   (lv.Size() + (1 << 30) - 1) >> 30,
-			DevMajor: lv.MajorNumber(),
-			DevMinor: lv.MinorNumber(),
-			Tags:     lv.Tags(),
-		}
+		vols[i] = toProtoLogicalVolume(lv)
 	}
 	return &proto.GetLVListResponse{Volumes: vols}, nil
 }
 
-func (s *vgService) GetFreeBytes(_ context.Context, req *proto.GetFreeBytesRequest) (*proto.GetFreeBytesResponse, error) {
+func (s *vgService) GetFreeBytes(ctx context.Context, req *proto.GetFreeBytesRequest) (*proto.GetFreeBytesResponse, error) {
 	dc, err := s.dcManager.DeviceClass(req.DeviceClass)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
 	}
-	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	vg, err := s.backend.FindVolumeGroup(ctx, dc.VolumeGroup)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	vgFree, err := vg.Free()
+	vgFree, err := vg.Free(ctx)
 	if err != nil {
 		log.Error("failed to free VG", map[string]interface{}{
 			log.FnError: err,
 		})
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
-
-	spare := dc.GetSpare()
-	if vgFree < spare {
-		vgFree = 0
-	} else {
-		vgFree -= spare
+	vgSize, err := vg.Size(ctx)
+	if err != nil {
+		log.Error("failed to get VG size", map[string]interface{}{
+			log.FnError: err,
+		})
+		return nil, statusError(err)
 	}
 
 	return &proto.GetFreeBytesResponse{
-		FreeBytes: vgFree,
+		FreeBytes: s.spare(dc).Subtract(vgFree, vgSize),
 	}, nil
 }
 
-func (s *vgService) send(server proto.VGService_WatchServer) error {
-	vgs, err := command.ListVolumeGroups()
+func (s *vgService) SetSpare(_ context.Context, req *proto.SetSpareRequest) (*proto.Empty, error) {
+	dc, err := s.dcManager.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	prev := s.spare(dc)
+	next := dc.GetSpare()
+	if req.Spare != "" {
+		next, err = spare.Parse(req.Spare)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	s.spareMu.Lock()
+	if req.Spare == "" {
+		delete(s.spares, dc.Name)
+	} else {
+		s.spares[dc.Name] = next
+	}
+	s.spareMu.Unlock()
+	if next == prev {
+		return &proto.Empty{}, nil
+	}
+
+	log.Info("spare capacity is overridden", map[string]interface{}{
+		"device_class": dc.Name,
+		"spare":        req.Spare,
+	})
+	s.notifyWatchers()
+	return &proto.Empty{}, nil
+}
+
+func (s *vgService) send(server proto.VGService_WatchServer, w *lvWatcher) error {
+	ctx := server.Context()
+	vgs, err := s.backend.ListVolumeGroups(ctx)
 	if err != nil {
 		return err
 	}
 	res := &proto.WatchResponse{}
 	for _, vg := range vgs {
-		vgFree, err := vg.Free()
+		vgFree, err := vg.Free(ctx)
 		if err != nil {
-			return status.Error(codes.Internal, err.Error())
+			return statusError(err)
 		}
-		vgSize, err := vg.Size()
+		vgSize, err := vg.Size(ctx)
 		if err != nil {
-			return status.Error(codes.Internal, err.Error())
+			return statusError(err)
 		}
 		dc, err := s.dcManager.FindDeviceClassByVGName(vg.Name())
 		if err == ErrNotFound {
@@ -111,15 +187,23 @@ func (s *vgService) send(server proto.VGService_WatchServer) error {
 		if err != nil {
 			return err
 		}
+		free := s.spare(dc).Subtract(vgFree, vgSize)
 		if dc.Default {
-			res.FreeBytes = vgFree
+			res.FreeBytes = free
 		}
 		res.Items = append(res.Items, &proto.WatchItem{
 			DeviceClass: dc.Name,
-			FreeBytes:   vgFree,
+			FreeBytes:   free,
 			SizeBytes:   vgSize,
+			SpareBytes:  vgFree - free,
+			Default:     dc.Default,
 		})
 	}
+	if w != nil {
+		if err := s.lvEvents(ctx, w, res); err != nil {
+			return err
+		}
+	}
 	return server.Send(res)
 }
 
@@ -153,12 +237,16 @@ func (s *vgService) notifyWatchers() {
 	}
 }
 
-func (s *vgService) Watch(_ *proto.Empty, server proto.VGService_WatchServer) error {
+func (s *vgService) Watch(req *proto.WatchRequest, server proto.VGService_WatchServer) error {
 	ch := make(chan struct{}, 1)
 	num := s.addWatcher(ch)
 	defer s.removeWatcher(num)
 
-	if err := s.send(server); err != nil {
+	var w *lvWatcher
+	if req.LvEvents {
+		w = &lvWatcher{token: req.ResumeToken}
+	}
+	if err := s.send(server, w); err != nil {
 		return err
 	}
 
@@ -167,7 +255,7 @@ func (s *vgService) Watch(_ *proto.Empty, server proto.VGService_WatchServer) er
 		case <-server.Context().Done():
 			return server.Context().Err()
 		case <-ch:
-			if err := s.send(server); err != nil {
+			if err := s.send(server, w); err != nil {
 				return err
 			}
 		}
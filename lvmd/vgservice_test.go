@@ -2,14 +2,19 @@ package lvmd
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"testing"
 	"time"
 
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
 	"github.com/topolvm/topolvm/lvmd/command"
 	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type mockWatchServer struct {
@@ -46,9 +51,9 @@ func (s *mockWatchServer) RecvMsg(m interface{}) error {
 	panic("implement me")
 }
 
-func testWatch(t *testing.T) {
+func testWatch(t *testing.T, b backend.Backend, vgName string) {
 	ctx, cancel := context.WithCancel(context.Background())
-	vgService, notifier := NewVGService(NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "test_vgservice"}}))
+	vgService, notifier := NewVGService(NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: vgName}}), b)
 
 	ch1 := make(chan struct{})
 	server1 := &mockWatchServer{
@@ -57,7 +62,7 @@ func testWatch(t *testing.T) {
 	}
 	done := make(chan struct{})
 	go func() {
-		vgService.Watch(&proto.Empty{}, server1)
+		vgService.Watch(&proto.WatchRequest{}, server1)
 		done <- struct{}{}
 	}()
 
@@ -87,7 +92,7 @@ func testWatch(t *testing.T) {
 		ch:  ch2,
 	}
 	go func() {
-		vgService.Watch(&proto.Empty{}, server2)
+		vgService.Watch(&proto.WatchRequest{}, server2)
 	}()
 
 	notifier()
@@ -113,8 +118,9 @@ func testWatch(t *testing.T) {
 }
 
 func testVGService(t *testing.T, vg *command.VolumeGroup) {
+	ctx := context.Background()
 	spareGB := uint64(1)
-	vgService, _ := NewVGService(NewDeviceClassManager([]*DeviceClass{{Name: vg.Name(), VolumeGroup: vg.Name(), SpareGB: &spareGB}}))
+	vgService, _ := NewVGService(NewDeviceClassManager([]*DeviceClass{{Name: vg.Name(), VolumeGroup: vg.Name(), SpareGB: &spareGB}}), backend.NewLVM())
 	res, err := vgService.GetLVList(context.Background(), &proto.GetLVListRequest{DeviceClass: vg.Name()})
 	if err != nil {
 		t.Fatal(err)
@@ -124,7 +130,7 @@ func testVGService(t *testing.T, vg *command.VolumeGroup) {
 		t.Errorf("numVolumes must be 0: %d", numVols1)
 	}
 	testtag := "testtag"
-	_, err = vg.CreateVolume("test1", 1<<30, []string{testtag}, 0, "")
+	_, err = vg.CreateVolume(ctx, "test1", 1<<30, []string{testtag}, 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,7 +158,7 @@ func testVGService(t *testing.T, vg *command.VolumeGroup) {
 		t.Errorf(`Volume.Tags[0] != %s: %v`, testtag, vol.GetTags())
 	}
 
-	_, err = vg.CreateVolume("test2", 1<<30, nil, 0, "")
+	_, err = vg.CreateVolume(ctx, "test2", 1<<30, nil, 0, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -174,7 +180,7 @@ func testVGService(t *testing.T, vg *command.VolumeGroup) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	freeBytes, err := vg.Free()
+	freeBytes, err := vg.Free(ctx)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -183,18 +189,19 @@ func testVGService(t *testing.T, vg *command.VolumeGroup) {
 		t.Errorf("Free bytes mismatch: %d, expected: %d, freeBytes: %d", res2.GetFreeBytes(), expected, freeBytes)
 	}
 
-	_, err = vg.CreateVolume("test3", 1<<30, nil, 2, "4k")
+	_, err = vg.CreateVolume(ctx, "test3", 1<<30, nil, 2, "4k")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	_, err = vg.CreateVolume("test4", 1<<30, nil, 2, "4M")
+	_, err = vg.CreateVolume(ctx, "test4", 1<<30, nil, 2, "4M")
 	if err != nil {
 		t.Fatal(err)
 	}
 }
 
 func TestVGService(t *testing.T) {
+	ctx := context.Background()
 	uid := os.Getuid()
 	if uid != 0 {
 		t.Skip("run as root")
@@ -216,7 +223,7 @@ func TestVGService(t *testing.T) {
 	}
 	defer CleanLoopbackVG(vgName, []string{loop1, loop2}, []string{vgName + "1", vgName + "2"})
 
-	vg, err := command.FindVolumeGroup(vgName)
+	vg, err := command.FindVolumeGroup(ctx, vgName)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -225,6 +232,215 @@ func TestVGService(t *testing.T) {
 		testVGService(t, vg)
 	})
 	t.Run("Watch", func(t *testing.T) {
-		testWatch(t)
+		testWatch(t, backend.NewLVM(), vgName)
 	})
 }
+
+func TestVGServiceWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 5<<30)
+	b.AddPhysicalVolume("vg", "/dev/fake2", 5<<30)
+	spareGB := uint64(1)
+	manager := NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "vg", SpareGB: &spareGB, Default: true}})
+	vgService, _ := NewVGService(manager, b)
+	vg, err := b.FindVolumeGroup(ctx, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected %s for an unknown device-class, but actual %v", codes.NotFound, err)
+	}
+	_, err = vgService.GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected %s for an unknown device-class, but actual %v", codes.NotFound, err)
+	}
+
+	res, err := vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetVolumes()) != 0 {
+		t.Errorf("expected no volumes, but actual %v", res.GetVolumes())
+	}
+
+	_, err = vg.CreateVolume(ctx, "test1", 1<<30, []string{"testtag"}, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = vg.CreateVolume(ctx, "test2", 2<<30, nil, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetVolumes()) != 2 {
+		t.Fatalf("expected 2 volumes, but actual %v", res.GetVolumes())
+	}
+	vol := res.GetVolumes()[0]
+	if vol.GetName() != "test1" || vol.GetSizeGb() != 1 || len(vol.GetTags()) != 1 || vol.GetTags()[0] != "testtag" {
+		t.Errorf("unexpected volume: %v", vol)
+	}
+
+	res2, err := vgService.GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10 GiB - 3 GiB of volumes - 1 GiB of spare
+	if res2.GetFreeBytes() != 6<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(6<<30), res2.GetFreeBytes())
+	}
+
+	b.InjectFailure(fake.OpListVolumes, errors.New("injected"), 1)
+	_, err = vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: "ssd"})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected %s, but actual %v", codes.Internal, err)
+	}
+
+	// a volume is looked up by its name without listing all volumes.
+	b.InjectFailure(fake.OpListVolumes, errors.New("must not be called"), 0)
+	res, err = vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: "ssd", Name: "test2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetVolumes()) != 1 || res.GetVolumes()[0].GetName() != "test2" {
+		t.Errorf("expected only test2, but actual %v", res.GetVolumes())
+	}
+	res, err = vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: "ssd", Name: "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetVolumes()) != 0 {
+		t.Errorf("expected no volumes, but actual %v", res.GetVolumes())
+	}
+	b.ClearFailures()
+	b.InjectFailure(fake.OpFindVolume, errors.New("injected"), 1)
+	_, err = vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: "ssd", Name: "test2"})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected %s, but actual %v", codes.Internal, err)
+	}
+
+	t.Run("Watch", func(t *testing.T) {
+		testWatch(t, b, "vg")
+	})
+}
+
+type recordingWatchServer struct {
+	mockWatchServer
+	responses []*proto.WatchResponse
+}
+
+func (s *recordingWatchServer) Send(r *proto.WatchResponse) error {
+	s.responses = append(s.responses, r)
+	return nil
+}
+
+func TestVGServiceSpareWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg1", "/dev/fake1", 100<<30)
+	b.AddPhysicalVolume("vg2", "/dev/fake2", 100<<30)
+	spareGB := uint64(20)
+	manager := NewDeviceClassManager([]*DeviceClass{
+		{Name: "ssd", VolumeGroup: "vg1", Spare: "10%", Default: true},
+		{Name: "hdd", VolumeGroup: "vg2", SpareGB: &spareGB},
+	})
+	svc, _ := NewVGService(manager, b)
+
+	res, err := svc.GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetFreeBytes() != 90<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(90<<30), res.GetFreeBytes())
+	}
+
+	server := &recordingWatchServer{mockWatchServer: mockWatchServer{ctx: ctx}}
+	err = svc.(*vgService).send(server, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchRes := server.responses[0]
+	if watchRes.GetFreeBytes() != 90<<30 {
+		t.Errorf("expected %d, but actual %d", uint64(90<<30), watchRes.GetFreeBytes())
+	}
+	expected := map[string]*proto.WatchItem{
+		"ssd": {DeviceClass: "ssd", FreeBytes: 90 << 30, SizeBytes: 100 << 30, SpareBytes: 10 << 30, Default: true},
+		"hdd": {DeviceClass: "hdd", FreeBytes: 80 << 30, SizeBytes: 100 << 30, SpareBytes: 20 << 30},
+	}
+	if len(watchRes.GetItems()) != len(expected) {
+		t.Fatalf("expected %d items, but actual %v", len(expected), watchRes.GetItems())
+	}
+	for _, item := range watchRes.GetItems() {
+		e := expected[item.GetDeviceClass()]
+		if e == nil || item.GetFreeBytes() != e.FreeBytes || item.GetSizeBytes() != e.SizeBytes ||
+			item.GetSpareBytes() != e.SpareBytes || item.GetDefault() != e.Default {
+			t.Errorf("expected %v, but actual %v", e, item)
+		}
+	}
+}
+
+func TestVGServiceSetSpareWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	b := fake.New()
+	b.AddPhysicalVolume("vg1", "/dev/fake1", 100<<30)
+	manager := NewDeviceClassManager([]*DeviceClass{{Name: "ssd", VolumeGroup: "vg1", Spare: "10%", Default: true}})
+	svc, _ := NewVGService(manager, b)
+	ch := make(chan struct{}, 1)
+	svc.(*vgService).addWatcher(ch)
+
+	free := func() (uint64, uint64) {
+		t.Helper()
+		res, err := svc.GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: "ssd"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := &recordingWatchServer{mockWatchServer: mockWatchServer{ctx: ctx}}
+		if err := svc.(*vgService).send(server, nil); err != nil {
+			t.Fatal(err)
+		}
+		return res.GetFreeBytes(), server.responses[0].GetItems()[0].GetFreeBytes()
+	}
+	notified := func() bool {
+		select {
+		case <-ch:
+			return true
+		default:
+			return false
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		req      *proto.SetSpareRequest
+		code     codes.Code
+		free     uint64
+		notified bool
+	}{
+		{"unknown device-class", &proto.SetSpareRequest{DeviceClass: "unknown", Spare: "1%"}, codes.NotFound, 90 << 30, false},
+		{"invalid spare", &proto.SetSpareRequest{DeviceClass: "ssd", Spare: "foo"}, codes.InvalidArgument, 90 << 30, false},
+		{"percentage", &proto.SetSpareRequest{DeviceClass: "ssd", Spare: "2%"}, codes.OK, 98 << 30, true},
+		{"same spare", &proto.SetSpareRequest{DeviceClass: "ssd", Spare: "2%"}, codes.OK, 98 << 30, false},
+		{"quantity for the default", &proto.SetSpareRequest{Spare: "5Gi"}, codes.OK, 95 << 30, true},
+		{"reset", &proto.SetSpareRequest{DeviceClass: "ssd"}, codes.OK, 90 << 30, true},
+		{"reset again", &proto.SetSpareRequest{DeviceClass: "ssd"}, codes.OK, 90 << 30, false},
+	}
+	for _, tc := range testCases {
+		_, err := svc.SetSpare(ctx, tc.req)
+		if status.Code(err) != tc.code {
+			t.Errorf("%s: expected %s, but actual %v", tc.name, tc.code, err)
+		}
+		getFree, watchFree := free()
+		if getFree != tc.free || watchFree != tc.free {
+			t.Errorf("%s: expected %d, but actual GetFreeBytes=%d, Watch=%d", tc.name, tc.free, getFree, watchFree)
+		}
+		if n := notified(); n != tc.notified {
+			t.Errorf("%s: expected notified=%v, but actual %v", tc.name, tc.notified, n)
+		}
+	}
+}
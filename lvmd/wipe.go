@@ -0,0 +1,66 @@
+package lvmd
+
+import (
+	"context"
+
+	"github.com/cybozu-go/log"
+	"github.com/topolvm/topolvm/lvmd/backend"
+)
+
+const (
+	// wipeChunkSize is the size of the range wiped at once.
+	// Progress is reported for each chunk.
+	wipeChunkSize = 1 << 30
+
+	// wipedTag is the tag added to logical volumes that have been wiped.
+	wipedTag = "topolvm.cybozu.com/wiped"
+)
+
+// isWiped returns true if lv has already been wiped.
+func isWiped(lv backend.LogicalVolume) bool {
+	for _, tag := range lv.Tags() {
+		if tag == wipedTag {
+			return true
+		}
+	}
+	return false
+}
+
+// wipe wipes all blocks of lv according to policy.
+// progress is called after each chunk with the number of wiped bytes and the total bytes.
+// If progress returns an error, wiping is aborted and the error is returned.
+func wipe(ctx context.Context, lv backend.LogicalVolume, policy string, progress func(wiped, total uint64) error) error {
+	if policy == "" || isWiped(lv) {
+		return nil
+	}
+
+	total := lv.Size()
+	zero := policy == WipePolicyZero
+	for offset := uint64(0); offset < total; offset += wipeChunkSize {
+		length := uint64(wipeChunkSize)
+		if total-offset < length {
+			length = total - offset
+		}
+
+		err := lv.Discard(ctx, offset, length, zero)
+		if err != nil && !zero && policy == WipePolicyDiscardOrZero {
+			log.Warn("discard failed; falling back to zero-fill", map[string]interface{}{
+				log.FnError: err,
+				"name":      lv.Name(),
+			})
+			zero = true
+			err = lv.Discard(ctx, offset, length, zero)
+		}
+		if err != nil {
+			return err
+		}
+
+		if progress != nil {
+			if err := progress(offset+length, total); err != nil {
+				return err
+			}
+		}
+	}
+
+	return lv.AddTags(ctx, wipedTag)
+}
@@ -0,0 +1,178 @@
+package lvmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/backend"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/command"
+)
+
+type discardCall struct {
+	offset uint64
+	length uint64
+	zero   bool
+}
+
+// recordingLV records calls of Discard.
+// The fake backend allocates whole GiB, so size overrides Size to test a partial chunk.
+type recordingLV struct {
+	backend.LogicalVolume
+	size  uint64
+	calls []discardCall
+}
+
+func (l *recordingLV) Size() uint64 {
+	return l.size
+}
+
+func (l *recordingLV) Discard(ctx context.Context, offset, length uint64, zero bool) error {
+	l.calls = append(l.calls, discardCall{offset, length, zero})
+	return l.LogicalVolume.Discard(ctx, offset, length, zero)
+}
+
+func TestWipeWithFakeBackend(t *testing.T) {
+	ctx := context.Background()
+	size := uint64(wipeChunkSize*2 + wipeChunkSize/2)
+	discardErr := &command.LVMError{
+		Command: "blkdiscard",
+		Err:     errors.New("exit status 1"),
+		Stderr:  "blkdiscard: BLKDISCARD ioctl failed: Operation not supported",
+	}
+	progressErr := errors.New("aborted")
+
+	testCases := []struct {
+		name        string
+		policy      string
+		tags        []string
+		failures    int
+		progressErr error
+		expected    []discardCall
+		progress    []uint64
+		expectErr   error
+		expectWiped bool
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:   "discard",
+			policy: WipePolicyDiscard,
+			expected: []discardCall{
+				{0, wipeChunkSize, false},
+				{wipeChunkSize, wipeChunkSize, false},
+				{wipeChunkSize * 2, wipeChunkSize / 2, false},
+			},
+			progress:    []uint64{wipeChunkSize, wipeChunkSize * 2, size},
+			expectWiped: true,
+		},
+		{
+			name:   "zero",
+			policy: WipePolicyZero,
+			expected: []discardCall{
+				{0, wipeChunkSize, true},
+				{wipeChunkSize, wipeChunkSize, true},
+				{wipeChunkSize * 2, wipeChunkSize / 2, true},
+			},
+			progress:    []uint64{wipeChunkSize, wipeChunkSize * 2, size},
+			expectWiped: true,
+		},
+		{
+			name:     "discard-or-zero falls back to zero",
+			policy:   WipePolicyDiscardOrZero,
+			failures: 1,
+			expected: []discardCall{
+				{0, wipeChunkSize, false},
+				{0, wipeChunkSize, true},
+				{wipeChunkSize, wipeChunkSize, true},
+				{wipeChunkSize * 2, wipeChunkSize / 2, true},
+			},
+			progress:    []uint64{wipeChunkSize, wipeChunkSize * 2, size},
+			expectWiped: true,
+		},
+		{
+			name:     "discard does not fall back",
+			policy:   WipePolicyDiscard,
+			failures: 1,
+			expected: []discardCall{
+				{0, wipeChunkSize, false},
+			},
+			expectErr: discardErr,
+		},
+		{
+			name:        "progress aborts",
+			policy:      WipePolicyDiscard,
+			progressErr: progressErr,
+			expected: []discardCall{
+				{0, wipeChunkSize, false},
+			},
+			progress:  []uint64{wipeChunkSize},
+			expectErr: progressErr,
+		},
+		{
+			name:        "already wiped",
+			policy:      WipePolicyZero,
+			tags:        []string{wipedTag},
+			expectWiped: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		b := fake.New()
+		b.AddPhysicalVolume("vg", "/dev/fake1", 10<<30)
+		vg, err := b.FindVolumeGroup(ctx, "vg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		lv, err := vg.CreateVolume(ctx, "lv", 3<<30, tc.tags, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.failures > 0 {
+			b.InjectFailure(fake.OpDiscard, discardErr, tc.failures)
+		}
+
+		rec := &recordingLV{LogicalVolume: lv, size: size}
+		var progress []uint64
+		err = wipe(ctx, rec, tc.policy, func(wiped, total uint64) error {
+			if total != size {
+				t.Errorf("%s: expected total %d, but actual %d", tc.name, size, total)
+			}
+			progress = append(progress, wiped)
+			return tc.progressErr
+		})
+		if err != tc.expectErr {
+			t.Errorf("%s: expected error %v, but actual %v", tc.name, tc.expectErr, err)
+		}
+		if len(rec.calls) != len(tc.expected) {
+			t.Errorf("%s: expected %v, but actual %v", tc.name, tc.expected, rec.calls)
+		} else {
+			for i := range tc.expected {
+				if rec.calls[i] != tc.expected[i] {
+					t.Errorf("%s: expected %v, but actual %v", tc.name, tc.expected, rec.calls)
+					break
+				}
+			}
+		}
+		if len(progress) != len(tc.progress) {
+			t.Errorf("%s: expected progress %v, but actual %v", tc.name, tc.progress, progress)
+		} else {
+			for i := range tc.progress {
+				if progress[i] != tc.progress[i] {
+					t.Errorf("%s: expected progress %v, but actual %v", tc.name, tc.progress, progress)
+					break
+				}
+			}
+		}
+
+		found, err := vg.FindVolume(ctx, "lv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isWiped(found) != tc.expectWiped {
+			t.Errorf("%s: expected wiped %v, but actual tags %v", tc.name, tc.expectWiped, found.Tags())
+		}
+	}
+}
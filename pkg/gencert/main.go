@@ -9,6 +9,7 @@ import (
 	"flag"
 	"log"
 	"math/big"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,6 +20,8 @@ var (
 	host     = flag.String("host", "controller.topolvm-system.svc", "TLS hostname")
 	validFor = flag.Duration("duration", 36500*24*time.Hour, "Duration that certificate is valid for")
 	outDir   = flag.String("outdir", ".", "Directory where the certificate files are created")
+	cn       = flag.String("cn", "controller", "Common name of the certificate")
+	client   = flag.Bool("client", false, "Create a certificate for TLS clients, e.g. topolvm-node connecting to lvmd")
 )
 
 func main() {
@@ -33,17 +36,26 @@ func main() {
 	notBefore := time.Now()
 	notAfter := notBefore.Add(*validFor)
 
+	extKeyUsage := x509.ExtKeyUsageServerAuth
+	if *client {
+		extKeyUsage = x509.ExtKeyUsageClientAuth
+	}
+
 	template := x509.Certificate{
 		SerialNumber: big.NewInt(1),
 		Subject: pkix.Name{
-			CommonName: "controller",
+			CommonName: *cn,
 		},
 		NotBefore:             notBefore,
 		NotAfter:              notAfter,
 		KeyUsage:              keyUsage,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		ExtKeyUsage:           []x509.ExtKeyUsage{extKeyUsage},
 		BasicConstraintsValid: true,
-		DNSNames:              dnsAliases(*host),
+	}
+	if ip := net.ParseIP(*host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = dnsAliases(*host)
 	}
 
 	certBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, priv.Public(), priv)
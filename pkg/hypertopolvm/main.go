@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	lvmctl "github.com/topolvm/topolvm/pkg/lvmctl/cmd"
 	lvmd "github.com/topolvm/topolvm/pkg/lvmd/cmd"
 	controller "github.com/topolvm/topolvm/pkg/topolvm-controller/cmd"
 	node "github.com/topolvm/topolvm/pkg/topolvm-node/cmd"
@@ -19,6 +20,7 @@ COMMAND:
     topolvm-node:        TopoLVM CSI node service.
     topolvm-scheduler:   Scheduler extender.
     lvmd:                gRPC service to manage LVM volumes.
+    lvmctl:              Command-line client of lvmd.
 `)
 }
 
@@ -36,6 +38,8 @@ func main() {
 	switch name {
 	case "lvmd":
 		lvmd.Execute()
+	case "lvmctl":
+		lvmctl.Execute()
 	case "topolvm-scheduler":
 		scheduler.Execute()
 	case "topolvm-node":
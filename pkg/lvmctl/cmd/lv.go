@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+var lvConfig struct {
+	sizeGB uint64
+	tags   []string
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list logical volumes of a device-class",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		conn, err := dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		res, err := proto.NewVGServiceClient(conn).GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: config.deviceClass})
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(res.GetVolumes()))
+		for i, lv := range res.GetVolumes() {
+			rows[i] = lvRow(lv)
+		}
+		return printMessage(cmd.OutOrStdout(), res, lvHeader, rows)
+	},
+}
+
+var createCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "create a logical volume",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if lvConfig.sizeGB == 0 {
+			return errors.New("size-gb must be specified")
+		}
+		cmd.SilenceUsage = true
+		conn, err := dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		res, err := proto.NewLVServiceClient(conn).CreateLV(ctx, &proto.CreateLVRequest{
+			Name:        args[0],
+			DeviceClass: config.deviceClass,
+			SizeGb:      lvConfig.sizeGB,
+			Tags:        lvConfig.tags,
+		})
+		if err != nil {
+			return err
+		}
+		return printMessage(cmd.OutOrStdout(), res, lvHeader, [][]string{lvRow(res.GetVolume())})
+	},
+}
+
+var resizeCmd = &cobra.Command{
+	Use:   "resize NAME",
+	Short: "expand a logical volume",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if lvConfig.sizeGB == 0 {
+			return errors.New("size-gb must be specified")
+		}
+		cmd.SilenceUsage = true
+		conn, err := dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		_, err = proto.NewLVServiceClient(conn).ResizeLV(ctx, &proto.ResizeLVRequest{
+			Name:        args[0],
+			DeviceClass: config.deviceClass,
+			SizeGb:      lvConfig.sizeGB,
+		})
+		return err
+	},
+}
+
+var removeCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "remove a logical volume",
+	Long: `Remove a logical volume.
+
+If the device-class has trash-retention, the logical volume is moved to the trash.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		conn, err := dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		_, err = proto.NewLVServiceClient(conn).RemoveLV(ctx, &proto.RemoveLVRequest{
+			Name:        args[0],
+			DeviceClass: config.deviceClass,
+		})
+		return err
+	},
+}
+
+var lvHeader = []string{"NAME", "SIZE_GB", "MAJOR", "MINOR", "TAGS"}
+
+func lvRow(lv *proto.LogicalVolume) []string {
+	return []string{
+		lv.GetName(),
+		strconv.FormatUint(lv.GetSizeGb(), 10),
+		strconv.FormatUint(uint64(lv.GetDevMajor()), 10),
+		strconv.FormatUint(uint64(lv.GetDevMinor()), 10),
+		strings.Join(lv.GetTags(), ","),
+	}
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{listCmd, createCmd, resizeCmd, removeCmd} {
+		addDeviceClassFlag(cmd)
+		rootCmd.AddCommand(cmd)
+	}
+	createCmd.Flags().Uint64Var(&lvConfig.sizeGB, "size-gb", 0, "Size of the logical volume in GiB")
+	createCmd.Flags().StringSliceVar(&lvConfig.tags, "tag", nil, "Tags of the logical volume")
+	resizeCmd.Flags().Uint64Var(&lvConfig.sizeGB, "size-gb", 0, "New size of the logical volume in GiB")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestList(t *testing.T) {
+	s := startLVMd(t)
+
+	testCases := []struct {
+		args     []string
+		expected [][]string
+	}{
+		{
+			args: []string{"list"},
+			expected: [][]string{
+				{"NAME", "SIZE_GB", "MAJOR", "MINOR", "TAGS"},
+				{"lv1", "2", "253", "0", "foo,bar"},
+			},
+		},
+		{
+			args: []string{"list", "-d", "hdd"},
+			expected: [][]string{
+				{"NAME", "SIZE_GB", "MAJOR", "MINOR", "TAGS"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		rows := tableFields(lvmctlOutput(t, s, append([]string{"-o", "table"}, tc.args...)...))
+		if !equalRows(rows, tc.expected) {
+			t.Errorf("%v: expected %v, but actual %v", tc.args, tc.expected, rows)
+		}
+	}
+
+	res := &proto.GetLVListResponse{}
+	if err := protojson.Unmarshal(lvmctlOutput(t, s, "-o", "json", "list", "-d", "ssd"), res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.GetVolumes()) != 1 {
+		t.Fatalf("expected 1 volume, but actual %v", res.GetVolumes())
+	}
+	lv := res.GetVolumes()[0]
+	if lv.GetName() != "lv1" || lv.GetSizeGb() != 2 || lv.GetDevMajor() != 253 || len(lv.GetTags()) != 2 {
+		t.Errorf("unexpected volume: %v", lv)
+	}
+
+	if err := runLvmctl(context.Background(), s, io.Discard, "list", "-d", "unknown"); err == nil {
+		t.Error("expected an error for an unknown device-class")
+	}
+}
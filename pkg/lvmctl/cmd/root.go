@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var config struct {
+	socket      string
+	output      string
+	timeout     time.Duration
+	deviceClass string
+
+	address       string
+	tlsCertFile   string
+	tlsKeyFile    string
+	tlsCAFile     string
+	tlsServerName string
+}
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:     "lvmctl",
+	Version: topolvm.Version,
+	Short:   "a command-line client of lvmd",
+	// errors are printed by Execute.
+	SilenceErrors: true,
+	Long: `A command-line client of lvmd.
+
+lvmctl talks to lvmd through its UNIX domain socket or TCP with
+mutual TLS, and shows
+what lvmd believes about device-classes and logical volumes.
+`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if config.output != outputTable && config.output != outputJSON {
+			return fmt.Errorf("output must be %q or %q: %s", outputTable, outputJSON, config.output)
+		}
+		return nil
+	},
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		stop()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	fs := rootCmd.PersistentFlags()
+	fs.StringVar(&config.socket, "socket", topolvm.DefaultLVMdSocket, "UNIX domain socket of lvmd service")
+	fs.StringVar(&config.address, "address", "", "TCP address of lvmd service; if set, lvmd is connected with mutual TLS instead of socket")
+	fs.StringVar(&config.tlsCertFile, "tls-cert-file", "", "Client certificate file for address")
+	fs.StringVar(&config.tlsKeyFile, "tls-key-file", "", "Client private key file for address")
+	fs.StringVar(&config.tlsCAFile, "tls-ca-file", "", "CA certificate file to verify lvmd")
+	fs.StringVar(&config.tlsServerName, "tls-server-name", "", "Server name to verify the certificate of lvmd, the host of address if empty")
+	fs.StringVarP(&config.output, "output", "o", outputTable, `Output format, "table" or "json"`)
+	fs.DurationVar(&config.timeout, "timeout", 10*time.Second, "Time limit of each request, except for watch")
+}
+
+// addDeviceClassFlag adds --device-class flag to cmd.
+func addDeviceClassFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&config.deviceClass, "device-class", "d", "", "Device-class of the request, the default device-class if empty")
+}
+
+// dial connects to lvmd.
+func dial() (*grpc.ClientConn, error) {
+	if config.address != "" {
+		tlsConfig, err := tlsconfig.Client(config.tlsCertFile, config.tlsKeyFile, config.tlsCAFile, config.tlsServerName)
+		if err != nil {
+			return nil, err
+		}
+		return grpc.Dial(config.address, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	dialer := &net.Dialer{}
+	dialFunc := func(ctx context.Context, a string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", a)
+	}
+	return grpc.Dial(config.socket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialFunc))
+}
+
+// requestContext returns a context for a unary request.
+func requestContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if config.timeout == 0 {
+		return context.WithCancel(cmd.Context())
+	}
+	return context.WithTimeout(cmd.Context(), config.timeout)
+}
+
+// printMessage prints msg as a line of JSON, or rows as a table with header.
+func printMessage(w io.Writer, msg protobuf.Message, header []string, rows [][]string) error {
+	if config.output == outputJSON {
+		data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd"
+	"github.com/topolvm/topolvm/lvmd/backend/fake"
+	"github.com/topolvm/topolvm/lvmd/lvmdtest"
+)
+
+// startLVMd serves lvmd with two device-classes on the fake backend.
+//
+// "ssd" is the default device-class on "vg" of 20 GiB with the default spare of 10 GiB.
+// "hdd" is on "vg2" of 30 GiB with no spare.
+// "lv1" of 2 GiB is created in "vg".
+func startLVMd(t *testing.T) *lvmdtest.Server {
+	t.Helper()
+	b := fake.New()
+	b.AddPhysicalVolume("vg", "/dev/fake1", 20<<30)
+	b.AddPhysicalVolume("vg2", "/dev/fake2", 30<<30)
+	vg, err := b.FindVolumeGroup(context.Background(), "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vg.CreateVolume(context.Background(), "lv1", 2<<30, []string{"foo", "bar"}, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	noSpare := uint64(0)
+	s, err := lvmdtest.NewServer(t.TempDir(), b, []*lvmd.DeviceClass{
+		{Name: "ssd", VolumeGroup: "vg", Default: true},
+		{Name: "hdd", VolumeGroup: "vg2", SpareGB: &noSpare},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+// runLvmctl runs lvmctl with args connecting to s, and writes the output to out.
+func runLvmctl(ctx context.Context, s *lvmdtest.Server, out io.Writer, args ...string) error {
+	// flags keep their values between executions.
+	config.deviceClass = ""
+	watchConfig.lvEvents = false
+	watchConfig.resumeToken = ""
+
+	args = append([]string{"--socket", s.Socket}, args...)
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(out)
+	// cobra gives the context of the root command to a subcommand only once,
+	// so the context is set to the subcommand directly.
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		return err
+	}
+	return cmd.ExecuteContext(ctx)
+}
+
+// lvmctlOutput runs lvmctl and returns its output.
+func lvmctlOutput(t *testing.T, s *lvmdtest.Server, args ...string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	if err := runLvmctl(context.Background(), s, buf, args...); err != nil {
+		t.Fatalf("lvmctl %v: %v", args, err)
+	}
+	return buf.Bytes()
+}
+
+// tableFields returns the fields of each line of a table.
+func tableFields(data []byte) [][]string {
+	var rows [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		rows = append(rows, strings.Fields(scanner.Text()))
+	}
+	return rows
+}
+
+func equalRows(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.Join(a[i], " ") != strings.Join(b[i], " ") {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOutputFormat(t *testing.T) {
+	s := startLVMd(t)
+	err := runLvmctl(context.Background(), s, io.Discard, "-o", "yaml", "list")
+	if err == nil {
+		t.Error("expected an error for an unknown output format")
+	}
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+var deviceClassesCmd = &cobra.Command{
+	Use:   "device-classes",
+	Short: "list device-classes with their free, total and spare bytes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		conn, err := dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		// lvmd sends the current status of device-classes as the first response of Watch.
+		stream, err := proto.NewVGServiceClient(conn).Watch(ctx, &proto.WatchRequest{})
+		if err != nil {
+			return err
+		}
+		res, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		return printWatchResponse(cmd, res)
+	},
+}
+
+var freeCmd = &cobra.Command{
+	Use:   "free",
+	Short: "show free bytes of a device-class",
+	Long: `Show free bytes of a device-class.
+
+The spare capacity of the device-class is already subtracted.
+If the spare capacity is overridden by topolvm-node, the overridden one is used.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		conn, err := dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ctx, cancel := requestContext(cmd)
+		defer cancel()
+		res, err := proto.NewVGServiceClient(conn).GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: config.deviceClass})
+		if err != nil {
+			return err
+		}
+		return printMessage(cmd.OutOrStdout(), res,
+			[]string{"FREE_BYTES"},
+			[][]string{{strconv.FormatUint(res.GetFreeBytes(), 10)}})
+	},
+}
+
+var watchConfig struct {
+	lvEvents    bool
+	resumeToken string
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "print the status of device-classes whenever lvmd notifies a change",
+	Long: `Print the status of device-classes whenever lvmd notifies a change.
+
+With --lv-events, changes of logical volumes are printed instead.
+The JSON output contains the token to pass to --resume-token.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		conn, err := dial()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		stream, err := proto.NewVGServiceClient(conn).Watch(cmd.Context(), &proto.WatchRequest{
+			LvEvents:    watchConfig.lvEvents,
+			ResumeToken: watchConfig.resumeToken,
+		})
+		if err != nil {
+			return err
+		}
+		for {
+			res, err := stream.Recv()
+			if err != nil {
+				// interrupted by a signal
+				if cmd.Context().Err() != nil {
+					return nil
+				}
+				return err
+			}
+			if watchConfig.lvEvents {
+				err = printLVEvents(cmd, res)
+			} else {
+				err = printWatchResponse(cmd, res)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	},
+}
+
+func printWatchResponse(cmd *cobra.Command, res *proto.WatchResponse) error {
+	rows := make([][]string, len(res.GetItems()))
+	for i, item := range res.GetItems() {
+		rows[i] = []string{
+			item.GetDeviceClass(),
+			strconv.FormatUint(item.GetFreeBytes(), 10),
+			strconv.FormatUint(item.GetSizeBytes(), 10),
+			strconv.FormatUint(item.GetSpareBytes(), 10),
+		}
+	}
+	return printMessage(cmd.OutOrStdout(), res, []string{"DEVICE_CLASS", "FREE_BYTES", "SIZE_BYTES", "SPARE_BYTES"}, rows)
+}
+
+func printLVEvents(cmd *cobra.Command, res *proto.WatchResponse) error {
+	if config.output == outputTable && len(res.GetEvents()) == 0 {
+		return nil
+	}
+	header := append([]string{"EVENT", "DEVICE_CLASS"}, lvHeader...)
+	header = append(header, "POOL_USED_BYTES", "POOL_SIZE_BYTES")
+	rows := make([][]string, len(res.GetEvents()))
+	for i, ev := range res.GetEvents() {
+		row := []string{ev.GetType().String(), ev.GetDeviceClass()}
+		if pool := ev.GetPool(); pool != nil {
+			row = append(row, make([]string, len(lvHeader))...)
+			row = append(row, strconv.FormatUint(pool.GetUsedBytes(), 10), strconv.FormatUint(pool.GetSizeBytes(), 10))
+		} else {
+			row = append(row, lvRow(ev.GetVolume())...)
+		}
+		rows[i] = row
+	}
+	return printMessage(cmd.OutOrStdout(), res, header, rows)
+}
+
+func init() {
+	addDeviceClassFlag(freeCmd)
+	watchCmd.Flags().BoolVar(&watchConfig.lvEvents, "lv-events", false, "Print changes of logical volumes")
+	watchCmd.Flags().StringVar(&watchConfig.resumeToken, "resume-token", "", "Resume LV events after this token")
+	rootCmd.AddCommand(deviceClassesCmd)
+	rootCmd.AddCommand(freeCmd)
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm/lvmd/lvmdtest"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func gib(n uint64) string {
+	return strconv.FormatUint(n<<30, 10)
+}
+
+func TestDeviceClasses(t *testing.T) {
+	s := startLVMd(t)
+
+	rows := tableFields(lvmctlOutput(t, s, "-o", "table", "device-classes"))
+	expected := [][]string{
+		{"DEVICE_CLASS", "FREE_BYTES", "SIZE_BYTES", "SPARE_BYTES"},
+		{"ssd", gib(8), gib(20), gib(10)},
+		{"hdd", gib(30), gib(30), gib(0)},
+	}
+	if !equalRows(rows, expected) {
+		t.Errorf("expected %v, but actual %v", expected, rows)
+	}
+
+	res := &proto.WatchResponse{}
+	if err := protojson.Unmarshal(lvmctlOutput(t, s, "-o", "json", "device-classes"), res); err != nil {
+		t.Fatal(err)
+	}
+	if res.GetFreeBytes() != 8<<30 {
+		t.Errorf("expected free bytes of the default device-class to be %d, but actual %d", uint64(8<<30), res.GetFreeBytes())
+	}
+	expectedItems := []*proto.WatchItem{
+		{DeviceClass: "ssd", FreeBytes: 8 << 30, SizeBytes: 20 << 30, SpareBytes: 10 << 30, Default: true},
+		{DeviceClass: "hdd", FreeBytes: 30 << 30, SizeBytes: 30 << 30},
+	}
+	if len(res.GetItems()) != len(expectedItems) {
+		t.Fatalf("expected %v, but actual %v", expectedItems, res.GetItems())
+	}
+	for i, item := range res.GetItems() {
+		e := expectedItems[i]
+		if item.GetDeviceClass() != e.DeviceClass || item.GetFreeBytes() != e.FreeBytes ||
+			item.GetSizeBytes() != e.SizeBytes || item.GetSpareBytes() != e.SpareBytes || item.GetDefault() != e.Default {
+			t.Errorf("expected %v, but actual %v", e, item)
+		}
+	}
+}
+
+// watchLvmctl runs "lvmctl watch" with args until the returned function is called.
+// Lines of the output are sent to the returned channel.
+func watchLvmctl(t *testing.T, s *lvmdtest.Server, args ...string) (<-chan string, func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := runLvmctl(ctx, s, w, append([]string{"watch"}, args...)...)
+		w.Close()
+		done <- err
+	}()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				// drain the output until the command returns.
+			}
+		}
+	}()
+
+	return lines, func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("watch should return no error when canceled, but actual %v", err)
+		}
+	}
+}
+
+func nextLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			t.Fatal("watch finished unexpectedly")
+		}
+		return line
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the output of watch")
+	}
+	return ""
+}
+
+func createLV(t *testing.T, s *lvmdtest.Server, name string) {
+	t.Helper()
+	conn, err := s.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = proto.NewLVServiceClient(conn).CreateLV(context.Background(), &proto.CreateLVRequest{Name: name, DeviceClass: "hdd", SizeGb: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchLVEventsTable(t *testing.T) {
+	s := startLVMd(t)
+	lines, stop := watchLvmctl(t, s, "-o", "table", "--lv-events")
+	defer stop()
+
+	header := []string{"EVENT", "DEVICE_CLASS", "NAME", "SIZE_GB", "MAJOR", "MINOR", "TAGS", "POOL_USED_BYTES", "POOL_SIZE_BYTES"}
+	expected := [][]string{
+		// existing volumes and the usage of pools are reported first.
+		header,
+		{"POOL_USAGE_CHANGED", "hdd", gib(0), gib(30)},
+		{"CREATED", "ssd", "lv1", "2", "253", "0", "foo,bar"},
+		{"POOL_USAGE_CHANGED", "ssd", gib(2), gib(20)},
+		header,
+		{"CREATED", "hdd", "lv2", "1", "253", "1"},
+		{"POOL_USAGE_CHANGED", "hdd", gib(1), gib(30)},
+	}
+	var rows [][]string
+	for i := range expected {
+		if i == 4 {
+			createLV(t, s, "lv2")
+		}
+		rows = append(rows, strings.Fields(nextLine(t, lines)))
+	}
+	if !equalRows(rows, expected) {
+		t.Errorf("expected %v, but actual %v", expected, rows)
+	}
+}
+
+func TestWatchLVEventsJSON(t *testing.T) {
+	s := startLVMd(t)
+	lines, stop := watchLvmctl(t, s, "-o", "json", "--lv-events")
+	defer stop()
+
+	first := &proto.WatchResponse{}
+	if err := protojson.Unmarshal([]byte(nextLine(t, lines)), first); err != nil {
+		t.Fatal(err)
+	}
+	if len(first.GetEvents()) != 3 || first.GetEvents()[1].GetType() != proto.LVEvent_CREATED || first.GetEvents()[1].GetVolume().GetName() != "lv1" {
+		t.Errorf("expected lv1 to be reported as created, but actual %v", first.GetEvents())
+	}
+	if first.GetResumeToken() == "" {
+		t.Error("resume token is empty")
+	}
+	// the status of device-classes is also printed in JSON.
+	if len(first.GetItems()) != 2 {
+		t.Errorf("expected 2 device-classes, but actual %v", first.GetItems())
+	}
+
+	createLV(t, s, "lv2")
+	second := &proto.WatchResponse{}
+	if err := protojson.Unmarshal([]byte(nextLine(t, lines)), second); err != nil {
+		t.Fatal(err)
+	}
+	if len(second.GetEvents()) != 2 || second.GetEvents()[0].GetType() != proto.LVEvent_CREATED ||
+		second.GetEvents()[0].GetDeviceClass() != "hdd" || second.GetEvents()[0].GetVolume().GetName() != "lv2" {
+		t.Fatalf("expected lv2 to be reported as created, but actual %v", second.GetEvents())
+	}
+	pool := second.GetEvents()[len(second.GetEvents())-1]
+	if pool.GetType() != proto.LVEvent_POOL_USAGE_CHANGED || pool.GetPool().GetUsedBytes() != 1<<30 || pool.GetPool().GetSizeBytes() != 30<<30 {
+		t.Errorf("expected the usage of the pool of hdd to be reported, but actual %v", pool)
+	}
+	if second.GetResumeToken() == first.GetResumeToken() {
+		t.Errorf("expected the resume token to change, but actual %s", second.GetResumeToken())
+	}
+}
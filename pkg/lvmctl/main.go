@@ -0,0 +1,7 @@
+package main
+
+import "github.com/topolvm/topolvm/pkg/lvmctl/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var healthcheckConfig struct {
+	deviceClass string
+	liveness    bool
+	timeout     time.Duration
+}
+
+var healthcheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "check the health of lvmd",
+	Long: `Check the health of lvmd through its UNIX domain socket.
+
+This exits with a non-zero status unless lvmd is serving, i.e. the volume
+groups of all device-classes, or the device-class given by --device-class,
+are available.  With --liveness, this only checks that lvmd responds.
+
+This is intended to be used as a probe of the container.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if err := loadConfig(); err != nil {
+			return err
+		}
+		return healthcheck()
+	},
+}
+
+func healthcheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthcheckConfig.timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	dialFunc := func(ctx context.Context, a string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", a)
+	}
+	conn, err := grpc.DialContext(ctx, config.SocketName, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialFunc))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: healthcheckConfig.deviceClass})
+	if err != nil {
+		return err
+	}
+	if healthcheckConfig.liveness {
+		return nil
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("lvmd is not serving: %s", res.GetStatus())
+	}
+	return nil
+}
+
+func init() {
+	fs := healthcheckCmd.Flags()
+	fs.StringVar(&healthcheckConfig.deviceClass, "device-class", "", "Device-class to check, all device-classes if empty")
+	fs.BoolVar(&healthcheckConfig.liveness, "liveness", false, "Only check that lvmd responds")
+	fs.DurationVar(&healthcheckConfig.timeout, "timeout", 5*time.Second, "Time limit of the check")
+	rootCmd.AddCommand(healthcheckCmd)
+}
@@ -2,31 +2,61 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/cybozu-go/log"
 	"github.com/cybozu-go/well"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/topolvm/topolvm"
 	"github.com/topolvm/topolvm/lvmd"
+	"github.com/topolvm/topolvm/lvmd/backend"
 	"github.com/topolvm/topolvm/lvmd/command"
 	"github.com/topolvm/topolvm/lvmd/proto"
+	"github.com/topolvm/topolvm/lvmd/tlsconfig"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"sigs.k8s.io/yaml"
 )
 
 var cfgFilePath string
 
+// healthCheckInterval is the interval to check the volume groups of device-classes.
+const healthCheckInterval = 10 * time.Second
+
 // Config represents configuration parameters for lvmd
 type Config struct {
 	// SocketName is Unix domain socket name
 	SocketName string `json:"socket-name"`
 	// DeviceClasses is
 	DeviceClasses []*lvmd.DeviceClass `json:"device-classes"`
+	// CommandTimeout is the time limit of each LVM command
+	CommandTimeout string `json:"command-timeout"`
+	// CommandTimeouts overrides CommandTimeout for commands keyed by their names
+	CommandTimeouts map[string]string `json:"command-timeouts"`
+	// MetricsBindAddress is the listen address for Prometheus metrics
+	MetricsBindAddress string `json:"metrics-bind-address"`
+	// AllowedPeers restricts processes connecting to SocketName
+	AllowedPeers []*lvmd.PeerRule `json:"allowed-peers"`
+	// ListenAddress is the TCP address to serve gRPC with mutual TLS
+	ListenAddress string `json:"listen-address"`
+	// TLSCertFile is the certificate file of lvmd for ListenAddress
+	TLSCertFile string `json:"tls-cert-file"`
+	// TLSKeyFile is the private key file of lvmd for ListenAddress
+	TLSKeyFile string `json:"tls-key-file"`
+	// TLSClientCAFile is the CA certificate file to verify clients connecting to ListenAddress
+	TLSClientCAFile string `json:"tls-client-ca-file"`
+	// AllowedClients restricts clients connecting to ListenAddress
+	AllowedClients []*lvmd.ClientRule `json:"allowed-clients"`
 }
 
 var config = &Config{
@@ -43,9 +73,9 @@ var rootCmd = &cobra.Command{
 lvmd handles a LVM volume group and provides gRPC API to manage logical
 volumes in the volume group.
 
-If command-line option "spare" is not zero, that value multiplied by 1 GiB
-will be subtracted from the value lvmd reports as the free space of the
-volume group.
+The spare capacity of each device-class, given by "spare" or "spare-gb" in
+the configuration file, is subtracted from the value lvmd reports as the free
+space of the volume group.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
@@ -53,17 +83,22 @@ volume group.
 	},
 }
 
-func subMain() error {
-	err := well.LogConfig{}.Apply()
+// loadConfig reads the configuration file into config.
+func loadConfig() error {
+	b, err := os.ReadFile(cfgFilePath)
 	if err != nil {
 		return err
 	}
+	return yaml.Unmarshal(b, &config)
+}
 
-	b, err := os.ReadFile(cfgFilePath)
+func subMain() error {
+	err := well.LogConfig{}.Apply()
 	if err != nil {
 		return err
 	}
-	err = yaml.Unmarshal(b, &config)
+
+	err = loadConfig()
 	if err != nil {
 		return err
 	}
@@ -76,8 +111,39 @@ func subMain() error {
 	if err != nil {
 		return err
 	}
+	err = lvmd.ValidatePeerRules(config.AllowedPeers)
+	if err != nil {
+		return err
+	}
+	err = lvmd.ValidateClientRules(config.AllowedClients)
+	if err != nil {
+		return err
+	}
+	if config.CommandTimeout != "" {
+		d, err := time.ParseDuration(config.CommandTimeout)
+		if err != nil {
+			return fmt.Errorf("command-timeout should be a duration like \"2m\": %w", err)
+		}
+		command.Timeout = d
+	}
+	for name, v := range config.CommandTimeouts {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("command-timeouts of %s should be a duration like \"2m\": %w", name, err)
+		}
+		command.Timeouts[name] = d
+	}
+	var tlsConfig *tls.Config
+	if config.ListenAddress != "" {
+		tlsConfig, err = tlsconfig.Server(config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile)
+		if err != nil {
+			return fmt.Errorf("invalid TLS settings for listen-address: %w", err)
+		}
+	}
+
+	lvm := backend.NewLVM()
 	for _, dc := range config.DeviceClasses {
-		_, err := command.FindVolumeGroup(dc.VolumeGroup)
+		_, err := lvm.FindVolumeGroup(context.Background(), dc.VolumeGroup)
 		if err != nil {
 			log.Error("Volume group not found:", map[string]interface{}{
 				"volume_group": dc.VolumeGroup,
@@ -96,19 +162,90 @@ func subMain() error {
 	if err != nil {
 		return err
 	}
-	grpcServer := grpc.NewServer()
 	manager := lvmd.NewDeviceClassManager(config.DeviceClasses)
-	vgService, notifier := lvmd.NewVGService(manager)
-	proto.RegisterVGServiceServer(grpcServer, vgService)
-	proto.RegisterLVServiceServer(grpcServer, lvmd.NewLVService(manager, notifier))
+	vgService, notifier := lvmd.NewVGService(manager, lvm)
+	lvService := lvmd.NewLVService(manager, lvm, notifier)
+	pvService := lvmd.NewPVService(manager, lvm, notifier)
+	healthServer := health.NewServer()
+	lvmd.UpdateHealth(context.Background(), lvm, manager, healthServer)
+	newServer := func(opts ...grpc.ServerOption) *grpc.Server {
+		s := grpc.NewServer(opts...)
+		proto.RegisterVGServiceServer(s, vgService)
+		proto.RegisterLVServiceServer(s, lvService)
+		proto.RegisterPVServiceServer(s, pvService)
+		healthpb.RegisterHealthServer(s, healthServer)
+		reflection.Register(s)
+		return s
+	}
+
+	var socketOpts []grpc.ServerOption
+	if len(config.AllowedPeers) > 0 {
+		unary, stream := lvmd.NewPeerAuthorizer(config.AllowedPeers)
+		socketOpts = append(socketOpts,
+			grpc.Creds(lvmd.NewPeerCredentials()),
+			grpc.UnaryInterceptor(unary),
+			grpc.StreamInterceptor(stream),
+		)
+	}
+	grpcServers := []*grpc.Server{newServer(socketOpts...)}
 	well.Go(func(ctx context.Context) error {
-		return grpcServer.Serve(lis)
+		return grpcServers[0].Serve(lis)
 	})
+	if tlsConfig != nil {
+		tcpLis, err := net.Listen("tcp", config.ListenAddress)
+		if err != nil {
+			return err
+		}
+		tcpOpts := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}
+		if len(config.AllowedClients) > 0 {
+			unary, stream := lvmd.NewClientAuthorizer(config.AllowedClients)
+			tcpOpts = append(tcpOpts,
+				grpc.UnaryInterceptor(unary),
+				grpc.StreamInterceptor(stream),
+			)
+		}
+		tcpServer := newServer(tcpOpts...)
+		grpcServers = append(grpcServers, tcpServer)
+		well.Go(func(ctx context.Context) error {
+			return tcpServer.Serve(tcpLis)
+		})
+		log.Info("serving gRPC with mutual TLS", map[string]interface{}{
+			"listen_address": config.ListenAddress,
+		})
+	}
+	if config.MetricsBindAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer := &well.HTTPServer{
+			Server: &http.Server{
+				Addr:    config.MetricsBindAddress,
+				Handler: mux,
+			},
+		}
+		if err := metricsServer.ListenAndServe(); err != nil {
+			return err
+		}
+	}
 	well.Go(func(ctx context.Context) error {
 		<-ctx.Done()
-		grpcServer.GracefulStop()
+		healthServer.Shutdown()
+		for _, s := range grpcServers {
+			s.GracefulStop()
+		}
 		return nil
 	})
+	well.Go(func(ctx context.Context) error {
+		ticker := time.NewTicker(healthCheckInterval)
+		for {
+			select {
+			case <-ctx.Done():
+				ticker.Stop()
+				return nil
+			case <-ticker.C:
+				lvmd.UpdateHealth(ctx, lvm, manager, healthServer)
+			}
+		}
+	})
 	well.Go(func(ctx context.Context) error {
 		ticker := time.NewTicker(10 * time.Minute)
 		for {
@@ -117,6 +254,12 @@ func subMain() error {
 				ticker.Stop()
 				return nil
 			case <-ticker.C:
+				_, err := lvmd.PurgeTrash(ctx, lvm, manager, time.Now())
+				if err != nil {
+					log.Error("failed to purge the trash", map[string]interface{}{
+						log.FnError: err,
+					})
+				}
 				notifier()
 			}
 		}
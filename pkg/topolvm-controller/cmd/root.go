@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/topolvm/topolvm"
@@ -12,13 +13,14 @@ import (
 )
 
 var config struct {
-	csiSocket        string
-	metricsAddr      string
-	webhookAddr      string
-	certDir          string
-	leaderElectionID string
-	skipNodeFinalize bool
-	zapOpts          zap.Options
+	csiSocket          string
+	metricsAddr        string
+	webhookAddr        string
+	certDir            string
+	leaderElectionID   string
+	skipNodeFinalize   bool
+	reservationTimeout time.Duration
+	zapOpts            zap.Options
 }
 
 var rootCmd = &cobra.Command{
@@ -51,6 +53,7 @@ func init() {
 	fs.StringVar(&config.certDir, "cert-dir", "", "certificate directory")
 	fs.StringVar(&config.leaderElectionID, "leader-election-id", "topolvm", "ID for leader election by controller-runtime")
 	fs.BoolVar(&config.skipNodeFinalize, "skip-node-finalize", false, "skips automatic cleanup of PhysicalVolumeClaims when a Node is deleted")
+	fs.DurationVar(&config.reservationTimeout, "capacity-reservation-timeout", 0, "How long capacity is reserved for scheduled volumes in GetCapacity. 0 disables the reservation.")
 
 	goflags := flag.NewFlagSet("klog", flag.ExitOnError)
 	klog.InitFlags(goflags)
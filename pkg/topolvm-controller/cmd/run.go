@@ -13,6 +13,8 @@ import (
 	"github.com/topolvm/topolvm/driver"
 	"github.com/topolvm/topolvm/driver/k8s"
 	"github.com/topolvm/topolvm/hook"
+	"github.com/topolvm/topolvm/policy"
+	"github.com/topolvm/topolvm/reservation"
 	"github.com/topolvm/topolvm/runners"
 	"google.golang.org/grpc"
 	storagev1 "k8s.io/api/storage/v1"
@@ -76,10 +78,12 @@ func subMain() error {
 	wh := mgr.GetWebhookServer()
 	wh.Register("/pod/mutate", hook.PodMutator(mgr.GetClient(), mgr.GetAPIReader(), dec))
 	wh.Register("/pvc/mutate", hook.PVCMutator(mgr.GetClient(), mgr.GetAPIReader(), dec))
+	wh.Register("/pvc/validate", hook.PVCValidator(mgr.GetClient(), dec))
 
 	// register controllers
 	nodecontroller := &controllers.NodeReconciler{
 		Client:           mgr.GetClient(),
+		Recorder:         mgr.GetEventRecorderFor("topolvm-controller"),
 		SkipNodeFinalize: config.skipNodeFinalize,
 	}
 	if err := nodecontroller.SetupWithManager(mgr); err != nil {
@@ -87,6 +91,15 @@ func subMain() error {
 		return err
 	}
 
+	pvcontroller := &controllers.PersistentVolumeReconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("topolvm-controller"),
+	}
+	if err := pvcontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PersistentVolume")
+		return err
+	}
+
 	pvccontroller := &controllers.PersistentVolumeClaimReconciler{
 		Client:    mgr.GetClient(),
 		APIReader: mgr.GetAPIReader(),
@@ -96,6 +109,22 @@ func subMain() error {
 		return err
 	}
 
+	importcontroller := &controllers.ImportedVolumeReconciler{
+		Client: mgr.GetClient(),
+	}
+	if err := importcontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ImportedVolume")
+		return err
+	}
+
+	quotacontroller := &controllers.StorageQuotaReconciler{
+		Client: mgr.GetClient(),
+	}
+	if err := quotacontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StorageQuota")
+		return err
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	// Add health checker to manager
@@ -117,11 +146,18 @@ func subMain() error {
 	if err != nil {
 		return err
 	}
-	n := k8s.NewNodeService(mgr)
+	var calc *reservation.Calculator
+	if config.reservationTimeout > 0 {
+		calc, err = reservation.NewCalculator(context.Background(), mgr.GetClient(), mgr.GetCache(), config.reservationTimeout)
+		if err != nil {
+			return err
+		}
+	}
+	n := k8s.NewNodeService(mgr, calc)
 
 	grpcServer := grpc.NewServer()
 	csi.RegisterIdentityServer(grpcServer, driver.NewIdentityService(checker.Ready))
-	csi.RegisterControllerServer(grpcServer, driver.NewControllerService(s, n))
+	csi.RegisterControllerServer(grpcServer, driver.NewControllerService(s, n, policy.NewChecker(mgr.GetClient())))
 
 	// gRPC service itself should run even when the manager is *not* a leader
 	// because CSI sidecar containers choose a leader.
@@ -17,6 +17,12 @@ var config struct {
 	lvmdSocket  string
 	metricsAddr string
 	zapOpts     zap.Options
+
+	lvmdAddress       string
+	lvmdTLSCertFile   string
+	lvmdTLSKeyFile    string
+	lvmdTLSCAFile     string
+	lvmdTLSServerName string
 }
 
 var rootCmd = &cobra.Command{
@@ -48,6 +54,11 @@ func init() {
 	fs := rootCmd.Flags()
 	fs.StringVar(&config.csiSocket, "csi-socket", topolvm.DefaultCSISocket, "UNIX domain socket filename for CSI")
 	fs.StringVar(&config.lvmdSocket, "lvmd-socket", topolvm.DefaultLVMdSocket, "UNIX domain socket of lvmd service")
+	fs.StringVar(&config.lvmdAddress, "lvmd-address", "", "TCP address of lvmd service; if set, lvmd is connected with mutual TLS instead of lvmd-socket")
+	fs.StringVar(&config.lvmdTLSCertFile, "lvmd-tls-cert-file", "", "Client certificate file for lvmd-address")
+	fs.StringVar(&config.lvmdTLSKeyFile, "lvmd-tls-key-file", "", "Client private key file for lvmd-address")
+	fs.StringVar(&config.lvmdTLSCAFile, "lvmd-tls-ca-file", "", "CA certificate file to verify lvmd")
+	fs.StringVar(&config.lvmdTLSServerName, "lvmd-tls-server-name", "", "Server name to verify the certificate of lvmd, the host of lvmd-address if empty")
 	fs.StringVar(&config.metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	fs.String("nodename", "", "The resource name of the running node")
 
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"time"
@@ -15,8 +16,10 @@ import (
 	"github.com/topolvm/topolvm/driver"
 	"github.com/topolvm/topolvm/driver/k8s"
 	"github.com/topolvm/topolvm/lvmd/proto"
+	"github.com/topolvm/topolvm/lvmd/tlsconfig"
 	"github.com/topolvm/topolvm/runners"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,6 +28,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	//+kubebuilder:scaffold:imports
 )
@@ -59,26 +63,38 @@ func subMain() error {
 		return err
 	}
 
-	dialer := &net.Dialer{}
-	dialFunc := func(ctx context.Context, a string) (net.Conn, error) {
-		return dialer.DialContext(ctx, "unix", a)
-	}
-	conn, err := grpc.Dial(config.lvmdSocket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialFunc))
+	conn, err := dialLVMd()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
+	// Changes of LVs in lvmd trigger the reconciliation of their LogicalVolumes.
+	lvEvents := make(chan event.GenericEvent)
+	if err := mgr.Add(runners.NewLVEventWatcher(conn, mgr, nodename, lvEvents)); err != nil {
+		return err
+	}
 	lvcontroller := controllers.NewLogicalVolumeReconciler(
 		mgr.GetClient(),
 		nodename,
 		conn,
+		lvEvents,
 	)
 
 	if err := lvcontroller.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LogicalVolume")
 		return err
 	}
+
+	evictioncontroller := controllers.NewPhysicalVolumeEvictionReconciler(
+		mgr.GetClient(),
+		nodename,
+		conn,
+	)
+	if err := evictioncontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PhysicalVolumeEviction")
+		return err
+	}
 	//+kubebuilder:scaffold:builder
 
 	// Add health checker to manager
@@ -94,6 +110,11 @@ func subMain() error {
 		return err
 	}
 
+	// Add recovery of LogicalVolumes for a re-registered node to manager.
+	if err := mgr.Add(runners.NewNodeRecovery(conn, mgr, nodename)); err != nil {
+		return err
+	}
+
 	// Add gRPC server to manager.
 	s, err := k8s.NewLogicalVolumeService(mgr)
 	if err != nil {
@@ -119,7 +140,26 @@ func subMain() error {
 	return nil
 }
 
+// dialLVMd connects to lvmd over TCP with mutual TLS if lvmd-address is given, or over the UNIX domain socket.
+func dialLVMd() (*grpc.ClientConn, error) {
+	if config.lvmdAddress != "" {
+		tlsConfig, err := tlsconfig.Client(config.lvmdTLSCertFile, config.lvmdTLSKeyFile, config.lvmdTLSCAFile, config.lvmdTLSServerName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS settings for lvmd-address: %w", err)
+		}
+		return grpc.Dial(config.lvmdAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	dialer := &net.Dialer{}
+	dialFunc := func(ctx context.Context, a string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", a)
+	}
+	return grpc.Dial(config.lvmdSocket, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialFunc))
+}
+
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.cybozu.com,resources=logicalvolumes,verbs=create
 
 func checkFunc(conn *grpc.ClientConn, r client.Reader) func() error {
 	vgs := proto.NewVGServiceClient(conn)
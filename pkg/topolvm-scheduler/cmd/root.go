@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -8,7 +10,17 @@ import (
 	"github.com/cybozu-go/well"
 	"github.com/spf13/cobra"
 	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/reservation"
 	"github.com/topolvm/topolvm/scheduler"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
 
@@ -25,6 +37,15 @@ type Config struct {
 	Divisors map[string]float64 `json:"divisors"`
 	// DefaultDivisor is the default divisor value.
 	DefaultDivisor float64 `json:"default-divisor"`
+	// Strategies is a mapping between device-class names and their scoring strategies.
+	Strategies map[string]scheduler.ScoringStrategy `json:"strategies"`
+	// DefaultStrategy is the default scoring strategy.
+	DefaultStrategy scheduler.ScoringStrategy `json:"default-strategy"`
+	// ReservationTimeout is how long capacity is reserved for scheduled volumes.
+	// Zero disables the reservation.
+	ReservationTimeout metav1.Duration `json:"reservation-timeout"`
+	// EnablePreemption enables the preemption verb to check the capacity freed by victims.
+	EnablePreemption bool `json:"enable-preemption"`
 }
 
 var config = &Config{
@@ -51,6 +72,11 @@ It scores nodes with this formula:
     min(10, max(0, log2(capacity >> 30 / divisor)))
 
 The default divisor is 1.  It can be changed with a command-line option.
+The scoring strategy can be changed to "binpack", which subtracts the above
+score from 10, or to "custom", which uses a piecewise linear function.
+
+The preemption verb is served at "/preemption" via HTTP when it is enabled.
+It keeps only nodes where evicting the victims frees enough storage capacity.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
@@ -75,7 +101,30 @@ func subMain() error {
 		}
 	}
 
-	h, err := scheduler.NewHandler(config.DefaultDivisor, config.Divisors)
+	opts := scheduler.HandlerOptions{
+		DefaultDivisor:  config.DefaultDivisor,
+		Divisors:        config.Divisors,
+		DefaultStrategy: config.DefaultStrategy,
+		Strategies:      config.Strategies,
+	}
+	if config.ReservationTimeout.Duration > 0 || config.EnablePreemption {
+		c, err := newCache()
+		if err != nil {
+			return err
+		}
+		if config.ReservationTimeout.Duration > 0 {
+			opts.Reservation, err = reservation.NewCalculator(context.Background(), c, c, config.ReservationTimeout.Duration)
+			if err != nil {
+				return err
+			}
+		}
+		if config.EnablePreemption {
+			opts.Reader = c
+		}
+		opts.NodeLister = c
+	}
+
+	h, err := scheduler.NewHandler(opts)
 	if err != nil {
 		return err
 	}
@@ -100,6 +149,44 @@ func subMain() error {
 	return nil
 }
 
+// newCache starts the cache of objects to calculate reserved capacity, to process preemption,
+// and to list nodes for the explain endpoint.
+func newCache() (cache.Cache, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := topolvmv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	c, err := cache.New(cfg, cache.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	for _, obj := range []client.Object{
+		&corev1.Pod{}, &corev1.PersistentVolumeClaim{}, &topolvmv1.LogicalVolume{},
+		&corev1.Node{}, &storagev1.StorageClass{},
+	} {
+		if _, err := c.GetInformer(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+	well.Go(func(ctx context.Context) error {
+		return c.Start(ctx)
+	})
+	if !c.WaitForCacheSync(ctx) {
+		return nil, errors.New("failed to sync cache")
+	}
+	return c, nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"context"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Checker checks whether namespaces are allowed to use device-classes according to DeviceClassPolicies.
+//
+// A device-class without any DeviceClassPolicy can be used by any namespace.
+// A device-class with DeviceClassPolicies can be used only by namespaces allowed by any of them.
+type Checker struct {
+	reader client.Reader
+}
+
+// NewChecker returns Checker.
+func NewChecker(reader client.Reader) *Checker {
+	return &Checker{reader: reader}
+}
+
+// Allowed returns true if namespace is allowed to use deviceClass.
+// The default device-class is specified by topolvm.DefaultDeviceClassAnnotationName.
+func (c *Checker) Allowed(ctx context.Context, namespace, deviceClass string) (bool, error) {
+	var policies topolvmv1.DeviceClassPolicyList
+	if err := c.reader.List(ctx, &policies); err != nil {
+		return false, err
+	}
+
+	var ns *corev1.Namespace
+	restricted := false
+	for _, p := range policies.Items {
+		if p.Spec.DeviceClass != deviceClass {
+			continue
+		}
+		restricted = true
+
+		for _, n := range p.Spec.Namespaces {
+			if n == namespace {
+				return true, nil
+			}
+		}
+		if p.Spec.NamespaceSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+		if err != nil {
+			return false, err
+		}
+		if ns == nil {
+			ns = &corev1.Namespace{}
+			if err := c.reader.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+				return false, err
+			}
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			return true, nil
+		}
+	}
+	return !restricted, nil
+}
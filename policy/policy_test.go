@@ -0,0 +1,74 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+	}
+}
+
+func testPolicy(name, dc string, namespaces []string, selector *metav1.LabelSelector) *topolvmv1.DeviceClassPolicy {
+	return &topolvmv1.DeviceClassPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: topolvmv1.DeviceClassPolicySpec{
+			DeviceClass:       dc,
+			Namespaces:        namespaces,
+			NamespaceSelector: selector,
+		},
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := topolvmv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		testNamespace("gpu-team", map[string]string{"team": "gpu"}),
+		testNamespace("gpu-team2", map[string]string{"team": "gpu"}),
+		testNamespace("web-team", map[string]string{"team": "web"}),
+		testNamespace("admin", nil),
+		testPolicy("gpu-by-label", "gpu", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"team": "gpu"}}),
+		testPolicy("gpu-by-name", "gpu", []string{"admin"}, nil),
+		testPolicy("nvme-all", "nvme", nil, &metav1.LabelSelector{}),
+		testPolicy("hdd-none", "hdd", nil, nil),
+	).Build()
+	checker := NewChecker(c)
+
+	testCases := []struct {
+		namespace   string
+		deviceClass string
+		allowed     bool
+	}{
+		{"gpu-team", "gpu", true},
+		{"gpu-team2", "gpu", true},
+		{"admin", "gpu", true},
+		{"web-team", "gpu", false},
+		{"web-team", "nvme", true},
+		{"web-team", "hdd", false},
+		{"web-team", "ssd", true},
+	}
+	for _, tc := range testCases {
+		allowed, err := checker.Allowed(context.Background(), tc.namespace, tc.deviceClass)
+		if err != nil {
+			t.Fatal(tc.namespace, tc.deviceClass, err)
+		}
+		if allowed != tc.allowed {
+			t.Errorf("%s/%s: expected %v, but actual %v", tc.namespace, tc.deviceClass, tc.allowed, allowed)
+		}
+	}
+}
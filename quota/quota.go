@@ -0,0 +1,150 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Checker calculates the usage of TopoLVM volumes in namespaces and checks it against StorageQuotas.
+//
+// The size of a PVC is the size of its LogicalVolume if it is bound,
+// or the requested size rounded up to GiB unit otherwise.
+// If the PVC is being expanded, the larger one is used.
+type Checker struct {
+	reader client.Reader
+}
+
+// NewChecker returns Checker.
+func NewChecker(reader client.Reader) *Checker {
+	return &Checker{reader: reader}
+}
+
+// ClaimCapacity returns the device-class and the size requested by pvc.
+// The default device-class is keyed by topolvm.DefaultDeviceClassAnnotationName.
+// It returns false if pvc is not for TopoLVM.
+func (c *Checker) ClaimCapacity(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (string, int64, bool, error) {
+	if pvc.Spec.StorageClassName == nil {
+		return "", 0, false, nil
+	}
+	var sc storagev1.StorageClass
+	err := c.reader.Get(ctx, client.ObjectKey{Name: *pvc.Spec.StorageClassName}, &sc)
+	if apierrors.IsNotFound(err) {
+		return "", 0, false, nil
+	}
+	if err != nil {
+		return "", 0, false, err
+	}
+	if sc.Provisioner != topolvm.PluginName {
+		return "", 0, false, nil
+	}
+
+	dc, ok := sc.Parameters[topolvm.DeviceClassKey]
+	if !ok {
+		dc = topolvm.DefaultDeviceClassAnnotationName
+	}
+	return dc, requestedSize(pvc), true, nil
+}
+
+// Usage returns the total size of TopoLVM volumes in namespace per device-class.
+// The PVC named exclude is not counted.
+func (c *Checker) Usage(ctx context.Context, namespace, exclude string) (map[string]int64, error) {
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := c.reader.List(ctx, &pvcs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64)
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if pvc.Name == exclude || pvc.DeletionTimestamp != nil {
+			continue
+		}
+		dc, size, ok, err := c.ClaimCapacity(ctx, pvc)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		if pvc.Spec.VolumeName != "" {
+			var lv topolvmv1.LogicalVolume
+			err := c.reader.Get(ctx, client.ObjectKey{Name: pvc.Spec.VolumeName}, &lv)
+			switch {
+			case err == nil:
+				if lv.Spec.Size.Value() > size {
+					size = lv.Spec.Size.Value()
+				}
+			case apierrors.IsNotFound(err):
+			default:
+				return nil, err
+			}
+		}
+		result[dc] += size
+	}
+	return result, nil
+}
+
+// Check returns an error message if pvc makes the usage of its namespace exceed any StorageQuota.
+// It returns an empty string if pvc is allowed.
+func (c *Checker) Check(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (string, error) {
+	dc, size, ok, err := c.ClaimCapacity(ctx, pvc)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", nil
+	}
+
+	var quotas topolvmv1.StorageQuotaList
+	if err := c.reader.List(ctx, &quotas, client.InNamespace(pvc.Namespace)); err != nil {
+		return "", err
+	}
+	limited := false
+	for _, q := range quotas.Items {
+		if _, ok := q.Spec.Hard[dc]; ok {
+			limited = true
+			break
+		}
+	}
+	if !limited {
+		return "", nil
+	}
+
+	usage, err := c.Usage(ctx, pvc.Namespace, pvc.Name)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(quotas.Items, func(i, j int) bool { return quotas.Items[i].Name < quotas.Items[j].Name })
+	for _, q := range quotas.Items {
+		hard, ok := q.Spec.Hard[dc]
+		if !ok {
+			continue
+		}
+		if usage[dc]+size > hard.Value() {
+			return fmt.Sprintf("exceeded quota %s for device-class %s: requested=%d, used=%d, limited=%d",
+				q.Name, dc, size, usage[dc], hard.Value()), nil
+		}
+	}
+	return "", nil
+}
+
+// requestedSize returns the requested size of pvc rounded up to GiB unit.
+// As TopoLVM provisions 1 GiB for a PVC without request, it is also treated as 1 GiB.
+func requestedSize(pvc *corev1.PersistentVolumeClaim) int64 {
+	var size int64 = topolvm.DefaultSize
+	if req, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		if req.Value() > topolvm.DefaultSize {
+			size = ((req.Value()-1)>>30 + 1) << 30
+		}
+	}
+	return size
+}
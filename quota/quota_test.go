@@ -0,0 +1,135 @@
+package quota
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testClaim(namespace, name, sc string, size int64, volumeName string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &sc,
+			VolumeName:       volumeName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI),
+				},
+			},
+		},
+	}
+}
+
+func testStorageClass(name, provisioner, dc string) *storagev1.StorageClass {
+	sc := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: name},
+		Provisioner: provisioner,
+	}
+	if dc != "" {
+		sc.Parameters = map[string]string{topolvm.DeviceClassKey: dc}
+	}
+	return sc
+}
+
+func testClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := topolvmv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestUsage(t *testing.T) {
+	c := testClient(t,
+		testStorageClass("ssd", topolvm.PluginName, "ssd"),
+		testStorageClass("default", topolvm.PluginName, ""),
+		testStorageClass("other", "other.example.com", "ssd"),
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv1"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Size: *resource.NewQuantity(5<<30, resource.BinarySI)},
+		},
+		&topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv2"},
+			Spec:       topolvmv1.LogicalVolumeSpec{Size: *resource.NewQuantity(1<<30, resource.BinarySI)},
+		},
+		// bound to a LogicalVolume larger than the request
+		testClaim("ns1", "bound", "ssd", 1<<30, "pv1"),
+		// being expanded
+		testClaim("ns1", "expanding", "ssd", 3<<30, "pv2"),
+		// rounded up to GiB
+		testClaim("ns1", "pending", "default", 1<<20, ""),
+		testClaim("ns1", "other", "other", 100<<30, ""),
+		testClaim("ns1", "excluded", "ssd", 100<<30, ""),
+		testClaim("ns2", "pending", "ssd", 100<<30, ""),
+	)
+	checker := NewChecker(c)
+
+	usage, err := checker.Usage(context.Background(), "ns1", "excluded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int64{
+		"ssd":                                    8 << 30,
+		topolvm.DefaultDeviceClassAnnotationName: 1 << 30,
+	}
+	if !reflect.DeepEqual(usage, expected) {
+		t.Errorf("expected %v, but actual %v", expected, usage)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	c := testClient(t,
+		testStorageClass("ssd", topolvm.PluginName, "ssd"),
+		testStorageClass("hdd", topolvm.PluginName, "hdd"),
+		testStorageClass("other", "other.example.com", "ssd"),
+		&topolvmv1.StorageQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "ns1"},
+			Spec: topolvmv1.StorageQuotaSpec{
+				Hard: map[string]resource.Quantity{"ssd": *resource.NewQuantity(10<<30, resource.BinarySI)},
+			},
+		},
+		testClaim("ns1", "existing", "ssd", 6<<30, ""),
+	)
+	checker := NewChecker(c)
+
+	testCases := []struct {
+		name    string
+		pvc     *corev1.PersistentVolumeClaim
+		allowed bool
+	}{
+		{"within quota", testClaim("ns1", "new", "ssd", 4<<30, ""), true},
+		{"exceeded", testClaim("ns1", "new", "ssd", 5<<30, ""), false},
+		{"expanding existing", testClaim("ns1", "existing", "ssd", 10<<30, ""), true},
+		{"expanding existing over quota", testClaim("ns1", "existing", "ssd", 11<<30, ""), false},
+		{"unlimited device-class", testClaim("ns1", "new", "hdd", 100<<30, ""), true},
+		{"other provisioner", testClaim("ns1", "new", "other", 100<<30, ""), true},
+		{"other namespace", testClaim("ns2", "new", "ssd", 100<<30, ""), true},
+	}
+	for _, tc := range testCases {
+		msg, err := checker.Check(context.Background(), tc.pvc)
+		if err != nil {
+			t.Fatal(tc.name, err)
+		}
+		if tc.allowed != (msg == "") {
+			t.Errorf("%s: unexpected result: %q", tc.name, msg)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package reservation
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Calculator calculates the capacity reserved on nodes for volumes that have been scheduled
+// but are not yet reflected in the capacity annotations of the nodes.
+//
+// The capacity is reserved for:
+//   - LogicalVolumes whose LV is not reflected in the capacity annotation of the node yet, and
+//   - Pods bound to nodes that still have PVCs waiting for provisioning.
+//
+// A LogicalVolume is reflected once the node records a change of the capacity annotations
+// in topolvm.CapacityUpdatedAtKey after the LogicalVolume was created.  The creation timestamp
+// is truncated to seconds, so only a change after the end of the creation second is taken.
+// A Pod does not reserve the capacity of its PVCs that already have LogicalVolumes,
+// so that no volume is counted twice.
+//
+// Each reservation expires after the timeout so that a stuck volume does not hold capacity forever.
+//
+// The result is cached until LogicalVolumes, Pods, PVCs or the capacity of Nodes are changed,
+// or until a reservation in it expires.
+type Calculator struct {
+	reader  client.Reader
+	timeout time.Duration
+
+	mu         sync.Mutex
+	generation uint64
+	cached     *cachedReservation
+}
+
+// cachedReservation is the result of Reserved valid from computedAt until expiresAt.
+type cachedReservation struct {
+	generation uint64
+	computedAt time.Time
+	// expiresAt is the zero time if no reservation will expire.
+	expiresAt time.Time
+	result    map[string]map[string]int64
+}
+
+// NewCalculator returns Calculator.
+// reader should read from the cache of informers so that changes are notified to Calculator.
+func NewCalculator(ctx context.Context, reader client.Reader, informers cache.Informers, timeout time.Duration) (*Calculator, error) {
+	c := &Calculator{
+		reader:  reader,
+		timeout: timeout,
+	}
+
+	handlers := map[client.Object]toolscache.ResourceEventHandler{
+		&topolvmv1.LogicalVolume{}:      c.invalidateFuncs(nil),
+		&corev1.PersistentVolumeClaim{}: c.invalidateFuncs(nil),
+		// Pods without capacity annotations never reserve capacity.
+		&corev1.Pod{}: c.invalidateFuncs(func(oldObj, newObj interface{}) bool {
+			oldPod, ok1 := oldObj.(*corev1.Pod)
+			newPod, ok2 := newObj.(*corev1.Pod)
+			return !ok1 || !ok2 || len(requestedCapacity(oldPod)) > 0 || len(requestedCapacity(newPod)) > 0
+		}),
+		// Nodes are updated frequently by kubelet, but only the capacity annotations matter.
+		&corev1.Node{}: c.invalidateFuncs(func(oldObj, newObj interface{}) bool {
+			oldNode, ok1 := oldObj.(*corev1.Node)
+			newNode, ok2 := newObj.(*corev1.Node)
+			return !ok1 || !ok2 ||
+				oldNode.Annotations[topolvm.CapacityUpdatedAtKey] != newNode.Annotations[topolvm.CapacityUpdatedAtKey]
+		}),
+	}
+	for obj, handler := range handlers {
+		informer, err := informers.GetInformer(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		informer.AddEventHandler(handler)
+	}
+	return c, nil
+}
+
+// invalidateFuncs returns the event handler to invalidate the cached result.
+// If updated is not nil, it decides whether an update of an object invalidates the result.
+func (c *Calculator) invalidateFuncs(updated func(oldObj, newObj interface{}) bool) toolscache.ResourceEventHandler {
+	invalidate := func() {
+		c.mu.Lock()
+		c.generation++
+		c.mu.Unlock()
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { invalidate() },
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if updated == nil || updated(oldObj, newObj) {
+				invalidate()
+			}
+		},
+		DeleteFunc: func(interface{}) { invalidate() },
+	}
+}
+
+// Reserved returns the reserved capacity in bytes keyed by node names and device-classes.
+// The default device-class is keyed by topolvm.DefaultDeviceClassAnnotationName as in the capacity annotations.
+// The returned map must not be modified.
+func (c *Calculator) Reserved(ctx context.Context, now time.Time) (map[string]map[string]int64, error) {
+	c.mu.Lock()
+	generation := c.generation
+	cached := c.cached
+	c.mu.Unlock()
+	if cached != nil && cached.generation == generation && !now.Before(cached.computedAt) &&
+		(cached.expiresAt.IsZero() || now.Before(cached.expiresAt)) {
+		return cached.result, nil
+	}
+
+	result, expiresAt, err := c.calculate(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// changes during the calculation leave the generation different, so the result will be recalculated.
+	c.mu.Lock()
+	c.cached = &cachedReservation{
+		generation: generation,
+		computedAt: now,
+		expiresAt:  expiresAt,
+		result:     result,
+	}
+	c.mu.Unlock()
+	return result, nil
+}
+
+// calculate returns the reserved capacity and when the first reservation in it expires.
+func (c *Calculator) calculate(ctx context.Context, now time.Time) (map[string]map[string]int64, time.Time, error) {
+	result := make(map[string]map[string]int64)
+	var expiresAt time.Time
+	add := func(node, dc string, size int64, since time.Time) {
+		if result[node] == nil {
+			result[node] = make(map[string]int64)
+		}
+		result[node][dc] += size
+		if t := since.Add(c.timeout); expiresAt.IsZero() || t.Before(expiresAt) {
+			expiresAt = t
+		}
+	}
+
+	var lvList topolvmv1.LogicalVolumeList
+	if err := c.reader.List(ctx, &lvList); err != nil {
+		return nil, time.Time{}, err
+	}
+	lvByName := make(map[string]*topolvmv1.LogicalVolume)
+	updatedAt := make(map[string]time.Time)
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		lvByName[lv.Name] = lv
+		if !isInFlightLogicalVolume(lv, now, c.timeout) {
+			continue
+		}
+		if lv.Status.VolumeID != "" {
+			t, ok := updatedAt[lv.Spec.NodeName]
+			if !ok {
+				var err error
+				t, err = c.capacityUpdatedAt(ctx, lv.Spec.NodeName)
+				if err != nil {
+					return nil, time.Time{}, err
+				}
+				updatedAt[lv.Spec.NodeName] = t
+			}
+			if !t.Before(lv.CreationTimestamp.Add(time.Second)) {
+				continue
+			}
+		}
+		add(lv.Spec.NodeName, annotationName(lv.Spec.DeviceClass), lv.Spec.Size.Value(), lv.CreationTimestamp.Time)
+	}
+
+	var podList corev1.PodList
+	if err := c.reader.List(ctx, &podList); err != nil {
+		return nil, time.Time{}, err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		requested := requestedCapacity(pod)
+		if len(requested) == 0 || !isRecentlyScheduled(pod, now, c.timeout) {
+			continue
+		}
+		pending, err := c.subtractProvisionedClaims(ctx, pod, requested, lvByName)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if !pending {
+			continue
+		}
+		for dc, size := range requested {
+			if size > 0 {
+				add(pod.Spec.NodeName, dc, size, scheduledAt(pod))
+			}
+		}
+	}
+
+	return result, expiresAt, nil
+}
+
+// capacityUpdatedAt returns when the capacity annotations of the node were last changed.
+// It returns the zero time if unknown.
+func (c *Calculator) capacityUpdatedAt(ctx context.Context, nodeName string) (time.Time, error) {
+	var node corev1.Node
+	err := c.reader.Get(ctx, client.ObjectKey{Name: nodeName}, &node)
+	if apierrors.IsNotFound(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, node.Annotations[topolvm.CapacityUpdatedAtKey])
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return t, nil
+}
+
+// subtractProvisionedClaims subtracts the size of the LogicalVolumes of pod's PVCs from requested
+// because they are reserved or used by themselves.
+// It returns true if pod has a PVC waiting for provisioning.
+func (c *Calculator) subtractProvisionedClaims(ctx context.Context, pod *corev1.Pod, requested map[string]int64, lvByName map[string]*topolvmv1.LogicalVolume) (bool, error) {
+	var pending bool
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil {
+			continue
+		}
+		var pvc corev1.PersistentVolumeClaim
+		err := c.reader.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: vol.PersistentVolumeClaim.ClaimName}, &pvc)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if pvc.Status.Phase == corev1.ClaimPending {
+			pending = true
+		}
+
+		// The external-provisioner names volumes "pvc-<UID of the PVC>".
+		volumeName := pvc.Spec.VolumeName
+		if volumeName == "" {
+			volumeName = "pvc-" + string(pvc.UID)
+		}
+		lv, ok := lvByName[volumeName]
+		if !ok {
+			continue
+		}
+		requested[annotationName(lv.Spec.DeviceClass)] -= lv.Spec.Size.Value()
+	}
+	return pending, nil
+}
+
+// isInFlightLogicalVolume returns true if lv was created within timeout and has not failed.
+func isInFlightLogicalVolume(lv *topolvmv1.LogicalVolume, now time.Time, timeout time.Duration) bool {
+	if lv.DeletionTimestamp != nil || lv.Status.Code != codes.OK {
+		return false
+	}
+	return now.Sub(lv.CreationTimestamp.Time) < timeout
+}
+
+// isRecentlyScheduled returns true if pod was bound to a node within timeout and is not running yet.
+func isRecentlyScheduled(pod *corev1.Pod, now time.Time, timeout time.Duration) bool {
+	if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodPending {
+		return false
+	}
+	return now.Sub(scheduledAt(pod)) < timeout
+}
+
+// scheduledAt returns when pod was bound to a node.
+func scheduledAt(pod *corev1.Pod) time.Time {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionTrue {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// requestedCapacity returns the capacity requested by pod in the annotations added by topolvm-hook.
+func requestedCapacity(pod *corev1.Pod) map[string]int64 {
+	result := make(map[string]int64)
+	for k, v := range pod.Annotations {
+		if !strings.HasPrefix(k, topolvm.CapacityKeyPrefix) {
+			continue
+		}
+		capacity, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		result[k[len(topolvm.CapacityKeyPrefix):]] = capacity
+	}
+	return result
+}
+
+func annotationName(deviceClass string) string {
+	if deviceClass == topolvm.DefaultDeviceClassName {
+		return topolvm.DefaultDeviceClassAnnotationName
+	}
+	return deviceClass
+}
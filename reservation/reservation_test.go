@@ -0,0 +1,237 @@
+package reservation
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testLogicalVolume(name, node, dc string, sizeGb int64, created time.Time) *topolvmv1.LogicalVolume {
+	return &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name:        name,
+			NodeName:    node,
+			DeviceClass: dc,
+			Size:        *resource.NewQuantity(sizeGb<<30, resource.BinarySI),
+		},
+	}
+}
+
+func testPod(name, node, claim string, phase corev1.PodPhase, sizeGb int64, scheduled time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Annotations: map[string]string{
+				topolvm.CapacityKeyPrefix + "ssd": strconv.FormatInt(sizeGb<<30, 10),
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Volumes: []corev1.Volume{{
+				Name: "data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodScheduled,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(scheduled),
+			}},
+		},
+	}
+}
+
+func testClaim(name string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func testNode(name string, updatedAt time.Time) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				topolvm.CapacityUpdatedAtKey: updatedAt.UTC().Format(time.RFC3339Nano),
+			},
+		},
+	}
+}
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := topolvmv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func TestReserved(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	timeout := 5 * time.Minute
+
+	// node1 has updated the capacity after the LV was created.
+	created := testLogicalVolume("created", "node1", "", 10, now)
+	created.Status.VolumeID = "uid"
+	// node2 has not updated the capacity yet.
+	unreflected := testLogicalVolume("unreflected", "node2", "", 5, now)
+	unreflected.Status.VolumeID = "uid2"
+	// the LV of a PVC of pod6 is being created.
+	provisioning := testClaim("provisioning", corev1.ClaimPending)
+	provisioning.UID = "uid6"
+	failed := testLogicalVolume("failed", "node1", "", 10, now)
+	failed.Status.Code = codes.ResourceExhausted
+	// node3 has updated the capacity in the creation second of the LV, maybe before the LV was created.
+	sameSecond := testLogicalVolume("same-second", "node3", "", 7, now)
+	sameSecond.Status.VolumeID = "uid3"
+
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		testLogicalVolume("inflight1", "node1", "", 1, now.Add(-time.Minute)),
+		testLogicalVolume("inflight2", "node1", "ssd", 2, now),
+		testLogicalVolume("inflight3", "node2", "", 3, now),
+		testLogicalVolume("expired", "node1", "", 10, now.Add(-10*time.Minute)),
+		created,
+		unreflected,
+		failed,
+		testLogicalVolume("pvc-uid6", "node2", "ssd", 6, now),
+		testNode("node1", now.Add(time.Second)),
+		testNode("node2", now.Add(-time.Minute)),
+		sameSecond,
+		testNode("node3", now.Add(300*time.Millisecond)),
+		testClaim("pending", corev1.ClaimPending),
+		provisioning,
+		testClaim("bound", corev1.ClaimBound),
+		testPod("pod1", "node2", "pending", corev1.PodPending, 4, now),
+		testPod("pod2", "node2", "bound", corev1.PodPending, 10, now),
+		testPod("pod3", "", "pending", corev1.PodPending, 10, now),
+		testPod("pod4", "node2", "pending", corev1.PodRunning, 10, now),
+		testPod("pod5", "node2", "pending", corev1.PodPending, 10, now.Add(-10*time.Minute)),
+		testPod("pod6", "node2", "provisioning", corev1.PodPending, 6, now),
+	).Build()
+
+	calc, err := NewCalculator(context.Background(), c, &informertest.FakeInformers{Scheme: scheme}, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reserved, err := calc.Reserved(context.Background(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]map[string]int64{
+		"node1": {
+			topolvm.DefaultDeviceClassAnnotationName: 1 << 30,
+			"ssd":                                    2 << 30,
+		},
+		"node2": {
+			topolvm.DefaultDeviceClassAnnotationName: 8 << 30,
+			"ssd":                                    10 << 30,
+		},
+		"node3": {
+			topolvm.DefaultDeviceClassAnnotationName: 7 << 30,
+		},
+	}
+	if !reflect.DeepEqual(reserved, expected) {
+		t.Errorf("expected %v, but actual %v", expected, reserved)
+	}
+}
+
+func TestReservedCache(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	timeout := 5 * time.Minute
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		testLogicalVolume("lv1", "node1", "", 1, now.Add(-time.Minute)),
+	).Build()
+	informers := &informertest.FakeInformers{Scheme: scheme}
+	calc, err := NewCalculator(context.Background(), c, informers, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reservedOf := func(at time.Time) int64 {
+		t.Helper()
+		reserved, err := calc.Reserved(context.Background(), at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return reserved["node1"][topolvm.DefaultDeviceClassAnnotationName]
+	}
+	create := func(obj client.Object) {
+		t.Helper()
+		if err := c.Create(context.Background(), obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if r := reservedOf(now); r != 1<<30 {
+		t.Fatalf("expected %d, but actual %d", int64(1<<30), r)
+	}
+
+	lv2 := testLogicalVolume("lv2", "node1", "", 2, now)
+	create(lv2)
+	if r := reservedOf(now); r != 1<<30 {
+		t.Errorf("the cached result should be returned without events: expected %d, but actual %d", int64(1<<30), r)
+	}
+
+	lvInformer, err := informers.FakeInformerForKind(context.Background(), topolvmv1.GroupVersion.WithKind("LogicalVolume"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lvInformer.Add(lv2)
+	if r := reservedOf(now); r != 3<<30 {
+		t.Errorf("the result should be recalculated after an event: expected %d, but actual %d", int64(3<<30), r)
+	}
+
+	// Node updates without changes of the capacity do not invalidate the result.
+	create(testLogicalVolume("lv3", "node1", "", 4, now))
+	nodeInformer, err := informers.FakeInformerForKind(context.Background(), corev1.SchemeGroupVersion.WithKind("Node"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := testNode("node1", now.Add(-time.Hour))
+	nodeInformer.Update(node, node.DeepCopy())
+	if r := reservedOf(now); r != 3<<30 {
+		t.Errorf("the cached result should be returned after a heartbeat: expected %d, but actual %d", int64(3<<30), r)
+	}
+
+	// lv1 expires first.
+	if r := reservedOf(now.Add(4*time.Minute - time.Second)); r != 3<<30 {
+		t.Errorf("expected %d, but actual %d", int64(3<<30), r)
+	}
+	if r := reservedOf(now.Add(4 * time.Minute)); r != 6<<30 {
+		t.Errorf("the result should be recalculated after a reservation expires: expected %d, but actual %d", int64(6<<30), r)
+	}
+}
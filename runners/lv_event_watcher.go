@@ -0,0 +1,123 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/controllers"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// lvEventRetryInterval is the interval to re-establish the watch of LV events.
+const lvEventRetryInterval = 5 * time.Second
+
+var lvewLogger = ctrl.Log.WithName("runners").WithName("lv_event_watcher")
+
+type lvEventWatcher struct {
+	reader    client.Reader
+	nodeName  string
+	vgService proto.VGServiceClient
+	events    chan<- event.GenericEvent
+}
+
+var _ manager.LeaderElectionRunnable = &lvEventWatcher{}
+
+// NewLVEventWatcher creates controller-runtime's manager.Runnable to watch changes of LVs in lvmd.
+// For each change, the LogicalVolumes of the LV on the node are sent to events
+// so that the LogicalVolume controller reconciles them without waiting for resync.
+func NewLVEventWatcher(conn *grpc.ClientConn, mgr manager.Manager, nodeName string, events chan<- event.GenericEvent) manager.Runnable {
+	return &lvEventWatcher{
+		reader:    mgr.GetClient(),
+		nodeName:  nodeName,
+		vgService: proto.NewVGServiceClient(conn),
+		events:    events,
+	}
+}
+
+// Start implements controller-runtime's manager.Runnable.
+func (w *lvEventWatcher) Start(ctx context.Context) error {
+	var token string
+	for {
+		var err error
+		token, err = w.watch(ctx, token)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if status.Code(err) == codes.OutOfRange {
+			// events after the token are lost, so start over from the current LVs.
+			lvewLogger.Info("resume token expired", "token", token)
+			token = ""
+		} else {
+			lvewLogger.Error(err, "failed to watch LV events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(lvEventRetryInterval):
+		}
+	}
+}
+
+// NeedLeaderElection implements controller-runtime's manager.LeaderElectionRunnable.
+func (w *lvEventWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// watch streams LV events after token until an error occurs.
+// It returns the last token received.
+func (w *lvEventWatcher) watch(ctx context.Context, token string) (string, error) {
+	wc, err := w.vgService.Watch(ctx, &proto.WatchRequest{LvEvents: true, ResumeToken: token})
+	if err != nil {
+		return token, err
+	}
+	for {
+		res, err := wc.Recv()
+		if err != nil {
+			return token, err
+		}
+		if len(res.Events) > 0 {
+			if err := w.notify(ctx, res.Events); err != nil {
+				return token, err
+			}
+		}
+		token = res.ResumeToken
+	}
+}
+
+// notify sends GenericEvents for the LogicalVolumes referring to the LVs in events.
+func (w *lvEventWatcher) notify(ctx context.Context, events []*proto.LVEvent) error {
+	changed := make(map[string]bool)
+	for _, ev := range events {
+		// the status of LogicalVolumes does not depend on the usage of pools.
+		if ev.Type == proto.LVEvent_POOL_USAGE_CHANGED {
+			continue
+		}
+		changed[ev.Volume.GetName()] = true
+	}
+
+	var lvList topolvmv1.LogicalVolumeList
+	if err := w.reader.List(ctx, &lvList); err != nil {
+		return err
+	}
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		if lv.Spec.NodeName != w.nodeName || !changed[controllers.VolumeName(lv)] {
+			continue
+		}
+		select {
+		case w.events <- event.GenericEvent{Object: lv}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package runners
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func testLogicalVolume(name, uid, volumeID, nodeName string) *topolvmv1.LogicalVolume {
+	return &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(uid)},
+		Spec:       topolvmv1.LogicalVolumeSpec{NodeName: nodeName},
+		Status:     topolvmv1.LogicalVolumeStatus{VolumeID: volumeID},
+	}
+}
+
+func TestLVEventWatcherNotify(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := topolvmv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	objs := []client.Object{
+		testLogicalVolume("lv1", "uid1", "uid1", "node1"),
+		testLogicalVolume("lv2", "uid2", "", "node1"),
+		testLogicalVolume("lv3", "uid3", "recovered", "node1"),
+		testLogicalVolume("lv4", "uid4", "uid4", "node2"),
+		testLogicalVolume("lv5", "uid5", "uid5", "node1"),
+	}
+	events := make(chan event.GenericEvent, len(objs))
+	w := &lvEventWatcher{
+		reader:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		nodeName: "node1",
+		events:   events,
+	}
+
+	err := w.notify(context.Background(), []*proto.LVEvent{
+		{Type: proto.LVEvent_REMOVED, Volume: &proto.LogicalVolume{Name: "uid1"}},
+		{Type: proto.LVEvent_CREATED, Volume: &proto.LogicalVolume{Name: "uid2"}},
+		{Type: proto.LVEvent_RESIZED, Volume: &proto.LogicalVolume{Name: "recovered"}},
+		{Type: proto.LVEvent_RESIZED, Volume: &proto.LogicalVolume{Name: "uid3"}},
+		{Type: proto.LVEvent_CREATED, Volume: &proto.LogicalVolume{Name: "uid4"}},
+		{Type: proto.LVEvent_POOL_USAGE_CHANGED, Pool: &proto.PoolUsage{UsedBytes: 1 << 30, SizeBytes: 10 << 30}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(events)
+
+	var actual []string
+	for e := range events {
+		actual = append(actual, e.Object.GetName())
+	}
+	sort.Strings(actual)
+	expected := []string{"lv1", "lv2", "lv3"}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v, but actual %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("expected %v, but actual %v", expected, actual)
+		}
+	}
+}
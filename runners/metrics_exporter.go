@@ -3,17 +3,24 @@ package runners
 import (
 	"context"
 	"io"
+	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/topolvm/topolvm"
 	"github.com/topolvm/topolvm/lvmd/proto"
+	"github.com/topolvm/topolvm/lvmd/spare"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -32,10 +39,15 @@ type NodeMetrics struct {
 
 type metricsExporter struct {
 	client         client.Client
+	informers      cache.Informers
 	nodeName       string
 	vgService      proto.VGServiceClient
 	availableBytes *prometheus.GaugeVec
 	sizeBytes      *prometheus.GaugeVec
+	draining       *prometheus.GaugeVec
+
+	// spares are the spare capacity set to lvmd for each device-class.
+	spares map[string]string
 }
 
 var _ manager.LeaderElectionRunnable = &metricsExporter{}
@@ -61,12 +73,24 @@ func NewMetricsExporter(conn *grpc.ClientConn, mgr manager.Manager, nodeName str
 	}, []string{"device_class"})
 	metrics.Registry.MustRegister(sizeBytes)
 
+	draining := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   metricsNamespace,
+		Subsystem:   "volumegroup",
+		Name:        "draining",
+		Help:        "1 if the device-class is draining and does not accept new volumes",
+		ConstLabels: prometheus.Labels{"node": nodeName},
+	}, []string{"device_class"})
+	metrics.Registry.MustRegister(draining)
+
 	return &metricsExporter{
 		client:         mgr.GetClient(),
+		informers:      mgr.GetCache(),
 		nodeName:       nodeName,
 		vgService:      proto.NewVGServiceClient(conn),
 		availableBytes: availableBytes,
 		sizeBytes:      sizeBytes,
+		draining:       draining,
+		spares:         make(map[string]string),
 	}
 }
 
@@ -85,11 +109,31 @@ func (m *metricsExporter) Start(ctx context.Context) error {
 		}
 	}()
 
-	wc, err := m.vgService.Watch(ctx, &proto.Empty{})
+	// The capacity is recalculated as soon as the annotations overriding it are changed.
+	nodeCh := make(chan struct{}, 1)
+	informer, err := m.informers.GetInformer(ctx, &corev1.Node{})
+	if err != nil {
+		return err
+	}
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNode, ok1 := oldObj.(*corev1.Node)
+			newNode, ok2 := newObj.(*corev1.Node)
+			if !ok1 || !ok2 || newNode.Name != m.nodeName || !overrideAnnotationsChanged(oldNode, newNode) {
+				return
+			}
+			select {
+			case nodeCh <- struct{}{}:
+			default:
+			}
+		},
+	})
+
+	wc, err := m.vgService.Watch(ctx, &proto.WatchRequest{})
 	if err != nil {
 		return err
 	}
-	return m.updateNode(ctx, wc, metricsCh)
+	return m.updateNode(ctx, wc, nodeCh, metricsCh)
 }
 
 // NeedLeaderElection implements controller-runtime's manager.LeaderElectionRunnable.
@@ -97,18 +141,54 @@ func (m *metricsExporter) NeedLeaderElection() bool {
 	return false
 }
 
-func (m *metricsExporter) updateNode(ctx context.Context, wc proto.VGService_WatchClient, ch chan<- NodeMetrics) error {
+func (m *metricsExporter) updateNode(ctx context.Context, wc proto.VGService_WatchClient, nodeCh <-chan struct{}, ch chan<- NodeMetrics) error {
+	resCh := make(chan *proto.WatchResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			res, err := wc.Recv()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case resCh <- res:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var last *proto.WatchResponse
 	for {
-		res, err := wc.Recv()
-		switch {
-		case err == io.EOF:
-			return nil
-		case status.Code(err) == codes.Canceled:
-			return nil
-		case err == nil:
-		default:
+		select {
+		case err := <-errCh:
+			switch {
+			case err == io.EOF:
+				return nil
+			case status.Code(err) == codes.Canceled:
+				return nil
+			default:
+				return err
+			}
+		case last = <-resCh:
+		case <-nodeCh:
+			if last == nil {
+				continue
+			}
+		}
+
+		var node corev1.Node
+		if err := m.client.Get(ctx, types.NamespacedName{Name: m.nodeName}, &node); err != nil {
+			return err
+		}
+		// lvmd sends the status again if the spare capacity is changed.
+		if err := m.syncSpares(ctx, &node, last); err != nil {
 			return err
 		}
+		// the last response is kept intact for the changes of the node.
+		res := protobuf.Clone(last).(*proto.WatchResponse)
+		draining := applyDraining(&node, res)
 
 		for _, item := range res.Items {
 			ch <- NodeMetrics{
@@ -118,14 +198,17 @@ func (m *metricsExporter) updateNode(ctx context.Context, wc proto.VGService_Wat
 			}
 		}
 
-		var node corev1.Node
-		if err := m.client.Get(ctx, types.NamespacedName{Name: m.nodeName}, &node); err != nil {
-			return err
-		}
-
 		if node.DeletionTimestamp != nil {
 			meLogger.Info("node is deleting")
-			break
+			return nil
+		}
+
+		for _, item := range res.Items {
+			var value float64
+			if draining[item.DeviceClass] {
+				value = 1
+			}
+			m.draining.WithLabelValues(item.DeviceClass).Set(value)
 		}
 
 		node2 := node.DeepCopy()
@@ -141,14 +224,118 @@ func (m *metricsExporter) updateNode(ctx context.Context, wc proto.VGService_Wat
 			node2.Finalizers = append(node2.Finalizers, topolvm.NodeFinalizer)
 		}
 
-		node2.Annotations[topolvm.CapacityKeyPrefix+topolvm.DefaultDeviceClassAnnotationName] = strconv.FormatUint(res.FreeBytes, 10)
-		for _, item := range res.Items {
-			node2.Annotations[topolvm.CapacityKeyPrefix+item.DeviceClass] = strconv.FormatUint(item.FreeBytes, 10)
-		}
+		setCapacityAnnotations(node2, res, time.Now())
+		setDefaultDrainingAnnotation(node2, res, draining)
 		if err := m.client.Patch(ctx, node2, client.MergeFrom(&node)); err != nil {
 			return err
 		}
 	}
+}
 
+// syncSpares sets the spare capacity in the annotations of node to lvmd
+// for the device-classes in res.  Only changes are sent after the first call.
+func (m *metricsExporter) syncSpares(ctx context.Context, node *corev1.Node, res *proto.WatchResponse) error {
+	for _, item := range res.Items {
+		value := spareAnnotation(node, item.DeviceClass)
+		if sent, ok := m.spares[item.DeviceClass]; ok && sent == value {
+			continue
+		}
+		_, err := m.vgService.SetSpare(ctx, &proto.SetSpareRequest{DeviceClass: item.DeviceClass, Spare: value})
+		if err != nil {
+			return err
+		}
+		m.spares[item.DeviceClass] = value
+	}
 	return nil
 }
+
+// setCapacityAnnotations sets the free bytes in res to the annotations of node.
+// If any of them changes, the time is recorded so that reservations for new volumes can be released.
+func setCapacityAnnotations(node *corev1.Node, res *proto.WatchResponse, now time.Time) {
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	var changed bool
+	set := func(dc string, free uint64) {
+		key := topolvm.CapacityKeyPrefix + dc
+		value := strconv.FormatUint(free, 10)
+		if node.Annotations[key] != value {
+			node.Annotations[key] = value
+			changed = true
+		}
+	}
+	set(topolvm.DefaultDeviceClassAnnotationName, res.FreeBytes)
+	for _, item := range res.Items {
+		set(item.DeviceClass, item.FreeBytes)
+	}
+	if changed {
+		node.Annotations[topolvm.CapacityUpdatedAtKey] = now.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// spareAnnotation returns the spare capacity of the device-class in the annotations of node.
+// It returns an empty string, i.e. the spare capacity configured in lvmd, if the annotation is absent or invalid.
+func spareAnnotation(node *corev1.Node, deviceClass string) string {
+	value, ok := node.Annotations[topolvm.SpareKeyPrefix+deviceClass]
+	if !ok {
+		return ""
+	}
+	if _, err := spare.Parse(value); err != nil {
+		meLogger.Error(err, "ignored invalid spare annotation", "device_class", deviceClass, "value", value)
+		return ""
+	}
+	return value
+}
+
+// overrideAnnotationsChanged returns true if the annotations to override the capacity reported by lvmd,
+// i.e. the spare capacity and draining, are changed.
+func overrideAnnotationsChanged(oldNode, newNode *corev1.Node) bool {
+	return !reflect.DeepEqual(overrideAnnotations(oldNode), overrideAnnotations(newNode))
+}
+
+func overrideAnnotations(node *corev1.Node) map[string]string {
+	ret := make(map[string]string)
+	for k, v := range node.Annotations {
+		// this is managed by topolvm-node itself.
+		if k == topolvm.DrainingKeyPrefix+topolvm.DefaultDeviceClassAnnotationName {
+			continue
+		}
+		if strings.HasPrefix(k, topolvm.SpareKeyPrefix) || strings.HasPrefix(k, topolvm.DrainingKeyPrefix) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// applyDraining sets zero to the free bytes in res for draining device-classes
+// so that no new volumes are placed on them.  It returns the draining device-classes.
+func applyDraining(node *corev1.Node, res *proto.WatchResponse) map[string]bool {
+	draining := make(map[string]bool)
+	for _, item := range res.Items {
+		if _, ok := node.Annotations[topolvm.DrainingKeyPrefix+item.DeviceClass]; !ok {
+			continue
+		}
+		draining[item.DeviceClass] = true
+		item.FreeBytes = 0
+		if item.Default {
+			res.FreeBytes = 0
+		}
+	}
+	return draining
+}
+
+// setDefaultDrainingAnnotation marks topolvm.DefaultDeviceClassAnnotationName as draining
+// while the default device-class is draining, so that users annotate only its name.
+func setDefaultDrainingAnnotation(node *corev1.Node, res *proto.WatchResponse, draining map[string]bool) {
+	key := topolvm.DrainingKeyPrefix + topolvm.DefaultDeviceClassAnnotationName
+	for _, item := range res.Items {
+		if item.Default && draining[item.DeviceClass] {
+			if node.Annotations == nil {
+				node.Annotations = make(map[string]string)
+			}
+			node.Annotations[key] = ""
+			return
+		}
+	}
+	delete(node.Annotations, key)
+}
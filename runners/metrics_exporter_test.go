@@ -0,0 +1,243 @@
+package runners
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// spareRecorder records SetSpare requests.
+type spareRecorder struct {
+	proto.VGServiceClient
+	requests []*proto.SetSpareRequest
+}
+
+func (r *spareRecorder) SetSpare(ctx context.Context, in *proto.SetSpareRequest, opts ...grpc.CallOption) (*proto.Empty, error) {
+	r.requests = append(r.requests, in)
+	return &proto.Empty{}, nil
+}
+
+func TestSyncSpares(t *testing.T) {
+	recorder := &spareRecorder{}
+	m := &metricsExporter{vgService: recorder, spares: make(map[string]string)}
+	res := &proto.WatchResponse{
+		Items: []*proto.WatchItem{
+			{DeviceClass: "ssd", Default: true},
+			{DeviceClass: "hdd"},
+			{DeviceClass: "nvme"},
+		},
+	}
+	newNode := func(annotations map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	testCases := []struct {
+		name     string
+		node     *corev1.Node
+		expected []string
+	}{
+		{
+			name: "first",
+			node: newNode(map[string]string{
+				topolvm.SpareKeyPrefix + "ssd":  "1%",
+				topolvm.SpareKeyPrefix + "nvme": "invalid",
+			}),
+			// all device-classes are set because lvmd may have the spare capacity set by the previous process.
+			expected: []string{"ssd=1%", "hdd=", "nvme="},
+		},
+		{
+			name: "unchanged",
+			node: newNode(map[string]string{
+				topolvm.SpareKeyPrefix + "ssd":  "1%",
+				topolvm.SpareKeyPrefix + "nvme": "invalid",
+			}),
+		},
+		{
+			name: "changed",
+			node: newNode(map[string]string{
+				topolvm.SpareKeyPrefix + "hdd":  "50Gi",
+				topolvm.SpareKeyPrefix + "nvme": "invalid",
+			}),
+			expected: []string{"ssd=", "hdd=50Gi"},
+		},
+	}
+	for _, tc := range testCases {
+		recorder.requests = nil
+		if err := m.syncSpares(context.Background(), tc.node, res); err != nil {
+			t.Fatal(err)
+		}
+		var actual []string
+		for _, req := range recorder.requests {
+			actual = append(actual, req.DeviceClass+"="+req.Spare)
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %v, but actual %v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestOverrideAnnotationsChanged(t *testing.T) {
+	newNode := func(annotations map[string]string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+	base := map[string]string{
+		topolvm.SpareKeyPrefix + "ssd":     "1%",
+		topolvm.DrainingKeyPrefix + "hdd":  "",
+		topolvm.CapacityKeyPrefix + "ssd":  "1000",
+		topolvm.CapacityUpdatedAtKey:       "2006-01-02T15:04:05Z",
+		topolvm.DrainingKeyPrefix + "nvme": "",
+	}
+	with := func(key, value string) map[string]string {
+		ret := make(map[string]string)
+		for k, v := range base {
+			ret[k] = v
+		}
+		if value == "-" {
+			delete(ret, key)
+		} else {
+			ret[key] = value
+		}
+		return ret
+	}
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{"spare changed", with(topolvm.SpareKeyPrefix+"ssd", "2%"), true},
+		{"spare added", with(topolvm.SpareKeyPrefix+"hdd", "2%"), true},
+		{"spare removed", with(topolvm.SpareKeyPrefix+"ssd", "-"), true},
+		{"draining added", with(topolvm.DrainingKeyPrefix+"ssd", ""), true},
+		{"draining removed", with(topolvm.DrainingKeyPrefix+"hdd", "-"), true},
+		{"capacity changed", with(topolvm.CapacityKeyPrefix+"ssd", "2000"), false},
+		{"default draining added by topolvm-node", with(topolvm.DrainingKeyPrefix+topolvm.DefaultDeviceClassAnnotationName, ""), false},
+		{"other annotation", with("foo", "bar"), false},
+	}
+	for _, tc := range testCases {
+		actual := overrideAnnotationsChanged(newNode(base), newNode(tc.annotations))
+		if actual != tc.expected {
+			t.Errorf("%s: expected %v, but actual %v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestApplyDraining(t *testing.T) {
+	newResponse := func() *proto.WatchResponse {
+		return &proto.WatchResponse{
+			FreeBytes: 90 << 30,
+			Items: []*proto.WatchItem{
+				{DeviceClass: "ssd", FreeBytes: 90 << 30, SizeBytes: 200 << 30, Default: true},
+				{DeviceClass: "hdd", FreeBytes: 80 << 30, SizeBytes: 200 << 30},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		keys        []string
+		expected    map[string]bool
+		free        []uint64
+		defaultFree uint64
+	}{
+		{
+			name:        "none",
+			expected:    map[string]bool{},
+			free:        []uint64{90 << 30, 80 << 30},
+			defaultFree: 90 << 30,
+		},
+		{
+			name:        "default by name",
+			keys:        []string{"ssd"},
+			expected:    map[string]bool{"ssd": true},
+			free:        []uint64{0, 80 << 30},
+			defaultFree: 0,
+		},
+		{
+			name:        "00default only",
+			keys:        []string{topolvm.DefaultDeviceClassAnnotationName},
+			expected:    map[string]bool{},
+			free:        []uint64{90 << 30, 80 << 30},
+			defaultFree: 90 << 30,
+		},
+		{
+			name:        "non-default",
+			keys:        []string{"hdd"},
+			expected:    map[string]bool{"hdd": true},
+			free:        []uint64{90 << 30, 0},
+			defaultFree: 90 << 30,
+		},
+	}
+
+	for _, tc := range testCases {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+		for _, key := range tc.keys {
+			node.Annotations[topolvm.DrainingKeyPrefix+key] = ""
+		}
+		res := newResponse()
+
+		draining := applyDraining(node, res)
+		if !reflect.DeepEqual(draining, tc.expected) {
+			t.Errorf("%s: expected %v, but actual %v", tc.name, tc.expected, draining)
+		}
+		for i, item := range res.Items {
+			if item.FreeBytes != tc.free[i] {
+				t.Errorf("%s: expected free bytes of %s to be %d, but actual %d", tc.name, item.DeviceClass, tc.free[i], item.FreeBytes)
+			}
+		}
+		if res.FreeBytes != tc.defaultFree {
+			t.Errorf("%s: expected default free bytes to be %d, but actual %d", tc.name, tc.defaultFree, res.FreeBytes)
+		}
+
+		setDefaultDrainingAnnotation(node, res, draining)
+		_, ok := node.Annotations[topolvm.DrainingKeyPrefix+topolvm.DefaultDeviceClassAnnotationName]
+		if ok != draining["ssd"] {
+			t.Errorf("%s: expected the draining annotation for the default device-class to be %v, but actual %v", tc.name, draining["ssd"], ok)
+		}
+	}
+}
+
+func TestSetCapacityAnnotations(t *testing.T) {
+	node := &corev1.Node{}
+	res := &proto.WatchResponse{
+		FreeBytes: 10 << 30,
+		Items: []*proto.WatchItem{
+			{DeviceClass: "ssd", FreeBytes: 10 << 30, Default: true},
+			{DeviceClass: "hdd", FreeBytes: 20 << 30},
+		},
+	}
+
+	t1 := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	setCapacityAnnotations(node, res, t1)
+	expected := map[string]string{
+		topolvm.CapacityKeyPrefix + topolvm.DefaultDeviceClassAnnotationName: "10737418240",
+		topolvm.CapacityKeyPrefix + "ssd":                                    "10737418240",
+		topolvm.CapacityKeyPrefix + "hdd":                                    "21474836480",
+		topolvm.CapacityUpdatedAtKey:                                         t1.Format(time.RFC3339Nano),
+	}
+	if !reflect.DeepEqual(node.Annotations, expected) {
+		t.Errorf("expected %v, but actual %v", expected, node.Annotations)
+	}
+
+	// the time is not updated unless the capacity changes.
+	setCapacityAnnotations(node, res, t1.Add(time.Minute))
+	if !reflect.DeepEqual(node.Annotations, expected) {
+		t.Errorf("expected %v, but actual %v", expected, node.Annotations)
+	}
+
+	t2 := t1.Add(2 * time.Minute)
+	res.Items[1].FreeBytes = 15 << 30
+	setCapacityAnnotations(node, res, t2)
+	expected[topolvm.CapacityKeyPrefix+"hdd"] = "16106127360"
+	expected[topolvm.CapacityUpdatedAtKey] = t2.Format(time.RFC3339Nano)
+	if !reflect.DeepEqual(node.Annotations, expected) {
+		t.Errorf("expected %v, but actual %v", expected, node.Annotations)
+	}
+}
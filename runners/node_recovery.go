@@ -0,0 +1,232 @@
+package runners
+
+import (
+	"context"
+	"strings"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var nrLogger = ctrl.Log.WithName("runners").WithName("node_recovery")
+
+// lvmdVolume is a LV in lvmd with its device-class.
+type lvmdVolume struct {
+	deviceClass string
+	volume      *proto.LogicalVolume
+}
+
+// recoveryPlan is the result of matching LVs in lvmd with LogicalVolumes and PersistentVolumes.
+type recoveryPlan struct {
+	// create is the list of LogicalVolumes to be re-created for existing LVs.
+	create []*topolvmv1.LogicalVolume
+	// missing is the list of PersistentVolumes whose LV is not found in lvmd.
+	missing []*corev1.PersistentVolume
+	// unowned is the list of PersistentVolumes whose LV is not owned by TopoLVM.
+	// The LV cannot be taken over until it is tagged with topolvm.OwnedTag.
+	unowned []*corev1.PersistentVolume
+	// conflicts is the list of PersistentVolumes whose LogicalVolume refers to another LV.
+	conflicts []*corev1.PersistentVolume
+	// orphans is the list of LVs owned by TopoLVM that no PersistentVolume nor LogicalVolume refers to.
+	orphans []string
+}
+
+type nodeRecovery struct {
+	reader    client.Reader
+	writer    client.Writer
+	nodeName  string
+	vgService proto.VGServiceClient
+}
+
+var _ manager.LeaderElectionRunnable = &nodeRecovery{}
+
+// NewNodeRecovery creates controller-runtime's manager.Runnable to match LVs in lvmd
+// with LogicalVolumes and PersistentVolumes once on startup.
+// It re-creates LogicalVolumes for PersistentVolumes that still reference the node,
+// and reports LVs and PersistentVolumes that cannot be matched.
+func NewNodeRecovery(conn *grpc.ClientConn, mgr manager.Manager, nodeName string) manager.Runnable {
+	return &nodeRecovery{
+		reader:    mgr.GetAPIReader(),
+		writer:    mgr.GetClient(),
+		nodeName:  nodeName,
+		vgService: proto.NewVGServiceClient(conn),
+	}
+}
+
+// Start implements controller-runtime's manager.Runnable.
+func (r *nodeRecovery) Start(ctx context.Context) error {
+	volumes, err := r.listVolumes(ctx)
+	if err != nil {
+		nrLogger.Error(err, "failed to list LVs")
+		return err
+	}
+
+	var lvList topolvmv1.LogicalVolumeList
+	if err := r.reader.List(ctx, &lvList); err != nil {
+		nrLogger.Error(err, "failed to list LogicalVolumes")
+		return err
+	}
+
+	var pvList corev1.PersistentVolumeList
+	if err := r.reader.List(ctx, &pvList); err != nil {
+		nrLogger.Error(err, "failed to list PersistentVolumes")
+		return err
+	}
+
+	plan := planRecovery(r.nodeName, volumes, lvList.Items, pvList.Items)
+
+	for _, lv := range plan.create {
+		if err := r.writer.Create(ctx, lv); err != nil {
+			nrLogger.Error(err, "failed to re-create LogicalVolume", "name", lv.Name, "lv", lv.Annotations[topolvm.RecoverFromKey])
+			continue
+		}
+		nrLogger.Info("re-created LogicalVolume for existing LV", "name", lv.Name, "lv", lv.Annotations[topolvm.RecoverFromKey])
+	}
+	for _, pv := range plan.missing {
+		nrLogger.Info("LV for PersistentVolume is not found", "name", pv.Name, "volume_id", pv.Spec.CSI.VolumeHandle)
+	}
+	for _, pv := range plan.unowned {
+		nrLogger.Info("LV for PersistentVolume is not owned by TopoLVM", "name", pv.Name, "volume_id", pv.Spec.CSI.VolumeHandle, "tag", topolvm.OwnedTag)
+	}
+	for _, pv := range plan.conflicts {
+		nrLogger.Info("LogicalVolume for PersistentVolume refers to another LV", "name", pv.Name, "volume_id", pv.Spec.CSI.VolumeHandle)
+	}
+	for _, name := range plan.orphans {
+		nrLogger.Info("LV is not referred to by any PersistentVolume nor LogicalVolume", "lv", name)
+	}
+	return nil
+}
+
+// NeedLeaderElection implements controller-runtime's manager.LeaderElectionRunnable.
+func (r *nodeRecovery) NeedLeaderElection() bool {
+	return false
+}
+
+// listVolumes returns LVs in all device-classes of lvmd keyed by their names.
+func (r *nodeRecovery) listVolumes(ctx context.Context) (map[string]lvmdVolume, error) {
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	wc, err := r.vgService.Watch(wctx, &proto.WatchRequest{})
+	if err != nil {
+		return nil, err
+	}
+	res, err := wc.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]lvmdVolume)
+	for _, item := range res.Items {
+		resp, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: item.DeviceClass})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Volumes {
+			volumes[v.Name] = lvmdVolume{deviceClass: item.DeviceClass, volume: v}
+		}
+	}
+	return volumes, nil
+}
+
+func planRecovery(nodeName string, volumes map[string]lvmdVolume, lvs []topolvmv1.LogicalVolume, pvs []corev1.PersistentVolume) *recoveryPlan {
+	plan := &recoveryPlan{}
+
+	lvByName := make(map[string]*topolvmv1.LogicalVolume)
+	referred := make(map[string]bool)
+	for i := range lvs {
+		lv := &lvs[i]
+		lvByName[lv.Name] = lv
+		if lv.Spec.NodeName == nodeName && lv.Status.VolumeID != "" {
+			referred[lv.Status.VolumeID] = true
+		}
+	}
+
+	for i := range pvs {
+		pv := &pvs[i]
+		if pv.DeletionTimestamp != nil || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != topolvm.PluginName {
+			continue
+		}
+		if persistentVolumeNodeName(pv) != nodeName {
+			continue
+		}
+		volumeID := pv.Spec.CSI.VolumeHandle
+		if referred[volumeID] {
+			continue
+		}
+		referred[volumeID] = true
+
+		if _, ok := lvByName[pv.Name]; ok {
+			plan.conflicts = append(plan.conflicts, pv)
+			continue
+		}
+		v, ok := volumes[volumeID]
+		if !ok {
+			plan.missing = append(plan.missing, pv)
+			continue
+		}
+		// LogicalVolumes for LVs without the tag would fail to take them over.
+		if !isOwnedVolume(v.volume) {
+			plan.unowned = append(plan.unowned, pv)
+			continue
+		}
+		plan.create = append(plan.create, &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: pv.Name,
+				Annotations: map[string]string{
+					topolvm.RecoverFromKey: volumeID,
+				},
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:        pv.Name,
+				NodeName:    nodeName,
+				DeviceClass: v.deviceClass,
+				Size:        *resource.NewQuantity(int64(v.volume.SizeGb<<30), resource.BinarySI),
+			},
+		})
+	}
+
+	for name, v := range volumes {
+		if referred[name] || !isOwnedVolume(v.volume) {
+			continue
+		}
+		plan.orphans = append(plan.orphans, name)
+	}
+	return plan
+}
+
+// persistentVolumeNodeName returns the node name in the node affinity of pv.
+func persistentVolumeNodeName(pv *corev1.PersistentVolume) string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return ""
+	}
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == topolvm.TopologyNodeKey && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+				return expr.Values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// isOwnedVolume returns true if v is created or imported by TopoLVM and is not in the trash.
+func isOwnedVolume(v *proto.LogicalVolume) bool {
+	var owned bool
+	for _, tag := range v.Tags {
+		if strings.HasPrefix(tag, topolvm.TrashedAtTagPrefix) {
+			return false
+		}
+		if tag == topolvm.OwnedTag {
+			owned = true
+		}
+	}
+	return owned
+}
@@ -0,0 +1,102 @@
+package runners
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPersistentVolume(name, volumeID, nodeName string) corev1.PersistentVolume {
+	return corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       topolvm.PluginName,
+					VolumeHandle: volumeID,
+				},
+			},
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      topolvm.TopologyNodeKey,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{nodeName},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestPlanRecovery(t *testing.T) {
+	volumes := map[string]lvmdVolume{
+		"uid1": {deviceClass: "ssd", volume: &proto.LogicalVolume{Name: "uid1", SizeGb: 5, Tags: []string{topolvm.OwnedTag}}},
+		"uid2": {deviceClass: "", volume: &proto.LogicalVolume{Name: "uid2", SizeGb: 1, Tags: []string{topolvm.OwnedTag}}},
+		"uid3": {deviceClass: "", volume: &proto.LogicalVolume{Name: "uid3", SizeGb: 1, Tags: []string{topolvm.OwnedTag}}},
+		"trash-uid4": {deviceClass: "", volume: &proto.LogicalVolume{Name: "trash-uid4", SizeGb: 1,
+			Tags: []string{topolvm.OwnedTag, topolvm.TrashedAtTagPrefix + "1600000000"}}},
+		"other": {deviceClass: "", volume: &proto.LogicalVolume{Name: "other", SizeGb: 1}},
+		// created before TopoLVM tagged LVs.
+		"uid8": {deviceClass: "", volume: &proto.LogicalVolume{Name: "uid8", SizeGb: 1}},
+	}
+	lvs := []topolvmv1.LogicalVolume{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv2"},
+			Spec:       topolvmv1.LogicalVolumeSpec{NodeName: "node1"},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: "uid2"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv5"},
+			Spec:       topolvmv1.LogicalVolumeSpec{NodeName: "node1"},
+			Status:     topolvmv1.LogicalVolumeStatus{VolumeID: "uid-new"},
+		},
+	}
+	pvs := []corev1.PersistentVolume{
+		testPersistentVolume("pv1", "uid1", "node1"),
+		testPersistentVolume("pv2", "uid2", "node1"),
+		testPersistentVolume("pv5", "uid5", "node1"),
+		testPersistentVolume("pv6", "uid6", "node1"),
+		testPersistentVolume("pv7", "uid7", "node2"),
+		testPersistentVolume("pv8", "uid8", "node1"),
+	}
+
+	plan := planRecovery("node1", volumes, lvs, pvs)
+
+	if len(plan.create) != 1 {
+		t.Fatalf("one LogicalVolume should be re-created: %d", len(plan.create))
+	}
+	lv := plan.create[0]
+	if lv.Name != "pv1" || lv.Spec.Name != "pv1" {
+		t.Errorf("unexpected name: %s, %s", lv.Name, lv.Spec.Name)
+	}
+	if lv.Annotations[topolvm.RecoverFromKey] != "uid1" {
+		t.Errorf("unexpected annotation: %v", lv.Annotations)
+	}
+	if lv.Spec.NodeName != "node1" || lv.Spec.DeviceClass != "ssd" || lv.Spec.Size.Value() != 5<<30 {
+		t.Errorf("unexpected spec: %+v", lv.Spec)
+	}
+
+	if len(plan.conflicts) != 1 || plan.conflicts[0].Name != "pv5" {
+		t.Errorf("pv5 should conflict: %v", plan.conflicts)
+	}
+	if len(plan.missing) != 1 || plan.missing[0].Name != "pv6" {
+		t.Errorf("pv6 should be missing: %v", plan.missing)
+	}
+
+	if len(plan.unowned) != 1 || plan.unowned[0].Name != "pv8" {
+		t.Errorf("pv8 should be unowned: %v", plan.unowned)
+	}
+
+	sort.Strings(plan.orphans)
+	if len(plan.orphans) != 1 || plan.orphans[0] != "uid3" {
+		t.Errorf("only uid3 should be orphan: %v", plan.orphans)
+	}
+}
@@ -43,3 +43,41 @@ type ExtenderFilterResult struct {
 
 // FailedNodesMap is copied from https://godoc.org/k8s.io/kubernetes/pkg/scheduler/api/v1#FailedNodesMap
 type FailedNodesMap map[string]string
+
+// ExtenderPreemptionArgs is copied from https://godoc.org/k8s.io/kube-scheduler/extender/v1#ExtenderPreemptionArgs
+type ExtenderPreemptionArgs struct {
+	// Pod being scheduled
+	Pod *apiv1.Pod `json:"pod"`
+	// Victims map generated by scheduler preemption phase
+	// Only set NodeNameToMetaVictims if ExtenderConfig.NodeCacheCapable == true. Otherwise, only set NodeNameToVictims.
+	NodeNameToVictims     map[string]*Victims     `json:"nodeNameToVictims,omitempty"`
+	NodeNameToMetaVictims map[string]*MetaVictims `json:"nodeNameToMetaVictims,omitempty"`
+}
+
+// Victims is copied from https://godoc.org/k8s.io/kube-scheduler/extender/v1#Victims
+type Victims struct {
+	// a group of pods expected to be preempted.
+	Pods []*apiv1.Pod `json:"pods"`
+	// the number of PDB violation.
+	NumPDBViolations int64 `json:"numPDBViolations"`
+}
+
+// MetaPod is copied from https://godoc.org/k8s.io/kube-scheduler/extender/v1#MetaPod
+type MetaPod struct {
+	// UID of the pod
+	UID string `json:"uid"`
+}
+
+// MetaVictims is copied from https://godoc.org/k8s.io/kube-scheduler/extender/v1#MetaVictims
+type MetaVictims struct {
+	// a group of pods expected to be preempted.
+	// Only Pod identifiers will be sent and user are expect to get Pod in their own way.
+	Pods []*MetaPod `json:"pods"`
+	// the number of PDB violation.
+	NumPDBViolations int64 `json:"numPDBViolations"`
+}
+
+// ExtenderPreemptionResult is copied from https://godoc.org/k8s.io/kube-scheduler/extender/v1#ExtenderPreemptionResult
+type ExtenderPreemptionResult struct {
+	NodeNameToMetaVictims map[string]*MetaVictims `json:"nodeNameToMetaVictims,omitempty"`
+}
@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeExplanation describes how the scheduler extender filters and scores a node.
+type NodeExplanation struct {
+	// Name of the node
+	Name string `json:"name"`
+	// Capacity of the node per device-class read from the capacity annotations
+	Capacity map[string]int64 `json:"capacity"`
+	// Reserved capacity of the node per device-class
+	Reserved map[string]int64 `json:"reserved,omitempty"`
+	// Passed is true if the node passes the predicate verb
+	Passed bool `json:"passed"`
+	// Reason why the node is filtered out
+	Reason string `json:"reason,omitempty"`
+	// Message describing why the node is filtered out
+	Message string `json:"message,omitempty"`
+	// Score of the node by the prioritize verb
+	Score int `json:"score"`
+}
+
+// ExplainResult is the response of the explain endpoint.
+type ExplainResult struct {
+	// Requested capacity of the pod per device-class
+	Requested map[string]int64 `json:"requested"`
+	// Nodes describes the decisions for each node
+	Nodes []NodeExplanation `json:"nodes"`
+}
+
+func explainNodes(nodes []corev1.Node, requested map[string]int64, reserved map[string]map[string]int64,
+	defaultDivisor float64, divisors map[string]float64, defaultStrategy ScoringStrategy, strategies map[string]ScoringStrategy) ExplainResult {
+	dcs := make([]string, 0, len(requested))
+	for dc := range requested {
+		dcs = append(dcs, dc)
+	}
+
+	result := ExplainResult{
+		Requested: requested,
+		Nodes:     make([]NodeExplanation, len(nodes)),
+	}
+	for i, node := range nodes {
+		reason, message := filterNode(node, requested, reserved[node.Name])
+		result.Nodes[i] = NodeExplanation{
+			Name:     node.Name,
+			Capacity: nodeCapacity(node),
+			Reserved: reserved[node.Name],
+			Passed:   reason == "",
+			Reason:   reason,
+			Message:  message,
+		}
+		if len(dcs) != 0 {
+			result.Nodes[i].Score = scoreNode(node, dcs, defaultDivisor, divisors, defaultStrategy, strategies)
+		}
+	}
+	return result
+}
+
+func nodeCapacity(node corev1.Node) map[string]int64 {
+	result := make(map[string]int64)
+	for k, v := range node.Annotations {
+		if !strings.HasPrefix(k, topolvm.CapacityKeyPrefix) {
+			continue
+		}
+		capacity, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		result[k[len(topolvm.CapacityKeyPrefix):]] = capacity
+	}
+	return result
+}
+
+func (s scheduler) explain(w http.ResponseWriter, r *http.Request) {
+	var input ExtenderArgs
+
+	reader := http.MaxBytesReader(w, r.Body, 10<<20)
+	err := json.NewDecoder(reader).Decode(&input)
+	if err != nil || input.Pod == nil || (input.Nodes == nil && s.nodeLister == nil) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	if input.Nodes == nil {
+		nodes := new(corev1.NodeList)
+		if err := s.nodeLister.List(r.Context(), nodes); err != nil {
+			http.Error(w, "failed to list nodes", http.StatusInternalServerError)
+			return
+		}
+		sort.Slice(nodes.Items, func(i, j int) bool {
+			return nodes.Items[i].Name < nodes.Items[j].Name
+		})
+		input.Nodes = nodes
+	}
+
+	requested := extractRequestedSize(input.Pod)
+	reserved, err := s.reserved(r.Context(), requested)
+	if err != nil {
+		http.Error(w, "failed to calculate reserved capacity", http.StatusInternalServerError)
+		return
+	}
+	result := explainNodes(input.Nodes.Items, requested, reserved, s.defaultDivisor, s.divisors, s.defaultStrategy, s.strategies)
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
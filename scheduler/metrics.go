@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "topolvm"
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "scheduler",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to the scheduler extender",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"verb"})
+
+	explainDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "scheduler",
+		Name:      "explain_duration_seconds",
+		Help:      "Latency of requests to the explain endpoint",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	filterRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "scheduler",
+		Name:      "filter_rejections_total",
+		Help:      "Number of nodes filtered out by the predicate verb",
+	}, []string{"reason"})
+)
+
+func init() {
+	prometheus.MustRegister(requestDuration, explainDuration, filterRejections)
+}
@@ -1,31 +1,50 @@
 package scheduler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/topolvm/topolvm"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func filterNodes(nodes corev1.NodeList, requested map[string]int64) ExtenderFilterResult {
+// Reasons why nodes are filtered out.
+// These are used as the label values of the metrics, so they must not contain variable parts.
+const (
+	reasonNoAnnotation  = "no_capacity_annotation"
+	reasonBadAnnotation = "bad_capacity_annotation"
+	reasonOutOfSpace    = "out_of_space"
+	reasonReserved      = "reserved"
+	reasonDraining      = "draining"
+)
+
+type filterFailure struct {
+	reason  string
+	message string
+}
+
+func filterNodes(nodes corev1.NodeList, requested map[string]int64, reserved map[string]map[string]int64) ExtenderFilterResult {
 	if len(requested) == 0 {
 		return ExtenderFilterResult{
 			Nodes: &nodes,
 		}
 	}
 
-	failedNodes := make([]string, len(nodes.Items))
+	failures := make([]filterFailure, len(nodes.Items))
 	wg := &sync.WaitGroup{}
 	wg.Add(len(nodes.Items))
 	for i := range nodes.Items {
-		reason := &failedNodes[i]
+		failure := &failures[i]
 		node := nodes.Items[i]
 		go func() {
-			*reason = filterNode(node, requested)
+			failure.reason, failure.message = filterNode(node, requested, reserved[node.Name])
 			wg.Done()
 		}()
 	}
@@ -34,31 +53,51 @@ func filterNodes(nodes corev1.NodeList, requested map[string]int64) ExtenderFilt
 		Nodes:       &corev1.NodeList{},
 		FailedNodes: FailedNodesMap{},
 	}
-	for i, reason := range failedNodes {
-		if len(reason) == 0 {
+	for i, failure := range failures {
+		if len(failure.reason) == 0 {
 			result.Nodes.Items = append(result.Nodes.Items, nodes.Items[i])
 		} else {
-			result.FailedNodes[nodes.Items[i].Name] = reason
+			result.FailedNodes[nodes.Items[i].Name] = failure.message
+			filterRejections.WithLabelValues(failure.reason).Inc()
 		}
 	}
 	return result
 }
 
-func filterNode(node corev1.Node, requested map[string]int64) string {
-	for dc, required := range requested {
+// filterNode returns empty strings if node has enough capacity for requested.
+// Otherwise, it returns the reason and the message why node is filtered out.
+func filterNode(node corev1.Node, requested map[string]int64, reserved map[string]int64) (string, string) {
+	dcs := make([]string, 0, len(requested))
+	for dc := range requested {
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+
+	for _, dc := range dcs {
+		required := requested[dc]
+		// topolvm-node marks topolvm.DefaultDeviceClassAnnotationName as draining
+		// while the default device-class is draining.
+		if _, ok := node.Annotations[topolvm.DrainingKeyPrefix+dc]; ok {
+			return reasonDraining, fmt.Sprintf("device-class %s is draining", dc)
+		}
 		val, ok := node.Annotations[topolvm.CapacityKeyPrefix+dc]
 		if !ok {
-			return "no capacity annotation"
+			return reasonNoAnnotation, fmt.Sprintf("no capacity annotation for device-class %s", dc)
 		}
 		capacity, err := strconv.ParseUint(val, 10, 64)
 		if err != nil {
-			return "bad capacity annotation: " + val
+			return reasonBadAnnotation, fmt.Sprintf("bad capacity annotation for device-class %s: %s", dc, val)
 		}
 		if capacity < uint64(required) {
-			return "out of VG free space"
+			return reasonOutOfSpace, fmt.Sprintf("out of VG free space for device-class %s: requested=%d, capacity=%d",
+				dc, required, capacity)
+		}
+		if capacity < uint64(required+reserved[dc]) {
+			return reasonReserved, fmt.Sprintf("out of VG free space for device-class %s due to reservation: requested=%d, capacity=%d, reserved=%d",
+				dc, required, capacity, reserved[dc])
 		}
 	}
-	return ""
+	return "", ""
 }
 
 func extractRequestedSize(pod *corev1.Pod) map[string]int64 {
@@ -78,6 +117,13 @@ func extractRequestedSize(pod *corev1.Pod) map[string]int64 {
 	return result
 }
 
+func (s scheduler) reserved(ctx context.Context, requested map[string]int64) (map[string]map[string]int64, error) {
+	if s.reservation == nil || len(requested) == 0 {
+		return nil, nil
+	}
+	return s.reservation.Reserved(ctx, time.Now())
+}
+
 func (s scheduler) predicate(w http.ResponseWriter, r *http.Request) {
 	var input ExtenderArgs
 
@@ -89,7 +135,12 @@ func (s scheduler) predicate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	requested := extractRequestedSize(input.Pod)
-	result := filterNodes(*input.Nodes, requested)
+	reserved, err := s.reserved(r.Context(), requested)
+	if err != nil {
+		http.Error(w, "failed to calculate reserved capacity", http.StatusInternalServerError)
+		return
+	}
+	result := filterNodes(*input.Nodes, requested, reserved)
 	w.Header().Set("content-type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
@@ -12,6 +12,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+func testDrainingNode(name string, cap1Gb, cap2Gb, cap3Gb int64, dc string) corev1.Node {
+	node := testNode(name, cap1Gb, cap2Gb, cap3Gb)
+	node.Annotations[topolvm.DrainingKeyPrefix+dc] = ""
+	return node
+}
+
 func testNode(name string, cap1Gb, cap2Gb, cap3Gb int64) corev1.Node {
 	return corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -29,6 +35,7 @@ func TestFilterNodes(t *testing.T) {
 	testCases := []struct {
 		nodes     corev1.NodeList
 		requested map[string]int64
+		reserved  map[string]map[string]int64
 		expect    ExtenderFilterResult
 	}{
 		{
@@ -61,9 +68,9 @@ func TestFilterNodes(t *testing.T) {
 					},
 				},
 				FailedNodes: FailedNodesMap{
-					"10.1.1.2": "out of VG free space",
-					"10.1.1.3": "no capacity annotation",
-					"10.1.1.4": "bad capacity annotation: foo",
+					"10.1.1.2": "out of VG free space for device-class ssd: requested=2147483648, capacity=1073741824",
+					"10.1.1.3": "no capacity annotation for device-class ssd",
+					"10.1.1.4": "bad capacity annotation for device-class ssd: foo",
 				},
 			},
 		},
@@ -87,8 +94,8 @@ func TestFilterNodes(t *testing.T) {
 					},
 				},
 				FailedNodes: FailedNodesMap{
-					"10.1.1.2": "out of VG free space",
-					"10.1.1.3": "out of VG free space",
+					"10.1.1.2": "out of VG free space for device-class hdd1: requested=10737418240, capacity=5368709120",
+					"10.1.1.3": "out of VG free space for device-class hdd1: requested=10737418240, capacity=5368709120",
 				},
 			},
 		},
@@ -110,10 +117,59 @@ func TestFilterNodes(t *testing.T) {
 				FailedNodes: map[string]string{},
 			},
 		},
+		{
+			nodes: corev1.NodeList{
+				Items: []corev1.Node{
+					testNode("10.1.1.1", 5, 10, 10),
+					testNode("10.1.1.2", 5, 10, 10),
+					testNode("10.1.1.3", 5, 10, 10),
+				},
+			},
+			requested: map[string]int64{
+				"ssd": 2 << 30,
+			},
+			reserved: map[string]map[string]int64{
+				"10.1.1.1": {"ssd": 3 << 30},
+				"10.1.1.2": {"ssd": 4 << 30},
+				"10.1.1.3": {"hdd1": 10 << 30},
+			},
+			expect: ExtenderFilterResult{
+				Nodes: &corev1.NodeList{
+					Items: []corev1.Node{
+						testNode("10.1.1.1", 5, 10, 10),
+						testNode("10.1.1.3", 5, 10, 10),
+					},
+				},
+				FailedNodes: FailedNodesMap{
+					"10.1.1.2": "out of VG free space for device-class ssd due to reservation: requested=2147483648, capacity=5368709120, reserved=4294967296",
+				},
+			},
+		},
+		{
+			nodes: corev1.NodeList{
+				Items: []corev1.Node{
+					testDrainingNode("10.1.1.1", 5, 10, 10, "ssd"),
+					testDrainingNode("10.1.1.2", 5, 10, 10, "hdd1"),
+				},
+			},
+			requested: map[string]int64{
+				"ssd": 2 << 30,
+			},
+			expect: ExtenderFilterResult{
+				Nodes: &corev1.NodeList{
+					Items: []corev1.Node{
+						testDrainingNode("10.1.1.2", 5, 10, 10, "hdd1"),
+					},
+				},
+				FailedNodes: FailedNodesMap{
+					"10.1.1.1": "device-class ssd is draining",
+				},
+			},
+		},
 	}
 
 	for _, tt := range testCases {
-		result := filterNodes(tt.nodes, tt.requested)
+		result := filterNodes(tt.nodes, tt.requested, tt.reserved)
 		if len(result.Nodes.Items) != len(tt.expect.Nodes.Items) {
 			t.Fatalf("not match length of filtered NodeList: expect=%d actual=%d", len(tt.expect.Nodes.Items), len(result.Nodes.Items))
 		}
@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// preemptNodes returns the victims of nodes where evicting them frees enough capacity for requested.
+// Nodes whose victims do not free enough capacity are removed from the result.
+func preemptNodes(ctx context.Context, reader client.Reader, victims map[string]*Victims,
+	requested map[string]int64, reserved map[string]map[string]int64) (map[string]*MetaVictims, error) {
+	result := make(map[string]*MetaVictims)
+	for nodeName, v := range victims {
+		var node corev1.Node
+		err := reader.Get(ctx, client.ObjectKey{Name: nodeName}, &node)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		freed, err := freedCapacity(ctx, reader, v.Pods)
+		if err != nil {
+			return nil, err
+		}
+		if !hasEnoughCapacity(node, requested, reserved[nodeName], freed) {
+			continue
+		}
+		result[nodeName] = toMetaVictims(v)
+	}
+	return result, nil
+}
+
+// hasEnoughCapacity returns true if node has enough capacity for requested after freed capacity is released.
+func hasEnoughCapacity(node corev1.Node, requested, reserved, freed map[string]int64) bool {
+	for dc, required := range requested {
+		if _, ok := node.Annotations[topolvm.DrainingKeyPrefix+dc]; ok {
+			return false
+		}
+		val, ok := node.Annotations[topolvm.CapacityKeyPrefix+dc]
+		if !ok {
+			return false
+		}
+		capacity, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return false
+		}
+		if capacity+freed[dc]-reserved[dc] < required {
+			return false
+		}
+	}
+	return true
+}
+
+// freedCapacity returns the capacity released by deleting the ephemeral volumes of pods.
+// Volumes referenced by PersistentVolumeClaims outlive pods, so they do not free capacity.
+func freedCapacity(ctx context.Context, reader client.Reader, pods []*corev1.Pod) (map[string]int64, error) {
+	result := make(map[string]int64)
+	for _, pod := range pods {
+		for _, vol := range pod.Spec.Volumes {
+			switch {
+			case vol.CSI != nil:
+				if vol.CSI.Driver != topolvm.PluginName {
+					continue
+				}
+				var size int64 = topolvm.DefaultSize
+				if val, ok := vol.CSI.VolumeAttributes[topolvm.EphemeralVolumeSizeKey]; ok {
+					sizeGb, err := strconv.ParseInt(val, 10, 64)
+					if err != nil {
+						continue
+					}
+					size = sizeGb << 30
+				}
+				result[topolvm.DefaultDeviceClassAnnotationName] += size
+			case vol.Ephemeral != nil && vol.Ephemeral.VolumeClaimTemplate != nil:
+				dc, size, err := ephemeralClaimCapacity(ctx, reader, vol.Ephemeral.VolumeClaimTemplate.Spec)
+				if err != nil {
+					return nil, err
+				}
+				if size != 0 {
+					result[dc] += size
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// ephemeralClaimCapacity returns the device-class and the size of a generic ephemeral volume.
+// It returns zero size if the volume is not provisioned by TopoLVM.
+func ephemeralClaimCapacity(ctx context.Context, reader client.Reader, spec corev1.PersistentVolumeClaimSpec) (string, int64, error) {
+	if spec.StorageClassName == nil {
+		return "", 0, nil
+	}
+	var sc storagev1.StorageClass
+	err := reader.Get(ctx, client.ObjectKey{Name: *spec.StorageClassName}, &sc)
+	if apierrors.IsNotFound(err) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if sc.Provisioner != topolvm.PluginName {
+		return "", 0, nil
+	}
+
+	var size int64 = topolvm.DefaultSize
+	if req, ok := spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		if req.Value() > topolvm.DefaultSize {
+			size = ((req.Value()-1)>>30 + 1) << 30
+		}
+	}
+	dc, ok := sc.Parameters[topolvm.DeviceClassKey]
+	if !ok {
+		dc = topolvm.DefaultDeviceClassAnnotationName
+	}
+	return dc, size, nil
+}
+
+func toMetaVictims(v *Victims) *MetaVictims {
+	result := &MetaVictims{
+		Pods:             make([]*MetaPod, len(v.Pods)),
+		NumPDBViolations: v.NumPDBViolations,
+	}
+	for i, pod := range v.Pods {
+		result.Pods[i] = &MetaPod{UID: string(pod.UID)}
+	}
+	return result
+}
+
+func (s scheduler) preemption(w http.ResponseWriter, r *http.Request) {
+	var input ExtenderPreemptionArgs
+
+	reader := http.MaxBytesReader(w, r.Body, 10<<20)
+	err := json.NewDecoder(reader).Decode(&input)
+	if err != nil || input.Pod == nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	requested := extractRequestedSize(input.Pod)
+	result := ExtenderPreemptionResult{
+		NodeNameToMetaVictims: input.NodeNameToMetaVictims,
+	}
+	switch {
+	case input.NodeNameToVictims == nil:
+		// Pods of victims are not sent when the extender is configured with nodeCacheCapable.
+		// The victims are returned as they are because their volumes cannot be inspected.
+	case len(requested) == 0 || s.reader == nil:
+		result.NodeNameToMetaVictims = make(map[string]*MetaVictims)
+		for nodeName, v := range input.NodeNameToVictims {
+			result.NodeNameToMetaVictims[nodeName] = toMetaVictims(v)
+		}
+	default:
+		reserved, err := s.reserved(r.Context(), requested)
+		if err != nil {
+			http.Error(w, "failed to calculate reserved capacity", http.StatusInternalServerError)
+			return
+		}
+		result.NodeNameToMetaVictims, err = preemptNodes(r.Context(), s.reader, input.NodeNameToVictims, requested, reserved)
+		if err != nil {
+			http.Error(w, "failed to process preemption", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
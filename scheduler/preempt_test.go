@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testPreemptionNode(name string, ssd string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				topolvm.CapacityKeyPrefix + "ssd":                                    ssd,
+				topolvm.CapacityKeyPrefix + topolvm.DefaultDeviceClassAnnotationName: "0",
+			},
+		},
+	}
+}
+
+func testVictim(uid string, volumes ...corev1.Volume) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      uid,
+			Namespace: "default",
+			UID:       types.UID(uid),
+		},
+		Spec: corev1.PodSpec{Volumes: volumes},
+	}
+}
+
+func testEphemeralVolume(sc string, sizeGb int64) corev1.Volume {
+	return corev1.Volume{
+		Name: "scratch",
+		VolumeSource: corev1.VolumeSource{
+			Ephemeral: &corev1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+					Spec: corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &sc,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: *resource.NewQuantity(sizeGb<<30, resource.BinarySI),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPreemptNodes(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		testPreemptionNode("node1", "1073741824"),
+		testPreemptionNode("node2", "1073741824"),
+		testPreemptionNode("node3", "1073741824"),
+		testPreemptionNode("node4", "4294967296"),
+		testPreemptionNode("node5", "1073741824"),
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "topolvm-ssd"},
+			Provisioner: topolvm.PluginName,
+			Parameters:  map[string]string{topolvm.DeviceClassKey: "ssd"},
+		},
+		&storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "other"},
+			Provisioner: "other.example.com",
+			Parameters:  map[string]string{topolvm.DeviceClassKey: "ssd"},
+		},
+	).Build()
+
+	inline := corev1.Volume{
+		Name: "inline",
+		VolumeSource: corev1.VolumeSource{
+			CSI: &corev1.CSIVolumeSource{
+				Driver:           topolvm.PluginName,
+				VolumeAttributes: map[string]string{topolvm.EphemeralVolumeSizeKey: "2"},
+			},
+		},
+	}
+	claim := corev1.Volume{
+		Name: "data",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+		},
+	}
+
+	victims := map[string]*Victims{
+		// frees 3GiB of ssd
+		"node1": {Pods: []*corev1.Pod{testVictim("pod1", testEphemeralVolume("topolvm-ssd", 3))}, NumPDBViolations: 1},
+		// volumes of PVCs and other provisioners are not freed
+		"node2": {Pods: []*corev1.Pod{testVictim("pod2", claim, testEphemeralVolume("other", 3))}},
+		// frees 2GiB of ssd, which is not enough
+		"node3": {Pods: []*corev1.Pod{testVictim("pod3", testEphemeralVolume("topolvm-ssd", 1)), testVictim("pod4", testEphemeralVolume("topolvm-ssd", 1))}},
+		// has enough capacity without victims, but it is reserved
+		"node4": {Pods: []*corev1.Pod{testVictim("pod5")}},
+		// frees 2GiB of the default device-class
+		"node5": {Pods: []*corev1.Pod{testVictim("pod6", inline, testEphemeralVolume("topolvm-ssd", 2))}},
+		// does not exist
+		"node6": {Pods: []*corev1.Pod{testVictim("pod7")}},
+	}
+	requested := map[string]int64{"ssd": 4 << 30}
+	reserved := map[string]map[string]int64{
+		"node4": {"ssd": 1 << 30},
+	}
+
+	result, err := preemptNodes(context.Background(), c, victims, requested, reserved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]*MetaVictims{
+		"node1": {Pods: []*MetaPod{{UID: "pod1"}}, NumPDBViolations: 1},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, but actual %v", expected, result)
+	}
+
+	requested = map[string]int64{
+		"ssd":                                    3 << 30,
+		topolvm.DefaultDeviceClassAnnotationName: 2 << 30,
+	}
+	result, err = preemptNodes(context.Background(), c, victims, requested, reserved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]*MetaVictims{
+		"node5": {Pods: []*MetaPod{{UID: "pod6"}}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, but actual %v", expected, result)
+	}
+}
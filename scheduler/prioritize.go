@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"net/http"
 	"strconv"
@@ -31,7 +33,90 @@ func capacityToScore(capacity uint64, divisor float64) int {
 	}
 }
 
-func scoreNodes(pod *corev1.Pod, nodes []corev1.Node, defaultDivisor float64, divisors map[string]float64) []HostPriority {
+// Scoring strategies
+const (
+	// StrategySpread scores nodes with more free capacity higher to spread volumes.
+	StrategySpread = "spread"
+	// StrategyBinpack scores nodes with less free capacity higher to fill nodes densely.
+	StrategyBinpack = "binpack"
+	// StrategyCustom scores nodes with a piecewise linear function of free capacity.
+	StrategyCustom = "custom"
+)
+
+// ScorePoint is a point of the piecewise linear function for StrategyCustom.
+type ScorePoint struct {
+	// Capacity is the free capacity in GiB divided by the divisor.
+	Capacity float64 `json:"capacity"`
+	// Score is the score of the node at Capacity, from 0 to 10.
+	Score int `json:"score"`
+}
+
+// ScoringStrategy represents how nodes are scored with their free capacity.
+type ScoringStrategy struct {
+	// Type is one of StrategySpread, StrategyBinpack, or StrategyCustom.
+	// Empty means StrategySpread.
+	Type string `json:"type"`
+	// Points defines the function for StrategyCustom.
+	// Scores between points are linearly interpolated, and scores outside of points are
+	// those of the nearest point.
+	Points []ScorePoint `json:"points,omitempty"`
+}
+
+// Validate validates the strategy.
+func (s ScoringStrategy) Validate() error {
+	switch s.Type {
+	case "", StrategySpread, StrategyBinpack:
+		if len(s.Points) != 0 {
+			return fmt.Errorf("points are not allowed for %q strategy", s.Type)
+		}
+		return nil
+	case StrategyCustom:
+	default:
+		return fmt.Errorf("unknown scoring strategy: %q", s.Type)
+	}
+
+	if len(s.Points) == 0 {
+		return errors.New("points are required for custom strategy")
+	}
+	for i, p := range s.Points {
+		if p.Score < 0 || p.Score > 10 {
+			return fmt.Errorf("score must be between 0 and 10: %d", p.Score)
+		}
+		if i > 0 && p.Capacity <= s.Points[i-1].Capacity {
+			return fmt.Errorf("capacities of points must be in ascending order: %f", p.Capacity)
+		}
+	}
+	return nil
+}
+
+func (s ScoringStrategy) score(capacity uint64, divisor float64) int {
+	switch s.Type {
+	case StrategyBinpack:
+		return 10 - capacityToScore(capacity, divisor)
+	case StrategyCustom:
+		return pointsToScore(s.Points, float64(capacity>>30)/divisor)
+	default:
+		return capacityToScore(capacity, divisor)
+	}
+}
+
+func pointsToScore(points []ScorePoint, x float64) int {
+	if x <= points[0].Capacity {
+		return points[0].Score
+	}
+	for i := 1; i < len(points); i++ {
+		p0, p1 := points[i-1], points[i]
+		if x > p1.Capacity {
+			continue
+		}
+		ratio := (x - p0.Capacity) / (p1.Capacity - p0.Capacity)
+		return int(math.Round(float64(p0.Score) + ratio*float64(p1.Score-p0.Score)))
+	}
+	return points[len(points)-1].Score
+}
+
+func scoreNodes(pod *corev1.Pod, nodes []corev1.Node, defaultDivisor float64, divisors map[string]float64,
+	defaultStrategy ScoringStrategy, strategies map[string]ScoringStrategy) []HostPriority {
 	var dcs []string
 	for k := range pod.Annotations {
 		if strings.HasPrefix(k, topolvm.CapacityKeyPrefix) {
@@ -49,7 +134,7 @@ func scoreNodes(pod *corev1.Pod, nodes []corev1.Node, defaultDivisor float64, di
 		r := &result[i]
 		item := nodes[i]
 		go func() {
-			score := scoreNode(item, dcs, defaultDivisor, divisors)
+			score := scoreNode(item, dcs, defaultDivisor, divisors, defaultStrategy, strategies)
 			*r = HostPriority{Host: item.Name, Score: score}
 			wg.Done()
 		}()
@@ -59,7 +144,8 @@ func scoreNodes(pod *corev1.Pod, nodes []corev1.Node, defaultDivisor float64, di
 	return result
 }
 
-func scoreNode(item corev1.Node, deviceClasses []string, defaultDivisor float64, divisors map[string]float64) int {
+func scoreNode(item corev1.Node, deviceClasses []string, defaultDivisor float64, divisors map[string]float64,
+	defaultStrategy ScoringStrategy, strategies map[string]ScoringStrategy) int {
 	minScore := math.MaxInt32
 	for _, dc := range deviceClasses {
 		if val, ok := item.Annotations[topolvm.CapacityKeyPrefix+dc]; ok {
@@ -70,7 +156,11 @@ func scoreNode(item corev1.Node, deviceClasses []string, defaultDivisor float64,
 			} else {
 				divisor = defaultDivisor
 			}
-			score := capacityToScore(capacity, divisor)
+			strategy, ok := strategies[dc]
+			if !ok {
+				strategy = defaultStrategy
+			}
+			score := strategy.score(capacity, divisor)
 			if score < minScore {
 				minScore = score
 			}
@@ -92,7 +182,7 @@ func (s scheduler) prioritize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := scoreNodes(input.Pod, input.Nodes.Items, s.defaultDivisor, s.divisors)
+	result := scoreNodes(input.Pod, input.Nodes.Items, s.defaultDivisor, s.divisors, s.defaultStrategy, s.strategies)
 
 	w.Header().Set("content-type", "application/json")
 	json.NewEncoder(w).Encode(result)
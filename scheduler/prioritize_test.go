@@ -35,6 +35,73 @@ func TestCapacityToScore(t *testing.T) {
 	}
 }
 
+func TestScoringStrategy(t *testing.T) {
+	custom := ScoringStrategy{
+		Type: StrategyCustom,
+		Points: []ScorePoint{
+			{Capacity: 10, Score: 10},
+			{Capacity: 100, Score: 0},
+		},
+	}
+
+	testCases := []struct {
+		strategy ScoringStrategy
+		input    uint64
+		divisor  float64
+		expect   int
+	}{
+		{ScoringStrategy{}, 128 << 30, 1, 7},
+		{ScoringStrategy{Type: StrategySpread}, 128 << 30, 1, 7},
+		{ScoringStrategy{Type: StrategyBinpack}, 128 << 30, 1, 3},
+		{ScoringStrategy{Type: StrategyBinpack}, 128 << 30, 2, 4},
+		{ScoringStrategy{Type: StrategyBinpack}, ^uint64(0), 1, 0},
+		{custom, 0, 1, 10},
+		{custom, 10 << 30, 1, 10},
+		{custom, 55 << 30, 1, 5},
+		{custom, 55 << 30, 0.5, 0},
+		{custom, 1000 << 30, 1, 0},
+	}
+
+	for _, tt := range testCases {
+		score := tt.strategy.score(tt.input, tt.divisor)
+		if score != tt.expect {
+			t.Errorf("score incorrect: strategy=%#v input=%d divisor=%f expect=%d actual=%d",
+				tt.strategy,
+				tt.input,
+				tt.divisor,
+				tt.expect,
+				score,
+			)
+		}
+	}
+}
+
+func TestScoringStrategyValidate(t *testing.T) {
+	testCases := []struct {
+		strategy ScoringStrategy
+		valid    bool
+	}{
+		{ScoringStrategy{}, true},
+		{ScoringStrategy{Type: StrategyBinpack}, true},
+		{ScoringStrategy{Type: "unknown"}, false},
+		{ScoringStrategy{Type: StrategySpread, Points: []ScorePoint{{Capacity: 1, Score: 1}}}, false},
+		{ScoringStrategy{Type: StrategyCustom}, false},
+		{ScoringStrategy{Type: StrategyCustom, Points: []ScorePoint{{Capacity: 1, Score: 11}}}, false},
+		{ScoringStrategy{Type: StrategyCustom, Points: []ScorePoint{{Capacity: 2, Score: 1}, {Capacity: 1, Score: 2}}}, false},
+		{ScoringStrategy{Type: StrategyCustom, Points: []ScorePoint{{Capacity: 1, Score: 10}, {Capacity: 2, Score: 0}}}, true},
+	}
+
+	for _, tt := range testCases {
+		err := tt.strategy.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%#v should be valid: %v", tt.strategy, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%#v should be invalid", tt.strategy)
+		}
+	}
+}
+
 func TestScoreNodes(t *testing.T) {
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -81,7 +148,7 @@ func TestScoreNodes(t *testing.T) {
 		"ssd":  4,
 		"hdd1": 10,
 	}
-	result := scoreNodes(pod, input, defaultDivisor, divisors)
+	result := scoreNodes(pod, input, defaultDivisor, divisors, ScoringStrategy{}, nil)
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("expected scoreNodes() to be %#v, but actual %#v", expected, result)
 	}
@@ -3,19 +3,60 @@ package scheduler
 import (
 	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/topolvm/topolvm/reservation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// HandlerOptions represents options of the scheduler extender.
+type HandlerOptions struct {
+	// DefaultDivisor is the default divisor for node scoring.
+	DefaultDivisor float64
+	// Divisors is a mapping between device-class names and their divisors.
+	Divisors map[string]float64
+	// DefaultStrategy is the default scoring strategy.
+	DefaultStrategy ScoringStrategy
+	// Strategies is a mapping between device-class names and their scoring strategies.
+	Strategies map[string]ScoringStrategy
+	// Reservation calculates the capacity to be subtracted from the capacity of nodes.
+	// If nil, no capacity is reserved.
+	Reservation *reservation.Calculator
+	// Reader reads Nodes, PersistentVolumeClaims, and StorageClasses for the preemption verb.
+	// If nil, the preemption verb does not change victims.
+	Reader client.Reader
+	// NodeLister lists Nodes for the explain endpoint when the request does not have nodes.
+	// If nil, the request must have nodes.
+	NodeLister client.Reader
+}
+
 type scheduler struct {
-	defaultDivisor float64
-	divisors       map[string]float64
+	defaultDivisor  float64
+	divisors        map[string]float64
+	defaultStrategy ScoringStrategy
+	strategies      map[string]ScoringStrategy
+	reservation     *reservation.Calculator
+	reader          client.Reader
+	nodeLister      client.Reader
 }
 
 func (s scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.URL.Path {
 	case "/predicate":
+		defer observeDuration("predicate", time.Now())
 		s.predicate(w, r)
 	case "/prioritize":
+		defer observeDuration("prioritize", time.Now())
 		s.prioritize(w, r)
+	case "/preemption":
+		defer observeDuration("preemption", time.Now())
+		s.preemption(w, r)
+	case "/explain":
+		defer observeExplainDuration(time.Now())
+		s.explain(w, r)
+	case "/metrics":
+		promhttp.Handler().ServeHTTP(w, r)
 	case "/status":
 		status(w, r)
 	default:
@@ -23,14 +64,38 @@ func (s scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func observeDuration(verb string, start time.Time) {
+	requestDuration.WithLabelValues(verb).Observe(time.Since(start).Seconds())
+}
+
+func observeExplainDuration(start time.Time) {
+	explainDuration.Observe(time.Since(start).Seconds())
+}
+
 // NewHandler return new http.Handler of the scheduler extender
-func NewHandler(defaultDiv float64, divisors map[string]float64) (http.Handler, error) {
-	for _, divisor := range divisors {
+func NewHandler(opts HandlerOptions) (http.Handler, error) {
+	for _, divisor := range opts.Divisors {
 		if divisor <= 0 {
 			return nil, fmt.Errorf("invalid divisor: %f", divisor)
 		}
 	}
-	return scheduler{defaultDiv, divisors}, nil
+	if err := opts.DefaultStrategy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid default strategy: %w", err)
+	}
+	for dc, strategy := range opts.Strategies {
+		if err := strategy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid strategy for device-class %s: %w", dc, err)
+		}
+	}
+	return scheduler{
+		defaultDivisor:  opts.DefaultDivisor,
+		divisors:        opts.Divisors,
+		defaultStrategy: opts.DefaultStrategy,
+		strategies:      opts.Strategies,
+		reservation:     opts.Reservation,
+		reader:          opts.Reader,
+		nodeLister:      opts.NodeLister,
+	}, nil
 }
 
 func status(w http.ResponseWriter, r *http.Request) {
@@ -13,6 +13,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var extenderArgs = ExtenderArgs{
@@ -45,8 +48,11 @@ var extenderArgs = ExtenderArgs{
 func testPredicate(t *testing.T) {
 	t.Parallel()
 
-	handler, err := NewHandler(1, map[string]float64{
-		"ssd": 1,
+	handler, err := NewHandler(HandlerOptions{
+		DefaultDivisor: 1,
+		Divisors: map[string]float64{
+			"ssd": 1,
+		},
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -92,8 +98,11 @@ func testPredicate(t *testing.T) {
 func testPrioritize(t *testing.T) {
 	t.Parallel()
 
-	handler, err := NewHandler(1, map[string]float64{
-		"ssd": 1,
+	handler, err := NewHandler(HandlerOptions{
+		DefaultDivisor: 1,
+		Divisors: map[string]float64{
+			"ssd": 1,
+		},
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -143,7 +152,170 @@ func testPrioritize(t *testing.T) {
 	}
 }
 
+func testExplain(t *testing.T) {
+	t.Parallel()
+
+	handler, err := NewHandler(HandlerOptions{
+		DefaultDivisor: 1,
+		Divisors: map[string]float64{
+			"ssd": 1,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := json.Marshal(extenderArgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/explain", bytes.NewReader(input))
+	handler.ServeHTTP(w, r)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Error("resp.StatusCode != http.StatusOK:", resp.StatusCode)
+	}
+
+	result := new(ExplainResult)
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result.Requested, map[string]int64{"ssd": 3 << 30}) {
+		t.Errorf("wrong result.Requested: %#v", result.Requested)
+	}
+	if len(result.Nodes) != 2 {
+		t.Fatalf("wrong result.Nodes: %#v", result.Nodes)
+	}
+	n1, n2 := result.Nodes[0], result.Nodes[1]
+	if n1.Name != "10.1.1.1" || n1.Passed || n1.Reason != reasonOutOfSpace || n1.Capacity["ssd"] != 2<<30 {
+		t.Errorf("wrong explanation of 10.1.1.1: %#v", n1)
+	}
+	if n2.Name != "10.1.1.2" || !n2.Passed || n2.Reason != "" || n2.Score != 2 || n2.Capacity["hdd1"] != 10<<30 {
+		t.Errorf("wrong explanation of 10.1.1.2: %#v", n2)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/explain", nil)
+	handler.ServeHTTP(w, r)
+
+	resp = w.Result()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Error("resp.StatusCode != http.StatusBadRequest:", resp.StatusCode)
+	}
+
+	podOnly, err := json.Marshal(ExtenderArgs{Pod: extenderArgs.Pod})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/explain", bytes.NewReader(podOnly))
+	handler.ServeHTTP(w, r)
+
+	resp = w.Result()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Error("resp.StatusCode != http.StatusBadRequest without nodes:", resp.StatusCode)
+	}
+
+	nodes := make([]client.Object, len(extenderArgs.Nodes.Items))
+	for i := range extenderArgs.Nodes.Items {
+		// list in the reverse order to check the result is sorted by name.
+		nodes[len(nodes)-1-i] = extenderArgs.Nodes.Items[i].DeepCopy()
+	}
+	handler, err = NewHandler(HandlerOptions{
+		DefaultDivisor: 1,
+		Divisors: map[string]float64{
+			"ssd": 1,
+		},
+		NodeLister: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(nodes...).Build(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/explain", bytes.NewReader(podOnly))
+	handler.ServeHTTP(w, r)
+
+	resp = w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal("resp.StatusCode != http.StatusOK with the node lister:", resp.StatusCode)
+	}
+	result = new(ExplainResult)
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Nodes) != 2 || result.Nodes[0].Name != "10.1.1.1" || result.Nodes[1].Name != "10.1.1.2" {
+		t.Fatalf("wrong result.Nodes with the node lister: %#v", result.Nodes)
+	}
+	if result.Nodes[0].Passed || !result.Nodes[1].Passed || result.Nodes[1].Score != 2 {
+		t.Errorf("wrong explanations with the node lister: %#v", result.Nodes)
+	}
+}
+
+func testPreemption(t *testing.T) {
+	t.Parallel()
+
+	handler, err := NewHandler(HandlerOptions{
+		DefaultDivisor: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := json.Marshal(ExtenderPreemptionArgs{
+		Pod: extenderArgs.Pod,
+		NodeNameToVictims: map[string]*Victims{
+			"10.1.1.1": {
+				Pods: []*corev1.Pod{{ObjectMeta: metav1.ObjectMeta{UID: "uid1"}}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/preemption", bytes.NewReader(input))
+	handler.ServeHTTP(w, r)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Error("resp.StatusCode != http.StatusOK:", resp.StatusCode)
+	}
+
+	result := new(ExtenderPreemptionResult)
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// victims are returned as they are when the preemption verb is disabled
+	expected := map[string]*MetaVictims{
+		"10.1.1.1": {Pods: []*MetaPod{{UID: "uid1"}}},
+	}
+	if !reflect.DeepEqual(result.NodeNameToMetaVictims, expected) {
+		t.Errorf("wrong result.NodeNameToMetaVictims: %#v", result.NodeNameToMetaVictims)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/preemption", nil)
+	handler.ServeHTTP(w, r)
+
+	resp = w.Result()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Error("resp.StatusCode != http.StatusBadRequest:", resp.StatusCode)
+	}
+}
+
 func TestRoute(t *testing.T) {
 	t.Run("predicate", testPredicate)
 	t.Run("prioritize", testPrioritize)
+	t.Run("explain", testExplain)
+	t.Run("preemption", testPreemption)
 }